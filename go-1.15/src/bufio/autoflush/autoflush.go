@@ -0,0 +1,170 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package autoflush implements a buffered writer that flushes on its
+// own after a period of inactivity or once its data reaches a given age.
+package autoflush
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// Writer is a bufio.Writer that also flushes buffered data on its
+// own, so that small writes are not held indefinitely when traffic is
+// sparse, as in interactive protocols and log writers. Data is flushed
+// once no write has happened for the idle duration, or once the oldest
+// buffered byte has waited for the maxAge duration, whichever comes
+// first; a zero duration disables that bound. The flushes happen on a
+// timer's goroutine, so, unlike a bufio.Writer, a Writer is safe for
+// concurrent use; an error from such a flush is returned by the next
+// method call, as for a bufio.Writer.
+type Writer struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	idle   time.Duration
+	maxAge time.Duration
+	timer  *time.Timer
+	last   time.Time // time of the last write
+	first  time.Time // time the oldest buffered byte was written
+	due    time.Time // time the timer is set to fire, if it is pending
+}
+
+// NewWriter returns a new Writer writing to w whose buffer has at least
+// the given size, with the given idle and maxAge bounds.
+func NewWriter(w io.Writer, size int, idle, maxAge time.Duration) *Writer {
+	return &Writer{
+		w:      bufio.NewWriterSize(w, size),
+		idle:   idle,
+		maxAge: maxAge,
+	}
+}
+
+// Write writes the contents of p into the buffer, as bufio.Writer.Write does.
+func (a *Writer) Write(p []byte) (nn int, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	before := a.w.Buffered()
+	nn, err = a.w.Write(p)
+	a.wrote(before, nn)
+	return nn, err
+}
+
+// WriteString writes a string, as bufio.Writer.WriteString does.
+func (a *Writer) WriteString(s string) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	before := a.w.Buffered()
+	nn, err := a.w.WriteString(s)
+	a.wrote(before, nn)
+	return nn, err
+}
+
+// WriteByte writes a single byte, as bufio.Writer.WriteByte does.
+func (a *Writer) WriteByte(c byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	before := a.w.Buffered()
+	err := a.w.WriteByte(c)
+	if err == nil {
+		a.wrote(before, 1)
+	}
+	return err
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (a *Writer) Flush() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.w.Flush()
+}
+
+// Buffered returns the number of bytes that have been written into the
+// current buffer.
+func (a *Writer) Buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.w.Buffered()
+}
+
+// Stop flushes any buffered data and stops the timer. Later writes are
+// buffered but not flushed automatically until the next call to Flush.
+func (a *Writer) Stop() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.idle, a.maxAge = 0, 0
+	if a.timer != nil {
+		a.timer.Stop()
+		a.due = time.Time{}
+	}
+	return a.w.Flush()
+}
+
+// wrote updates the flush deadline after n bytes were written when
+// before bytes were buffered.
+func (a *Writer) wrote(before, n int) {
+	if a.idle <= 0 && a.maxAge <= 0 {
+		return
+	}
+	buffered := a.w.Buffered()
+	if buffered == 0 {
+		return
+	}
+	now := time.Now()
+	a.last = now
+	if before == 0 || buffered < before+n {
+		// The buffer was empty, or it was flushed during the write
+		// and what remains is new.
+		a.first = now
+	}
+	a.arm(a.deadline())
+}
+
+// deadline returns the time at which the buffered data must be flushed.
+func (a *Writer) deadline() time.Time {
+	var due time.Time
+	if a.idle > 0 {
+		due = a.last.Add(a.idle)
+	}
+	if a.maxAge > 0 {
+		if t := a.first.Add(a.maxAge); due.IsZero() || t.Before(due) {
+			due = t
+		}
+	}
+	return due
+}
+
+// arm sets the timer to fire at due.
+func (a *Writer) arm(due time.Time) {
+	if !a.due.IsZero() && !due.Before(a.due) {
+		// The pending timer fires early enough; it will rearm itself if
+		// it is early.
+		return
+	}
+	a.due = due
+	d := time.Until(due)
+	if a.timer == nil {
+		a.timer = time.AfterFunc(d, a.fire)
+		return
+	}
+	a.timer.Stop()
+	a.timer.Reset(d)
+}
+
+// fire runs on the timer's goroutine.
+func (a *Writer) fire() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.due = time.Time{}
+	if a.w.Buffered() == 0 || a.idle <= 0 && a.maxAge <= 0 {
+		return
+	}
+	if due := a.deadline(); time.Now().Before(due) {
+		a.arm(due)
+		return
+	}
+	a.w.Flush()
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autoflush_test
+
+import (
+	. "bufio/autoflush"
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	err error
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor waits up to a few seconds for the buffer to hold want.
+func (b *lockedBuffer) waitFor(want string) bool {
+	for i := 0; i < 500; i++ {
+		if b.String() == want {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestIdle(t *testing.T) {
+	var out lockedBuffer
+	w := NewWriter(&out, 1024, 20*time.Millisecond, 0)
+	w.WriteString("hello ")
+	w.Write([]byte("world"))
+	w.WriteByte('\n')
+	if got := out.String(); got != "" {
+		t.Fatalf("flushed %q immediately", got)
+	}
+	if !out.waitFor("hello world\n") {
+		t.Fatalf("got %q after idle period, want %q", out.String(), "hello world\n")
+	}
+	if w.Buffered() != 0 {
+		t.Fatalf("Buffered = %d after auto-flush", w.Buffered())
+	}
+	w.WriteString("again")
+	if !out.waitFor("hello world\nagain") {
+		t.Fatalf("second auto-flush: got %q", out.String())
+	}
+}
+
+func TestMaxAge(t *testing.T) {
+	var out lockedBuffer
+	w := NewWriter(&out, 1024, time.Hour, 50*time.Millisecond)
+	start := time.Now()
+	// Keep writing so the writer is never idle; only the age bound
+	// can cause a flush.
+	for out.String() == "" {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("no flush from the age bound")
+		}
+		w.WriteByte('x')
+		time.Sleep(5 * time.Millisecond)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("flushed after %v, before the age bound", d)
+	}
+}
+
+func TestStop(t *testing.T) {
+	var out lockedBuffer
+	w := NewWriter(&out, 1024, 10*time.Millisecond, 0)
+	w.WriteString("a")
+	if err := w.Stop(); err != nil || out.String() != "a" {
+		t.Fatalf("Stop = %v, wrote %q", err, out.String())
+	}
+	w.WriteString("b")
+	time.Sleep(50 * time.Millisecond)
+	if got := out.String(); got != "a" {
+		t.Fatalf("flushed %q after Stop", got)
+	}
+	w.Flush()
+	if got := out.String(); got != "ab" {
+		t.Fatalf("got %q after Flush", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	errWrite := errors.New("write failed")
+	out := &lockedBuffer{err: errWrite}
+	w := NewWriter(out, 1024, time.Millisecond, 0)
+	w.WriteString("a")
+	// The failed auto-flush makes the error sticky.
+	for i := 0; i < 500; i++ {
+		if _, err := w.WriteString(""); err == errWrite {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("error from auto-flush not reported")
+}
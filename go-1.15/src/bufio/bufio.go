@@ -181,6 +181,36 @@ func (b *Reader) Discard(n int) (discarded int, err error) {
 	}
 }
 
+// DiscardUntil skips input up to and including the first occurrence of
+// delim, returning the number of bytes discarded. Unlike ReadSlice, it
+// does not need the skipped bytes to fit in the buffer, so it can be used
+// to resynchronize a parser after a corrupt frame of any length.
+// If DiscardUntil reaches an error before finding delim, it returns the
+// bytes discarded so far and the error (often io.EOF).
+func (b *Reader) DiscardUntil(delim byte) (discarded int, err error) {
+	b.lastByte = -1
+	b.lastRuneSize = -1
+
+	for {
+		if i := bytes.IndexByte(b.buf[b.r:b.w], delim); i >= 0 {
+			b.r += i + 1
+			discarded += i + 1
+			break
+		}
+		discarded += b.w - b.r
+		b.r = b.w
+		if b.err != nil {
+			err = b.readErr()
+			break
+		}
+		b.fill()
+	}
+	if b.r > 0 && discarded > 0 {
+		b.lastByte = int(b.buf[b.r-1])
+	}
+	return
+}
+
 // Read reads data into p.
 // It returns the number of bytes read into p.
 // The bytes are taken from at most one Read on the underlying Reader,
@@ -310,6 +340,19 @@ func (b *Reader) UnreadRune() error {
 // Buffered returns the number of bytes that can be read from the current buffer.
 func (b *Reader) Buffered() int { return b.w - b.r }
 
+// BufferedBytes returns the bytes that can be read from the current
+// buffer, without reading from the underlying reader and without copying.
+// The bytes stop being valid at the next read call. Together with
+// Discard it lets a parser inspect and consume buffered input in place.
+//
+// Like Peek, calling BufferedBytes prevents a UnreadByte or UnreadRune
+// call from succeeding until the next read operation.
+func (b *Reader) BufferedBytes() []byte {
+	b.lastByte = -1
+	b.lastRuneSize = -1
+	return b.buf[b.r:b.w]
+}
+
 // ReadSlice reads until the first occurrence of delim in the input,
 // returning a slice pointing at the bytes in the buffer.
 // The bytes stop being valid at the next read.
@@ -412,6 +455,33 @@ func (b *Reader) ReadLine() (line []byte, isPrefix bool, err error) {
 	return
 }
 
+// ReadFullLine reads a whole line, not including the end-of-line bytes
+// ("\r\n" or "\n"), however long it is. If the line fits in the buffer,
+// the returned slice points at the bytes in the buffer and is only valid
+// until the next read; only longer lines are copied into a newly
+// allocated slice. As with ReadLine, the final line of the input need
+// not end in a newline, and a non-nil line is returned without an error
+// unless an error other than io.EOF interrupts a long line, in which case
+// the part read so far is returned with the error.
+func (b *Reader) ReadFullLine() (line []byte, err error) {
+	line, isPrefix, err := b.ReadLine()
+	if !isPrefix {
+		return line, err
+	}
+	full := append([]byte(nil), line...)
+	for isPrefix {
+		line, isPrefix, err = b.ReadLine()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			break
+		}
+		full = append(full, line...)
+	}
+	return full, err
+}
+
 // collectFragments reads until the first occurrence of delim in the input. It
 // returns (slice of full buffers, remaining bytes before delim, total number
 // of bytes in the combined first two elements, error).
@@ -693,13 +763,28 @@ func (b *Writer) WriteRune(r rune) (size int, err error) {
 // If the count is less than len(s), it also returns an error explaining
 // why the write is short.
 func (b *Writer) WriteString(s string) (int, error) {
+	var sw io.StringWriter
+	tryStringWriter := true
+
 	nn := 0
 	for len(s) > b.Available() && b.err == nil {
-		n := copy(b.buf[b.n:], s)
-		b.n += n
+		var n int
+		if tryStringWriter {
+			// Check at most once whether b.wr is a StringWriter.
+			sw, tryStringWriter = b.wr.(io.StringWriter)
+		}
+		if b.Buffered() == 0 && tryStringWriter {
+			// Large write, empty buffer, and the underlying writer
+			// supports WriteString: write directly from s to avoid
+			// the copy.
+			n, b.err = sw.WriteString(s)
+		} else {
+			n = copy(b.buf[b.n:], s)
+			b.n += n
+			b.Flush()
+		}
 		nn += n
 		s = s[n:]
-		b.Flush()
 	}
 	if b.err != nil {
 		return nn, b.err
@@ -711,19 +796,16 @@ func (b *Writer) WriteString(s string) (int, error) {
 }
 
 // ReadFrom implements io.ReaderFrom. If the underlying writer
-// supports the ReadFrom method, and b has no buffered data yet,
-// this calls the underlying ReadFrom without buffering.
+// supports the ReadFrom method, this calls the underlying ReadFrom
+// without buffering as soon as b has no buffered data: immediately if
+// the buffer is empty, or else once the buffered data, topped up from r,
+// has been flushed. Optimizations such as sendfile and splice in the
+// underlying writer thus still apply to all but the first buffer of r.
 func (b *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 	if b.err != nil {
 		return 0, b.err
 	}
-	if b.Buffered() == 0 {
-		if w, ok := b.wr.(io.ReaderFrom); ok {
-			n, err = w.ReadFrom(r)
-			b.err = err
-			return n, err
-		}
-	}
+	readerFrom, readerFromOK := b.wr.(io.ReaderFrom)
 	var m int
 	for {
 		if b.Available() == 0 {
@@ -731,6 +813,12 @@ func (b *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 				return n, err1
 			}
 		}
+		if readerFromOK && b.Buffered() == 0 {
+			nn, err := readerFrom.ReadFrom(r)
+			b.err = err
+			n += nn
+			return n, err
+		}
 		nr := 0
 		for nr < maxConsecutiveEmptyReads {
 			m, err = r.Read(b.buf[b.n:])
@@ -682,6 +682,48 @@ func TestWriteString(t *testing.T) {
 	}
 }
 
+// stringWriterRecorder records the calls to its methods.
+type stringWriterRecorder struct {
+	calls []string
+	buf   bytes.Buffer
+}
+
+func (w *stringWriterRecorder) Write(p []byte) (int, error) {
+	w.calls = append(w.calls, "Write:"+string(p))
+	return w.buf.Write(p)
+}
+
+func (w *stringWriterRecorder) WriteString(s string) (int, error) {
+	w.calls = append(w.calls, "WriteString:"+s)
+	return w.buf.WriteString(s)
+}
+
+func TestWriteStringDirect(t *testing.T) {
+	w := new(stringWriterRecorder)
+	b := NewWriterSize(w, 8)
+	b.WriteString("0123456789abc")      // large, empty buffer: direct
+	b.WriteString("de")                 // small: buffered
+	b.WriteString("fghijklmnopqrstuvw") // large: top up, flush, then direct
+	b.Flush()
+	want := []string{"WriteString:0123456789abc", "Write:defghijk", "WriteString:lmnopqrstuvw"}
+	if strings.Join(w.calls, " ") != strings.Join(want, " ") {
+		t.Errorf("got calls %q, want %q", w.calls, want)
+	}
+	if got := w.buf.String(); got != "0123456789abcdefghijklmnopqrstuvw" {
+		t.Errorf("got %q", got)
+	}
+
+	// Large strings written to a StringWriter do not allocate.
+	b = NewWriterSize(ioutil.Discard, 8)
+	s := strings.Repeat("x", 100)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.WriteString(s)
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocs per large WriteString, want 0", allocs)
+	}
+}
+
 func TestBufferFull(t *testing.T) {
 	const longString = "And now, hello, world! It is the time for all good men to come to the aid of their party"
 	buf := NewReaderSize(strings.NewReader(longString), minReadBufferSize)
@@ -752,6 +794,34 @@ func TestPeek(t *testing.T) {
 	}
 }
 
+func TestBufferedBytes(t *testing.T) {
+	buf := NewReaderSize(&StringReader{data: []string{"abcd", "efgh"}}, minReadBufferSize)
+	if b := buf.BufferedBytes(); len(b) != 0 {
+		t.Fatalf("BufferedBytes before any read = %q, want empty", b)
+	}
+	c, err := buf.ReadByte()
+	if c != 'a' || err != nil {
+		t.Fatalf("ReadByte = %q, %v", c, err)
+	}
+	b := buf.BufferedBytes()
+	if string(b) != "bcd" {
+		t.Fatalf("BufferedBytes = %q, want %q", b, "bcd")
+	}
+	if err := buf.UnreadByte(); err != ErrInvalidUnreadByte {
+		t.Fatalf("UnreadByte after BufferedBytes = %v, want %v", err, ErrInvalidUnreadByte)
+	}
+	if n, err := buf.Discard(2); n != 2 || err != nil {
+		t.Fatalf("Discard(2) = %d, %v", n, err)
+	}
+	if b := buf.BufferedBytes(); string(b) != "d" {
+		t.Fatalf("BufferedBytes after Discard = %q, want %q", b, "d")
+	}
+	rest, err := ioutil.ReadAll(buf)
+	if string(rest) != "defgh" || err != nil {
+		t.Fatalf("ReadAll = %q, %v", rest, err)
+	}
+}
+
 type dataAndEOFReader string
 
 func (r dataAndEOFReader) Read(p []byte) (int, error) {
@@ -959,6 +1029,93 @@ func testReadLineNewlines(t *testing.T, input string, expect []readLineResult) {
 	}
 }
 
+func TestReadFullLine(t *testing.T) {
+	long := strings.Repeat("0123456789", 5)
+	for _, test := range []struct {
+		input string
+		want  []string
+	}{
+		{"short\n" + long + "\r\n\nlast", []string{"short", long, "", "last"}},
+		{long, []string{long}},
+		{long[:15] + "\r\n" + long[:16] + "\n", []string{long[:15], long[:16]}},
+		{long[:16], []string{long[:16]}},
+		{"a\r", []string{"a\r"}},
+		{"", nil},
+	} {
+		b := NewReaderSize(iotest.HalfReader(strings.NewReader(test.input)), minReadBufferSize)
+		var got []string
+		for {
+			line, err := b.ReadFullLine()
+			if err == io.EOF {
+				if line != nil {
+					t.Errorf("%q: line %q returned with EOF", test.input, line)
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("%q: %v", test.input, err)
+			}
+			got = append(got, string(line))
+		}
+		if len(got) != len(test.want) || strings.Join(got, "|") != strings.Join(test.want, "|") {
+			t.Errorf("%q: got %q, want %q", test.input, got, test.want)
+		}
+	}
+
+	// An error in the middle of a long line is returned with what was read.
+	b := NewReaderSize(iotest.TimeoutReader(strings.NewReader(long)), minReadBufferSize)
+	line, err := b.ReadFullLine()
+	if string(line) != long[:minReadBufferSize] || err != iotest.ErrTimeout {
+		t.Errorf("got %q, %v; want %q, %v", line, err, long[:minReadBufferSize], iotest.ErrTimeout)
+	}
+}
+
+func TestDiscardUntil(t *testing.T) {
+	junk := strings.Repeat("corrupt", 10)
+	for _, test := range []struct {
+		input     string
+		discarded int
+		err       error
+		rest      string
+	}{
+		{"abc\ndef", 4, nil, "def"},
+		{"\n", 1, nil, ""},
+		{junk + "\nframe", len(junk) + 1, nil, "frame"},
+		{junk, len(junk), io.EOF, ""},
+		{"", 0, io.EOF, ""},
+	} {
+		b := NewReaderSize(iotest.HalfReader(strings.NewReader(test.input)), minReadBufferSize)
+		n, err := b.DiscardUntil('\n')
+		if n != test.discarded || err != test.err {
+			t.Errorf("%q: DiscardUntil = %d, %v; want %d, %v", test.input, n, err, test.discarded, test.err)
+		}
+		rest, _ := ioutil.ReadAll(b)
+		if string(rest) != test.rest {
+			t.Errorf("%q: rest = %q, want %q", test.input, rest, test.rest)
+		}
+	}
+
+	// The delimiter can be unread.
+	b := NewReader(strings.NewReader("xx\nyy"))
+	b.DiscardUntil('\n')
+	if err := b.UnreadByte(); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := b.ReadByte(); c != '\n' {
+		t.Errorf("ReadByte after UnreadByte = %q, want '\\n'", c)
+	}
+
+	// A byte read before DiscardUntil cannot be unread after it.
+	b = NewReader(strings.NewReader("xabc"))
+	b.ReadByte()
+	if n, err := b.DiscardUntil('z'); n != 3 || err != io.EOF {
+		t.Fatalf("DiscardUntil = %d, %v; want 3, EOF", n, err)
+	}
+	if err := b.UnreadByte(); err != ErrInvalidUnreadByte {
+		t.Errorf("UnreadByte after DiscardUntil at EOF = %v, want %v", err, ErrInvalidUnreadByte)
+	}
+}
+
 func createTestInput(n int) []byte {
 	input := make([]byte, n)
 	for i := range input {
@@ -1217,6 +1374,52 @@ func TestWriterReadFromWhileFull(t *testing.T) {
 	}
 }
 
+// readFromRecorder is a writer that implements io.ReaderFrom and
+// records how much of its input arrived through each method.
+type readFromRecorder struct {
+	bytes.Buffer
+	written, readFrom int64
+}
+
+func (w *readFromRecorder) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	return w.Buffer.Write(p)
+}
+
+func (w *readFromRecorder) ReadFrom(r io.Reader) (int64, error) {
+	n, err := w.Buffer.ReadFrom(r)
+	w.readFrom += n
+	return n, err
+}
+
+func TestWriterReadFromPassthrough(t *testing.T) {
+	for _, test := range []struct {
+		header                string
+		wantWrite, wantReadFr int64
+	}{
+		{"", 0, 100},                       // empty buffer: delegate at once
+		{"head", 16, 88},                   // top up the buffer, flush it, then delegate
+		{strings.Repeat("h", 16), 16, 100}, // full buffer: flush, then delegate
+	} {
+		w := &readFromRecorder{}
+		b := NewWriterSize(w, 16)
+		b.WriteString(test.header)
+		body := strings.Repeat("b", 100)
+		n, err := b.ReadFrom(onlyReader{strings.NewReader(body)})
+		if n != 100 || err != nil {
+			t.Errorf("header %q: ReadFrom = %d, %v; want 100, nil", test.header, n, err)
+		}
+		b.Flush()
+		if w.written != test.wantWrite || w.readFrom != test.wantReadFr {
+			t.Errorf("header %q: got %d bytes written and %d read from, want %d and %d",
+				test.header, w.written, w.readFrom, test.wantWrite, test.wantReadFr)
+		}
+		if got, want := w.String(), test.header+body; got != want {
+			t.Errorf("header %q: got %q, want %q", test.header, got, want)
+		}
+	}
+}
+
 type emptyThenNonEmptyReader struct {
 	r io.Reader
 	n int
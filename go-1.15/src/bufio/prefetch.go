@@ -0,0 +1,128 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"errors"
+	"io"
+)
+
+// PrefetchReader implements double buffering for an io.Reader object:
+// while the client drains one buffer, a background goroutine reads ahead
+// into the other, so that latency of a slow or remote reader overlaps
+// with the processing of data already read.
+//
+// The goroutine starts with the first Read and exits when the underlying
+// reader returns an error, such as io.EOF, or after Close. A client that
+// stops reading before the end of the input must call Close. Close cannot
+// interrupt a Read already in progress on the underlying reader.
+type PrefetchReader struct {
+	rd    io.Reader
+	size  int
+	cur   []byte // unread part of the current buffer
+	buf   []byte // the current buffer, returned for reuse once drained
+	err   error  // error to return once cur is drained
+	full  chan prefetched
+	empty chan []byte
+	stop  chan struct{}
+}
+
+// prefetched is a buffer read by the background goroutine.
+type prefetched struct {
+	buf []byte
+	err error
+}
+
+var errReadClosed = errors.New("bufio: read from closed PrefetchReader")
+
+// NewPrefetchReaderSize returns a new PrefetchReader whose two buffers
+// each have at least the specified size.
+func NewPrefetchReaderSize(rd io.Reader, size int) *PrefetchReader {
+	if size < minReadBufferSize {
+		size = minReadBufferSize
+	}
+	return &PrefetchReader{rd: rd, size: size}
+}
+
+// start starts the background goroutine.
+func (b *PrefetchReader) start() {
+	b.full = make(chan prefetched, 2)
+	b.empty = make(chan []byte, 2)
+	b.stop = make(chan struct{})
+	b.empty <- make([]byte, b.size)
+	b.empty <- make([]byte, b.size)
+	go b.prefetch()
+}
+
+// prefetch fills buffers until the underlying reader returns an error.
+func (b *PrefetchReader) prefetch() {
+	for {
+		var buf []byte
+		select {
+		case buf = <-b.empty:
+		case <-b.stop:
+			return
+		}
+		n, err := 0, error(nil)
+		for i := maxConsecutiveEmptyReads; i > 0; i-- {
+			n, err = b.rd.Read(buf)
+			if n < 0 {
+				panic(errNegativeRead)
+			}
+			if n > 0 || err != nil {
+				break
+			}
+		}
+		if n == 0 && err == nil {
+			err = io.ErrNoProgress
+		}
+		// There are only two buffers, so this never blocks.
+		b.full <- prefetched{buf[:n], err}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Read reads data into p.
+// It returns the number of bytes read into p.
+// The bytes are taken from at most one buffer read ahead from the
+// underlying Reader, hence n may be less than len(p).
+// At EOF, the count will be zero and err will be io.EOF.
+func (b *PrefetchReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(b.cur) == 0 {
+		if b.err != nil {
+			return 0, b.err
+		}
+		if b.stop == nil {
+			b.start()
+		}
+		if b.buf != nil {
+			b.empty <- b.buf[:cap(b.buf)]
+			b.buf = nil
+		}
+		f := <-b.full
+		b.cur, b.buf, b.err = f.buf, f.buf, f.err
+		if len(b.cur) == 0 {
+			return 0, b.err
+		}
+	}
+	n = copy(p, b.cur)
+	b.cur = b.cur[n:]
+	return n, nil
+}
+
+// Close stops reading ahead; later calls to Read return an error. Close
+// does not close the underlying reader.
+func (b *PrefetchReader) Close() error {
+	if b.stop != nil && b.err != errReadClosed {
+		close(b.stop)
+	}
+	b.cur, b.buf, b.err = nil, nil, errReadClosed
+	return nil
+}
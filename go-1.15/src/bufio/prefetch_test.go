@@ -0,0 +1,73 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestPrefetchReader(t *testing.T) {
+	data := createTestInput(10000)
+	readers := []func(io.Reader) io.Reader{
+		func(r io.Reader) io.Reader { return r },
+		iotest.OneByteReader,
+		iotest.HalfReader,
+		iotest.DataErrReader,
+	}
+	for i, rf := range readers {
+		for _, size := range []int{16, 100, 4096} {
+			b := NewPrefetchReaderSize(rf(bytes.NewReader(data)), size)
+			got, err := ioutil.ReadAll(iotest.OneByteReader(b))
+			if err != nil || !bytes.Equal(got, data) {
+				t.Errorf("reader %d, size %d: got %d bytes, %v", i, size, len(got), err)
+			}
+			if n, err := b.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+				t.Errorf("reader %d, size %d: Read at EOF = %d, %v", i, size, n, err)
+			}
+		}
+	}
+}
+
+// signalingReader signals each call to Read.
+type signalingReader struct {
+	r     io.Reader
+	calls chan int
+	n     int
+}
+
+func (r *signalingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n++
+	r.calls <- r.n
+	return n, err
+}
+
+func TestPrefetchReaderReadsAhead(t *testing.T) {
+	r := &signalingReader{r: iotest.OneByteReader(strings.NewReader("abc")), calls: make(chan int, 10)}
+	b := NewPrefetchReaderSize(r, 16)
+	p := make([]byte, 16)
+	if n, err := b.Read(p); n != 1 || err != nil || p[0] != 'a' {
+		t.Fatalf("Read = %d, %v, %q", n, err, p[:n])
+	}
+	// The second buffer is filled without another call to Read.
+	<-r.calls
+	if c := <-r.calls; c != 2 {
+		t.Fatalf("got read %d, want 2", c)
+	}
+	if n, err := b.Read(p); n != 1 || err != nil || p[0] != 'b' {
+		t.Fatalf("Read = %d, %v, %q", n, err, p[:n])
+	}
+	b.Close()
+	if _, err := b.Read(p); err == nil {
+		t.Fatal("Read after Close succeeded")
+	}
+	b.Close()
+}
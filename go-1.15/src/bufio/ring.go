@@ -0,0 +1,256 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import "io"
+
+// RingReader implements buffering for an io.Reader object using a
+// circular buffer. Unlike Reader, it never moves buffered bytes within
+// its buffer to make room for more input, so parsers that Peek at large
+// windows do not pay for repeated compaction. In exchange, the bytes
+// returned by Peek may wrap around the end of the buffer and so come in
+// two pieces, and there are no methods, like ReadSlice, that must return
+// buffered data as a single slice.
+type RingReader struct {
+	buf []byte
+	rd  io.Reader // reader provided by the client
+	r   int       // index of the first buffered byte
+	n   int       // number of buffered bytes
+	err error
+}
+
+// NewRingReaderSize returns a new RingReader whose buffer has at least
+// the specified size.
+func NewRingReaderSize(rd io.Reader, size int) *RingReader {
+	if size < minReadBufferSize {
+		size = minReadBufferSize
+	}
+	return &RingReader{buf: make([]byte, size), rd: rd}
+}
+
+// Size returns the size of the underlying buffer in bytes.
+func (b *RingReader) Size() int { return len(b.buf) }
+
+// Reset discards any buffered data, resets all state, and switches
+// the buffered reader to read from r.
+func (b *RingReader) Reset(r io.Reader) {
+	*b = RingReader{buf: b.buf, rd: r}
+}
+
+// Buffered returns the number of bytes that can be read from the current buffer.
+func (b *RingReader) Buffered() int { return b.n }
+
+// fill reads a new chunk into the free space following the buffered data.
+func (b *RingReader) fill() {
+	if b.n >= len(b.buf) {
+		panic("bufio: tried to fill full buffer")
+	}
+	// Read into the free space up to the end of the buffer or, if the
+	// buffered data wraps, up to its start.
+	w := b.r + b.n
+	end := len(b.buf)
+	if w >= len(b.buf) {
+		w -= len(b.buf)
+		end = b.r
+	}
+
+	// Read new data: try a limited number of times.
+	for i := maxConsecutiveEmptyReads; i > 0; i-- {
+		n, err := b.rd.Read(b.buf[w:end])
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		b.n += n
+		if err != nil {
+			b.err = err
+			return
+		}
+		if n > 0 {
+			return
+		}
+	}
+	b.err = io.ErrNoProgress
+}
+
+func (b *RingReader) readErr() error {
+	err := b.err
+	b.err = nil
+	return err
+}
+
+// segments returns the first n buffered bytes, which wrap around the end
+// of the buffer if tail is not empty.
+func (b *RingReader) segments(n int) (head, tail []byte) {
+	end := b.r + n
+	if end <= len(b.buf) {
+		return b.buf[b.r:end], nil
+	}
+	return b.buf[b.r:], b.buf[:end-len(b.buf)]
+}
+
+// consume discards the first n buffered bytes.
+func (b *RingReader) consume(n int) {
+	b.n -= n
+	if b.n == 0 {
+		// Start over at the beginning, leaving the most room for a
+		// contiguous read.
+		b.r = 0
+		return
+	}
+	b.r += n
+	if b.r >= len(b.buf) {
+		b.r -= len(b.buf)
+	}
+}
+
+// Peek returns the next n bytes without advancing the reader, in order,
+// as head followed by tail; tail is empty unless the bytes wrap around
+// the end of the buffer. The bytes stop being valid at the next read
+// call. If Peek returns fewer than n bytes, it also returns an error
+// explaining why the read is short. The error is ErrBufferFull if n is
+// larger than b's buffer size.
+func (b *RingReader) Peek(n int) (head, tail []byte, err error) {
+	if n < 0 {
+		return nil, nil, ErrNegativeCount
+	}
+	for b.n < n && b.n < len(b.buf) && b.err == nil {
+		b.fill() // b.n < len(b.buf) => buffer is not full
+	}
+	if n > len(b.buf) {
+		head, tail = b.segments(b.n)
+		return head, tail, ErrBufferFull
+	}
+	// 0 <= n <= len(b.buf)
+	if b.n < n {
+		// not enough data in buffer
+		n = b.n
+		err = b.readErr()
+		if err == nil {
+			err = ErrBufferFull
+		}
+	}
+	head, tail = b.segments(n)
+	return head, tail, err
+}
+
+// Discard skips the next n bytes, returning the number of bytes discarded.
+//
+// If Discard skips fewer than n bytes, it also returns an error.
+// If 0 <= n <= b.Buffered(), Discard is guaranteed to succeed without
+// reading from the underlying io.Reader.
+func (b *RingReader) Discard(n int) (discarded int, err error) {
+	if n < 0 {
+		return 0, ErrNegativeCount
+	}
+	remain := n
+	for remain > 0 {
+		if b.n == 0 {
+			if b.err != nil {
+				return n - remain, b.readErr()
+			}
+			b.fill()
+		}
+		skip := b.n
+		if skip > remain {
+			skip = remain
+		}
+		b.consume(skip)
+		remain -= skip
+	}
+	return n, nil
+}
+
+// Read reads data into p.
+// It returns the number of bytes read into p.
+// The bytes are taken from at most one Read on the underlying Reader,
+// hence n may be less than len(p).
+// At EOF, the count will be zero and err will be io.EOF.
+func (b *RingReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		if b.n > 0 {
+			return 0, nil
+		}
+		return 0, b.readErr()
+	}
+	if b.n == 0 {
+		if b.err != nil {
+			return 0, b.readErr()
+		}
+		if len(p) >= len(b.buf) {
+			// Large read, empty buffer.
+			// Read directly into p to avoid copy.
+			n, b.err = b.rd.Read(p)
+			if n < 0 {
+				panic(errNegativeRead)
+			}
+			return n, b.readErr()
+		}
+		// One read.
+		// Do not use b.fill, which will loop.
+		n, b.err = b.rd.Read(b.buf)
+		if n < 0 {
+			panic(errNegativeRead)
+		}
+		if n == 0 {
+			return 0, b.readErr()
+		}
+		b.n = n
+	}
+
+	// copy as much as we can
+	head, tail := b.segments(b.n)
+	n = copy(p, head)
+	n += copy(p[n:], tail)
+	b.consume(n)
+	return n, nil
+}
+
+// ReadByte reads and returns a single byte.
+// If no byte is available, returns an error.
+func (b *RingReader) ReadByte() (byte, error) {
+	for b.n == 0 {
+		if b.err != nil {
+			return 0, b.readErr()
+		}
+		b.fill() // buffer is empty
+	}
+	c := b.buf[b.r]
+	b.consume(1)
+	return c, nil
+}
+
+// WriteTo implements io.WriterTo.
+// This may make multiple calls to the Read method of the underlying Reader.
+func (b *RingReader) WriteTo(w io.Writer) (n int64, err error) {
+	for {
+		if b.n > 0 {
+			head, tail := b.segments(b.n)
+			for _, seg := range [2][]byte{head, tail} {
+				if len(seg) == 0 {
+					continue
+				}
+				m, err := w.Write(seg)
+				if m < 0 {
+					panic(errNegativeWrite)
+				}
+				b.consume(m)
+				n += int64(m)
+				if err != nil {
+					return n, err
+				}
+				if m < len(seg) {
+					return n, io.ErrShortWrite
+				}
+			}
+		}
+		if b.err != nil {
+			if err := b.readErr(); err != io.EOF {
+				return n, err
+			}
+			return n, nil
+		}
+		b.fill()
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRingReader(t *testing.T) {
+	data := make([]byte, 5000)
+	rand.New(rand.NewSource(1)).Read(data)
+	readers := []func(io.Reader) io.Reader{
+		func(r io.Reader) io.Reader { return r },
+		iotest.OneByteReader,
+		iotest.HalfReader,
+		iotest.DataErrReader,
+	}
+	for _, size := range []int{16, 17, 100, 4096} {
+		for ri, rf := range readers {
+			rng := rand.New(rand.NewSource(int64(size)))
+			b := NewRingReaderSize(rf(bytes.NewReader(data)), size)
+			var got []byte
+			for len(got) < len(data) {
+				switch rng.Intn(4) {
+				case 0:
+					n := rng.Intn(size + 2)
+					head, tail, err := b.Peek(n)
+					peeked := append(append([]byte(nil), head...), tail...)
+					want := data[len(got):]
+					if len(want) > n {
+						want = want[:n]
+					}
+					if len(want) > size {
+						want = want[:size]
+					}
+					if !bytes.Equal(peeked, want) {
+						t.Fatalf("size %d, reader %d: Peek(%d) = %d bytes, want %d", size, ri, n, len(peeked), len(want))
+					}
+					if len(peeked) < n && err == nil {
+						t.Fatalf("size %d, reader %d: short Peek(%d) without error", size, ri, n)
+					}
+				case 1:
+					p := make([]byte, rng.Intn(2*size))
+					n, _ := b.Read(p)
+					got = append(got, p[:n]...)
+				case 2:
+					if c, err := b.ReadByte(); err == nil {
+						got = append(got, c)
+					}
+				case 3:
+					n := rng.Intn(size)
+					if len(data)-len(got) < n {
+						n = len(data) - len(got)
+					}
+					m, err := b.Discard(n)
+					if m != n || err != nil {
+						t.Fatalf("size %d, reader %d: Discard(%d) = %d, %v", size, ri, n, m, err)
+					}
+					got = append(got, data[len(got):len(got)+n]...)
+				}
+				if !bytes.Equal(got, data[:len(got)]) {
+					t.Fatalf("size %d, reader %d: data mismatch at offset %d", size, ri, len(got))
+				}
+			}
+			if _, err := b.ReadByte(); err != io.EOF {
+				t.Fatalf("size %d, reader %d: ReadByte at end = %v, want EOF", size, ri, err)
+			}
+		}
+	}
+}
+
+func TestRingReaderPeekWrap(t *testing.T) {
+	b := NewRingReaderSize(iotest.OneByteReader(strings.NewReader("abcdefghijklmnopqrstuvwxyz")), 16)
+	if head, tail, err := b.Peek(16); string(head) != "abcdefghijklmnop" || len(tail) != 0 || err != nil {
+		t.Fatalf("Peek(16) = %q, %q, %v", head, tail, err)
+	}
+	b.Discard(10)
+	head, tail, err := b.Peek(10)
+	if string(head) != "klmnop" || string(tail) != "qrst" || err != nil {
+		t.Fatalf("Peek(10) = %q, %q, %v", head, tail, err)
+	}
+	if _, _, err := b.Peek(17); err != ErrBufferFull {
+		t.Fatalf("Peek(17) error = %v, want ErrBufferFull", err)
+	}
+	if _, _, err := b.Peek(-1); err != ErrNegativeCount {
+		t.Fatalf("Peek(-1) error = %v, want ErrNegativeCount", err)
+	}
+	var buf bytes.Buffer
+	if n, err := b.WriteTo(&buf); n != 16 || err != nil || buf.String() != "klmnopqrstuvwxyz" {
+		t.Fatalf("WriteTo = %d, %v, %q", n, err, buf.String())
+	}
+	b.Reset(strings.NewReader("again"))
+	if all, err := ioutil.ReadAll(b); string(all) != "again" || err != nil {
+		t.Fatalf("ReadAll after Reset = %q, %v", all, err)
+	}
+}
+
+func benchmarkPeekWindow(b *testing.B, peek func(n int), discard func(n int), reset func(io.Reader)) {
+	const window = 48 << 10
+	data := make([]byte, 1<<20)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		reset(bytes.NewReader(data))
+		for j := 0; j+window <= len(data); j += 1000 {
+			peek(window)
+			discard(1000)
+		}
+	}
+}
+
+func BenchmarkReaderPeekWindow(b *testing.B) {
+	r := NewReaderSize(nil, 64<<10)
+	benchmarkPeekWindow(b, func(n int) { r.Peek(n) }, func(n int) { r.Discard(n) }, r.Reset)
+}
+
+func BenchmarkRingReaderPeekWindow(b *testing.B) {
+	r := NewRingReaderSize(nil, 64<<10)
+	benchmarkPeekWindow(b, func(n int) { r.Peek(n) }, func(n int) { r.Discard(n) }, r.Reset)
+}
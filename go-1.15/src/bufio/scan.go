@@ -35,6 +35,40 @@ type Scanner struct {
 	empties      int       // Count of successive empty tokens.
 	scanCalled   bool      // Scan has been called; buffer is in use.
 	done         bool      // Scan has finished.
+	pos          position  // Position of buf[start] in the input.
+	tokenPos     position  // Position of the last token.
+	ctx          doner     // Context of the ScanContext call in progress, if any.
+	skipTooLong  bool      // Truncate and skip tokens that are too long.
+	skipping     bool      // The rest of a truncated token is being skipped.
+	tokenErr     error     // Error for the last token.
+	abandoned    bool      // A canceled read may still write to buf.
+	borrowed     bool      // The token is borrowed by Token and not yet released.
+	tokenGen     uint64    // Number of calls to Scan, identifying the token.
+	debugTokens  bool      // Overwrite tokens once they must no longer be used.
+}
+
+// doner is the part of context.Context used by ScanContext.
+type doner interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
+// position is a location in the input of a Scanner.
+type position struct {
+	offset int64
+	line   int // 1-based
+	column int // 1-based, in bytes
+}
+
+// move advances p over the input b.
+func (p *position) move(b []byte) {
+	p.offset += int64(len(b))
+	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+		p.line += bytes.Count(b, []byte{'\n'})
+		p.column = len(b) - i
+	} else {
+		p.column += len(b)
+	}
 }
 
 // SplitFunc is the signature of the split function used to tokenize the
@@ -66,6 +100,9 @@ var (
 	ErrNegativeAdvance = errors.New("bufio.Scanner: SplitFunc returns negative advance count")
 	ErrAdvanceTooFar   = errors.New("bufio.Scanner: SplitFunc returns advance count beyond input")
 	ErrBadReadCount    = errors.New("bufio.Scanner: Read returned impossible count")
+	ErrFrameTooLong    = errors.New("bufio.Scanner: frame length exceeds maximum")
+	ErrBadFrameLength  = errors.New("bufio.Scanner: invalid uvarint frame length")
+	ErrUnclosedQuote   = errors.New("bufio.Scanner: unclosed quote")
 )
 
 const (
@@ -85,7 +122,27 @@ func NewScanner(r io.Reader) *Scanner {
 		r:            r,
 		split:        ScanLines,
 		maxTokenSize: MaxScanTokenSize,
+		pos:          position{line: 1, column: 1},
+		tokenPos:     position{line: 1, column: 1},
+	}
+}
+
+// NewScannerSize returns a new Scanner to read from r whose buffer
+// starts with initial bytes and may grow to max bytes, as if Buffer had
+// been called with a buffer of size initial. The maximum token size is
+// the larger of initial and max. If initial is not positive, the buffer
+// is allocated on the first Scan as by NewScanner.
+// The split function defaults to ScanLines.
+func NewScannerSize(r io.Reader, initial, max int) *Scanner {
+	s := NewScanner(r)
+	if initial > 0 {
+		s.buf = make([]byte, initial)
+	}
+	if max < initial {
+		max = initial
 	}
+	s.maxTokenSize = max
+	return s
 }
 
 // Err returns the first non-EOF error that was encountered by the Scanner.
@@ -97,12 +154,69 @@ func (s *Scanner) Err() error {
 }
 
 // Bytes returns the most recent token generated by a call to Scan.
-// The underlying array may point to data that will be overwritten
-// by a subsequent call to Scan. It does no allocation.
+// The slice is valid only until the next call to Scan, which may
+// overwrite the underlying array; to keep the token longer, copy it or
+// borrow it with Token. It does no allocation.
 func (s *Scanner) Bytes() []byte {
 	return s.token
 }
 
+// Token returns the most recent token generated by a call to Scan, as
+// Bytes does, and borrows it: until release is called, the Scanner
+// will not overwrite the token, and Scan and Reset panic. This lets a
+// token be handed to other code, such as another goroutine in a
+// pipeline, without copying it, with the point at which it may no
+// longer be used made explicit. Calling release more than once, or
+// after a later call to Token, panics.
+func (s *Scanner) Token() (b []byte, release func()) {
+	gen := s.tokenGen
+	s.borrowed = true
+	return s.token, func() {
+		if !s.borrowed || gen != s.tokenGen {
+			panic("bufio: token released twice")
+		}
+		s.tokenGen++
+		s.borrowed = false
+		if s.debugTokens {
+			s.poisonToken()
+		}
+	}
+}
+
+// DebugTokens sets whether the Scanner overwrites the bytes of each token
+// held in its buffer as soon as the token must no longer be used: when
+// it is released, if it was borrowed with Token, or else at the next call
+// to Scan. Code that wrongly retains a token then sees garbage rather
+// than plausible data that would be silently overwritten later. It is
+// intended for tests.
+func (s *Scanner) DebugTokens(on bool) {
+	s.debugTokens = on
+}
+
+// poisonToken overwrites the token if it lies in the part of the buffer
+// already consumed.
+func (s *Scanner) poisonToken() {
+	t := s.token
+	i := cap(s.buf) - cap(t)
+	if len(t) == 0 || i < 0 || i+len(t) > s.start || &s.buf[i] != &t[0] {
+		return
+	}
+	for j := range t {
+		t[j] = 0xDE
+	}
+}
+
+// Position reports the location in the input of the first byte of the
+// most recent token generated by a call to Scan: its byte offset, and
+// its line and column, both starting at 1. Lines end at each '\n', so a
+// "\r\n" line ending counts once, and columns count bytes. If the split
+// function returned a token that is not a slice of the data it was
+// given, as ScanRunes does for invalid UTF-8, the location is that of
+// the first byte the split function consumed.
+func (s *Scanner) Position() (offset int64, line, column int) {
+	return s.tokenPos.offset, s.tokenPos.line, s.tokenPos.column
+}
+
 // Text returns the most recent token generated by a call to Scan
 // as a newly allocated string holding its bytes.
 func (s *Scanner) Text() string {
@@ -129,19 +243,35 @@ var ErrFinalToken = errors.New("final token")
 // tokens without advancing the input. This is a common error mode for
 // scanners.
 func (s *Scanner) Scan() bool {
+	if s.borrowed {
+		panic("bufio: Scan called before the token from Token was released")
+	}
+	if s.debugTokens {
+		s.poisonToken()
+	}
+	s.tokenGen++
 	if s.done {
 		return false
 	}
 	s.scanCalled = true
+	s.tokenErr = nil
 	// Loop until we have a token.
 	for {
 		// See if we can get a token with what we already have.
 		// If we've run out of data but have an error, give the split function
 		// a chance to recover any remaining, possibly empty token.
 		if s.end > s.start || s.err != nil {
-			advance, token, err := s.split(s.buf[s.start:s.end], s.err != nil)
+			data, pos := s.buf[s.start:s.end], s.pos
+			advance, token, err := s.split(data, s.err != nil)
 			if err != nil {
 				if err == ErrFinalToken {
+					if s.skipping && tokenOffset(data, token) == 0 {
+						// The final token is the rest of a truncated one.
+						s.token = nil
+						s.done = true
+						return false
+					}
+					s.setTokenPos(pos, data, token)
 					s.token = token
 					s.done = true
 					return true
@@ -153,7 +283,18 @@ func (s *Scanner) Scan() bool {
 				return false
 			}
 			s.token = token
+			if token != nil && s.skipping {
+				s.skipping = false
+				if tokenOffset(data, token) == 0 {
+					// This is the rest of a truncated token; drop it.
+					// A token that starts later in data follows the
+					// truncated one, which ended at the buffer boundary.
+					s.token = nil
+					continue
+				}
+			}
 			if token != nil {
+				s.setTokenPos(pos, data, token)
 				if s.err == nil || advance > 0 {
 					s.empties = 0
 				} else {
@@ -187,6 +328,14 @@ func (s *Scanner) Scan() bool {
 			// Guarantee no overflow in the multiplication below.
 			const maxInt = int(^uint(0) >> 1)
 			if len(s.buf) >= s.maxTokenSize || len(s.buf) > maxInt/2 {
+				if s.skipping {
+					// Drop more of the rest of a truncated token.
+					s.advance(s.end - s.start)
+					continue
+				}
+				if s.skipTooLong {
+					return s.truncate()
+				}
 				s.setErr(ErrTooLong)
 				return false
 			}
@@ -207,7 +356,13 @@ func (s *Scanner) Scan() bool {
 		// a misbehaving Reader. Officially we don't need to do this, but let's
 		// be extra careful: Scanner is for safe, simple jobs.
 		for loop := 0; ; {
-			n, err := s.r.Read(s.buf[s.end:len(s.buf)])
+			n, err := s.read(s.buf[s.end:len(s.buf)])
+			if err == errCanceled {
+				s.setErr(s.ctx.Err())
+				s.abandoned = true
+				s.done = true
+				return false
+			}
 			if n < 0 || len(s.buf)-s.end < n {
 				s.setErr(ErrBadReadCount)
 				break
@@ -230,6 +385,96 @@ func (s *Scanner) Scan() bool {
 	}
 }
 
+// truncate makes the full buffer the current token, marked with
+// ErrTooLong, and arranges for the rest of the token to be skipped.
+func (s *Scanner) truncate() bool {
+	data, pos := s.buf[s.start:s.end], s.pos
+	s.advance(len(data))
+	s.token = data
+	s.tokenPos = pos
+	s.tokenErr = ErrTooLong
+	s.skipping = true
+	return true
+}
+
+// setTokenPos records the position of token, which the split function
+// returned for data at position pos. If the token is not a slice of data,
+// its position is taken to be that of data.
+func (s *Scanner) setTokenPos(pos position, data, token []byte) {
+	s.tokenPos = pos
+	if i := tokenOffset(data, token); i > 0 {
+		s.tokenPos.move(data[:i])
+	}
+}
+
+// tokenOffset returns the offset in data of token, which the split
+// function returned for data, or 0 if the token is not a slice of data.
+func tokenOffset(data, token []byte) int {
+	i := cap(data) - cap(token)
+	if len(token) > 0 && i > 0 && i < len(data) && &data[i] == &token[0] {
+		return i
+	}
+	return 0
+}
+
+// ScanContext is like Scan, but stops waiting for input when ctx, which
+// is typically a context.Context, is done. Scanning then stops for good:
+// ScanContext returns false, Err returns ctx.Err(), and any partial token
+// already read is discarded rather than returned as if at EOF.
+//
+// A read that is interrupted this way keeps running in the background
+// until the underlying reader returns, and the data it reads is lost;
+// to release it, close the reader or set a deadline on it. While ctx can
+// be canceled, each read from the underlying reader is made in a new
+// goroutine.
+func (s *Scanner) ScanContext(ctx interface {
+	Done() <-chan struct{}
+	Err() error
+}) bool {
+	if s.done {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		s.setErr(err)
+		s.done = true
+		return false
+	}
+	if ctx.Done() == nil {
+		// ctx can never be canceled.
+		return s.Scan()
+	}
+	s.ctx = ctx
+	defer func() { s.ctx = nil }()
+	return s.Scan()
+}
+
+// errCanceled is returned by read when the context of ScanContext is done.
+var errCanceled = errors.New("bufio.Scanner: read canceled")
+
+type readResult struct {
+	n   int
+	err error
+}
+
+// read reads from the underlying reader into p, giving up if the context
+// of a ScanContext call is done before the read returns.
+func (s *Scanner) read(p []byte) (int, error) {
+	if s.ctx == nil {
+		return s.r.Read(p)
+	}
+	c := make(chan readResult, 1)
+	go func(r io.Reader) {
+		n, err := r.Read(p)
+		c <- readResult{n, err}
+	}(s.r)
+	select {
+	case res := <-c:
+		return res.n, res.err
+	case <-s.ctx.Done():
+		return 0, errCanceled
+	}
+}
+
 // advance consumes n bytes of the buffer. It reports whether the advance was legal.
 func (s *Scanner) advance(n int) bool {
 	if n < 0 {
@@ -240,6 +485,7 @@ func (s *Scanner) advance(n int) bool {
 		s.setErr(ErrAdvanceTooFar)
 		return false
 	}
+	s.pos.move(s.buf[s.start : s.start+n])
 	s.start += n
 	return true
 }
@@ -268,6 +514,86 @@ func (s *Scanner) Buffer(buf []byte, max int) {
 	s.maxTokenSize = max
 }
 
+// MaxTokenSize returns the maximum size of a token, which is the larger
+// of the maximum set by NewScanner, NewScannerSize, Buffer or
+// SetMaxTokenSize, and the size of the buffer set by Buffer or
+// NewScannerSize. As for Buffer, the actual maximum token size may be
+// smaller, as the buffer may need to include, for instance, a newline.
+func (s *Scanner) MaxTokenSize() int {
+	if len(s.buf) > s.maxTokenSize {
+		return len(s.buf)
+	}
+	return s.maxTokenSize
+}
+
+// SetMaxTokenSize sets the maximum size of buffer that may be allocated
+// during scanning, keeping the initial buffer, if any. It is equivalent
+// to calling Buffer with the current buffer and n, so the maximum token
+// size becomes the larger of n and the size of that buffer.
+//
+// SetMaxTokenSize panics if n is not positive or if it is called after
+// scanning has started.
+func (s *Scanner) SetMaxTokenSize(n int) {
+	if s.scanCalled {
+		panic("SetMaxTokenSize called after Scan")
+	}
+	if n <= 0 {
+		panic("bufio: non-positive max token size")
+	}
+	s.maxTokenSize = n
+}
+
+// SkipTooLong sets whether a token longer than the maximum token size
+// stops the scan with ErrTooLong, the default, or is truncated. With
+// skip set, Scan returns as the token the first bytes of an overlong
+// token, as many as fit in the buffer, and TokenErr reports ErrTooLong
+// for it; the rest of the token, up to and including the delimiter
+// that ends it, is skipped, and scanning continues with the token after
+// it. This suits processing line-oriented input, such as logs, in which
+// the occasional line is too long to be of use.
+//
+// SkipTooLong panics if it is called after scanning has started.
+func (s *Scanner) SkipTooLong(skip bool) {
+	if s.scanCalled {
+		panic("SkipTooLong called after Scan")
+	}
+	s.skipTooLong = skip
+}
+
+// TokenErr returns the error, if any, for the most recent token
+// generated by a call to Scan. It is ErrTooLong if the token was
+// truncated because SkipTooLong was set, and nil otherwise.
+func (s *Scanner) TokenErr() error {
+	return s.tokenErr
+}
+
+// Reset discards the Scanner's tokens, input and errors and switches it
+// to read from r, keeping its buffer, maximum token size, split function
+// and other settings, so that a Scanner may be reused without allocating
+// a new buffer. Split and Buffer may be called again after Reset.
+//
+// If a read was abandoned by ScanContext, the buffer is not reused, as
+// that read may still write to it.
+func (s *Scanner) Reset(r io.Reader) {
+	if s.borrowed {
+		panic("bufio: Reset called before the token from Token was released")
+	}
+	buf := s.buf
+	if s.abandoned {
+		buf = nil
+	}
+	*s = Scanner{
+		r:            r,
+		split:        s.split,
+		maxTokenSize: s.maxTokenSize,
+		buf:          buf,
+		pos:          position{line: 1, column: 1},
+		tokenPos:     position{line: 1, column: 1},
+		skipTooLong:  s.skipTooLong,
+		debugTokens:  s.debugTokens,
+	}
+}
+
 // Split sets the split function for the Scanner.
 // The default split function is ScanLines.
 //
@@ -413,3 +739,185 @@ func ScanWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	// Request more data.
 	return start, nil, nil
 }
+
+// ScanQuotedWords is a split function for a Scanner that returns each
+// space-separated word of text, as ScanWords does, except that text in
+// single or double quotes is part of the word it appears in, spaces and
+// all, and a backslash outside single quotes makes the character after
+// it literal. As in a shell, the quotes and backslashes are removed:
+// the input
+//
+//	cmd "two words" it\'s 'a\b' "say \"hi\"" ""
+//
+// yields the tokens cmd, two words, it's, a\b, say "hi" and an empty
+// token. A quote that is still open at the end of the input is reported
+// as ErrUnclosedQuote.
+func ScanQuotedWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	// Skip leading spaces.
+	start := 0
+	for width := 0; start < len(data); start += width {
+		var r rune
+		r, width = utf8.DecodeRune(data[start:])
+		if !isSpace(r) {
+			break
+		}
+	}
+	// tok holds the unquoted word if it has quotes or backslashes, up to
+	// data[seg:], which is yet to be copied.
+	var tok []byte
+	seg := start
+	var quote rune
+	for width, i := 0, start; i < len(data); i += width {
+		var r rune
+		r, width = utf8.DecodeRune(data[i:])
+		switch {
+		case r == '\\' && quote != '\'':
+			if i+1 == len(data) {
+				if !atEOF {
+					// Request more data.
+					return start, nil, nil
+				}
+				// A trailing backslash is literal.
+				continue
+			}
+			tok = append(append(tok, data[seg:i]...), data[i+1])
+			// Skip the escaped byte, keeping multi-byte characters whole.
+			seg = i + 2
+			width = 2
+		case quote != 0:
+			if r == quote {
+				tok = append(tok, data[seg:i]...)
+				seg = i + width
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			if tok == nil {
+				tok = []byte{}
+			}
+			tok = append(tok, data[seg:i]...)
+			seg = i + width
+			quote = r
+		case isSpace(r):
+			if tok == nil {
+				return i + width, data[start:i], nil
+			}
+			return i + width, append(tok, data[seg:i]...), nil
+		}
+	}
+	if !atEOF {
+		// Request more data.
+		return start, nil, nil
+	}
+	if quote != 0 {
+		return 0, nil, ErrUnclosedQuote
+	}
+	// We have a final, non-terminated word. Return it.
+	if tok != nil {
+		return len(data), append(tok, data[seg:]...), nil
+	}
+	if len(data) > start {
+		return len(data), data[start:], nil
+	}
+	return len(data), nil, nil
+}
+
+// ScanFramesUvarint is a split function for a Scanner that returns each
+// frame of a stream in which every frame is preceded by its length
+// encoded as an unsigned varint, as written by encoding/binary's
+// PutUvarint. The token is the frame without its length prefix. A length
+// that does not fit in an int is reported as ErrBadFrameLength, and a
+// frame cut short by the end of the input as io.ErrUnexpectedEOF. The
+// largest frame that can be scanned is limited by the Scanner's maximum
+// token size.
+func ScanFramesUvarint(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	var x uint64
+	var shift uint
+	for i, b := range data {
+		if i == maxVarintLen64 || i == maxVarintLen64-1 && b > 1 {
+			return 0, nil, ErrBadFrameLength
+		}
+		if b < 0x80 {
+			x |= uint64(b) << shift
+			if x > uint64(maxInt) {
+				return 0, nil, ErrBadFrameLength
+			}
+			return frame(data, i+1, int(x), atEOF)
+		}
+		x |= uint64(b&0x7f) << shift
+		shift += 7
+	}
+	if atEOF && len(data) > 0 {
+		return 0, nil, io.ErrUnexpectedEOF
+	}
+	// Request more data.
+	return 0, nil, nil
+}
+
+// ScanFramesU32 returns a split function for a Scanner that returns each
+// frame of a stream in which every frame is preceded by its length as a
+// 4-byte big-endian unsigned integer. The token is the frame without its
+// length prefix. A length greater than maxFrame is reported as
+// ErrFrameTooLong before any of the frame is read, and a frame cut short
+// by the end of the input as io.ErrUnexpectedEOF. The Scanner's maximum
+// token size must leave room for the largest frame and its prefix.
+func ScanFramesU32(maxFrame uint32) SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if len(data) < 4 {
+			if atEOF && len(data) > 0 {
+				return 0, nil, io.ErrUnexpectedEOF
+			}
+			// Request more data.
+			return 0, nil, nil
+		}
+		n := uint32(data[0])<<24 | uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3])
+		if n > maxFrame || uint64(n) > uint64(maxInt) {
+			return 0, nil, ErrFrameTooLong
+		}
+		return frame(data, 4, int(n), atEOF)
+	}
+}
+
+const (
+	maxInt         = int(^uint(0) >> 1)
+	maxVarintLen64 = 10
+)
+
+// frame returns the frame of length n that follows a length prefix of
+// size prefix at the start of data, if it is all present.
+func frame(data []byte, prefix, n int, atEOF bool) (advance int, token []byte, err error) {
+	if len(data)-prefix < n {
+		if atEOF {
+			return 0, nil, io.ErrUnexpectedEOF
+		}
+		// Request more data.
+		return 0, nil, nil
+	}
+	return prefix + n, data[prefix : prefix+n], nil
+}
+
+// ScanDelimiter returns a split function for a Scanner that returns each
+// piece of text separated by delim, which may be several bytes long, as
+// in "\r\n\r\n". The delimiter is stripped from the token and may be
+// split across reads. The last piece of non-empty data is returned even
+// if it has no delimiter; an empty final piece is not. ScanDelimiter
+// panics if delim is empty.
+func ScanDelimiter(delim []byte) SplitFunc {
+	if len(delim) == 0 {
+		panic("bufio: empty delimiter")
+	}
+	delim = append([]byte(nil), delim...)
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, delim); i >= 0 {
+			return i + len(delim), data[0:i], nil
+		}
+		// If we're at EOF, we have a final, non-terminated piece. Return it.
+		if atEOF {
+			return len(data), data, nil
+		}
+		// Request more data.
+		return 0, nil, nil
+	}
+}
@@ -7,6 +7,7 @@ package bufio_test
 import (
 	. "bufio"
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"strings"
@@ -190,7 +191,7 @@ func TestScanLongLines(t *testing.T) {
 	}
 	s := NewScanner(&slowReader{1, buf})
 	s.Split(ScanLines)
-	s.MaxTokenSize(smallMaxTokenSize)
+	s.SetMaxTokenSize(smallMaxTokenSize)
 	j = 0
 	for lineNum := 0; s.Scan(); lineNum++ {
 		genLine(tmp, lineNum, j, false)
@@ -226,7 +227,7 @@ func TestScanLineTooLong(t *testing.T) {
 	}
 	s := NewScanner(&slowReader{3, buf})
 	s.Split(ScanLines)
-	s.MaxTokenSize(smallMaxTokenSize)
+	s.SetMaxTokenSize(smallMaxTokenSize)
 	j = 0
 	for lineNum := 0; s.Scan(); lineNum++ {
 		genLine(tmp, lineNum, j, false)
@@ -405,7 +406,7 @@ func TestScanWordsExcessiveWhiteSpace(t *testing.T) {
 	const word = "ipsum"
 	s := strings.Repeat(" ", 4*smallMaxTokenSize) + word
 	scanner := NewScanner(strings.NewReader(s))
-	scanner.MaxTokenSize(smallMaxTokenSize)
+	scanner.SetMaxTokenSize(smallMaxTokenSize)
 	scanner.Split(ScanWords)
 	if !scanner.Scan() {
 		t.Fatalf("scan failed: %v", scanner.Err())
@@ -538,6 +539,39 @@ func TestHugeBuffer(t *testing.T) {
 	}
 }
 
+// Test that NewScannerSize sets the buffer and maximum token size.
+func TestNewScannerSize(t *testing.T) {
+	text := strings.Repeat("x", 2*MaxScanTokenSize)
+	s := NewScannerSize(strings.NewReader(text+"\n"), 100, 3*MaxScanTokenSize)
+	if !s.Scan() || s.Text() != text {
+		t.Fatalf("scan failed: %v", s.Err())
+	}
+
+	s = NewScannerSize(strings.NewReader(text+"\n"), 16, 1024)
+	if s.Scan() {
+		t.Fatal("scanned a token longer than the maximum")
+	}
+	if s.Err() != ErrTooLong {
+		t.Fatalf("after scan: got %v, want %v", s.Err(), ErrTooLong)
+	}
+
+	// The maximum is at least the initial size.
+	s = NewScannerSize(strings.NewReader("abcdefgh\n"), 9, 4)
+	if !s.Scan() || s.Text() != "abcdefgh" {
+		t.Fatalf("scan failed: %v", s.Err())
+	}
+
+	// A non-positive initial size allocates lazily.
+	s = NewScannerSize(strings.NewReader("a\nb"), 0, 10)
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if s.Err() != nil || len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("got %q, %v", got, s.Err())
+	}
+}
+
 // negativeEOFReader returns an invalid -1 at the end, as though it
 // were wrapping the read system call.
 type negativeEOFReader int
@@ -594,3 +628,433 @@ func TestLargeReader(t *testing.T) {
 		t.Errorf("scanner.Err: got %v, want %v", got, want)
 	}
 }
+
+func TestScanFramesUvarint(t *testing.T) {
+	frames := []string{"", "a", strings.Repeat("b", 200), strings.Repeat("c", 20000)}
+	var stream []byte
+	for _, f := range frames {
+		for n := uint64(len(f)); ; n >>= 7 {
+			if n < 0x80 {
+				stream = append(stream, byte(n))
+				break
+			}
+			stream = append(stream, byte(n)|0x80)
+		}
+		stream = append(stream, f...)
+	}
+	// Read one byte at a time so prefixes and frames straddle reads.
+	s := NewScanner(&slowReader{1, bytes.NewReader(stream)})
+	s.Split(ScanFramesUvarint)
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if s.Err() != nil {
+		t.Fatal(s.Err())
+	}
+	if len(got) != len(frames) {
+		t.Fatalf("got %d frames, want %d", len(got), len(frames))
+	}
+	for i := range frames {
+		if got[i] != frames[i] {
+			t.Errorf("frame %d: got %d bytes, want %d", i, len(got[i]), len(frames[i]))
+		}
+	}
+
+	for _, test := range []struct {
+		input string
+		err   error
+	}{
+		{"\x05abc", io.ErrUnexpectedEOF},
+		{"\x80", io.ErrUnexpectedEOF},
+		{"\xff\xff\xff\xff\xff\xff\xff\xff\xff\x02", ErrBadFrameLength},
+		{"\xff\xff\xff\xff\xff\xff\xff\xff\xff\x80\x00", ErrBadFrameLength},
+	} {
+		s := NewScanner(strings.NewReader(test.input))
+		s.Split(ScanFramesUvarint)
+		for s.Scan() {
+		}
+		if s.Err() != test.err {
+			t.Errorf("%q: got error %v, want %v", test.input, s.Err(), test.err)
+		}
+	}
+}
+
+func TestScanFramesU32(t *testing.T) {
+	input := "\x00\x00\x00\x03abc\x00\x00\x00\x00\x00\x00\x00\x01d"
+	s := NewScanner(&slowReader{1, strings.NewReader(input)})
+	s.Split(ScanFramesU32(16))
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if s.Err() != nil || len(got) != 3 || got[0] != "abc" || got[1] != "" || got[2] != "d" {
+		t.Fatalf("got %q, %v", got, s.Err())
+	}
+
+	for _, test := range []struct {
+		input string
+		err   error
+	}{
+		{"\x00\x00\x00\x11" + strings.Repeat("x", 17), ErrFrameTooLong},
+		{"\xff\xff\xff\xff", ErrFrameTooLong},
+		{"\x00\x00\x00\x02a", io.ErrUnexpectedEOF},
+		{"\x00\x00", io.ErrUnexpectedEOF},
+	} {
+		s := NewScanner(strings.NewReader(test.input))
+		s.Split(ScanFramesU32(16))
+		for s.Scan() {
+		}
+		if s.Err() != test.err {
+			t.Errorf("%q: got error %v, want %v", test.input, s.Err(), test.err)
+		}
+	}
+}
+
+func TestScanDelimiter(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		delim string
+		want  []string
+	}{
+		{"a\r\n\r\nb\r\nc\r\n\r\n\r\n\r\nd", "\r\n\r\n", []string{"a", "b\r\nc", "", "d"}},
+		{"a\r\n\r\n", "\r\n\r\n", []string{"a"}},
+		{"", "--", nil},
+		{"x---y", "--", []string{"x", "-y"}},
+		{"aXa", "X", []string{"a", "a"}},
+	} {
+		// Read one byte at a time so delimiters straddle reads.
+		s := NewScanner(&slowReader{1, strings.NewReader(test.input)})
+		s.Split(ScanDelimiter([]byte(test.delim)))
+		var got []string
+		for s.Scan() {
+			got = append(got, s.Text())
+		}
+		if s.Err() != nil {
+			t.Errorf("%q: %v", test.input, s.Err())
+		}
+		if strings.Join(got, "|") != strings.Join(test.want, "|") || len(got) != len(test.want) {
+			t.Errorf("%q split on %q: got %q, want %q", test.input, test.delim, got, test.want)
+		}
+	}
+}
+
+func TestScannerPosition(t *testing.T) {
+	type pos struct {
+		token        string
+		offset       int64
+		line, column int
+	}
+	for _, test := range []struct {
+		name  string
+		split SplitFunc
+		input string
+		want  []pos
+	}{
+		{"lines", ScanLines, "ab\r\n\ncd\nef", []pos{
+			{"ab", 0, 1, 1}, {"", 4, 2, 1}, {"cd", 5, 3, 1}, {"ef", 8, 4, 1},
+		}},
+		{"words", ScanWords, "  one two\n\t three", []pos{
+			{"one", 2, 1, 3}, {"two", 6, 1, 7}, {"three", 12, 2, 3},
+		}},
+		{"runes", ScanRunes, "a\n\xffé", []pos{
+			{"a", 0, 1, 1}, {"\n", 1, 1, 2}, {"�", 2, 2, 1}, {"é", 3, 2, 2},
+		}},
+		{"delimiter", ScanDelimiter([]byte("--")), "x--\ny--z", []pos{
+			{"x", 0, 1, 1}, {"\ny", 3, 1, 4}, {"z", 7, 2, 4},
+		}},
+	} {
+		s := NewScanner(&slowReader{1, strings.NewReader(test.input)})
+		s.Split(test.split)
+		var got []pos
+		for s.Scan() {
+			offset, line, column := s.Position()
+			got = append(got, pos{s.Text(), offset, line, column})
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: token %d: got %v, want %v", test.name, i, got[i], test.want[i])
+			}
+		}
+	}
+}
+
+func TestScanContext(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScanner(pr)
+	go pw.Write([]byte("one\ntw"))
+	if !s.ScanContext(ctx) || s.Text() != "one" {
+		t.Fatalf("ScanContext = %q, %v", s.Text(), s.Err())
+	}
+	// The rest of the input is incomplete, so the next scan blocks
+	// until canceled.
+	done := make(chan bool)
+	go func() { done <- s.ScanContext(ctx) }()
+	cancel()
+	if <-done {
+		t.Fatalf("ScanContext after cancel returned token %q", s.Text())
+	}
+	if s.Err() != context.Canceled {
+		t.Fatalf("Err = %v, want %v", s.Err(), context.Canceled)
+	}
+	if s.ScanContext(context.Background()) || s.Scan() {
+		t.Fatal("scanning continued after cancel")
+	}
+}
+
+func TestScanContextBackground(t *testing.T) {
+	s := NewScanner(strings.NewReader("a\nb\n"))
+	var got []string
+	for s.ScanContext(context.Background()) {
+		got = append(got, s.Text())
+	}
+	if s.Err() != nil || len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("got %q, %v", got, s.Err())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s = NewScanner(strings.NewReader("a\nb\n"))
+	if !s.ScanContext(ctx) || s.Text() != "a" {
+		t.Fatalf("ScanContext = %q, %v", s.Text(), s.Err())
+	}
+	cancel()
+	if s.ScanContext(ctx) || s.Err() != context.Canceled {
+		t.Fatalf("ScanContext with canceled context: %q, %v", s.Text(), s.Err())
+	}
+}
+
+func TestSkipTooLong(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	input := "short\n" + long + "\nafter\n" + long + long + "\n\nlast " + long
+	for _, split := range []struct {
+		name string
+		fn   SplitFunc
+		want []string
+	}{
+		{"lines", ScanLines, []string{"short", "!xxxxxxxxxxxxxxxx", "after", "!xxxxxxxxxxxxxxxx", "", "!last xxxxxxxxxxx"}},
+		{"words", ScanWords, []string{"short", "!xxxxxxxxxxxxxxxx", "after", "!xxxxxxxxxxxxxxxx", "last", "!xxxxxxxxxxxxxxxx"}},
+	} {
+		s := NewScanner(&slowReader{7, strings.NewReader(input)})
+		s.Split(split.fn)
+		s.Buffer(nil, 16)
+		s.SkipTooLong(true)
+		var got []string
+		for s.Scan() {
+			tok := s.Text()
+			if s.TokenErr() == ErrTooLong {
+				tok = "!" + tok
+			} else if s.TokenErr() != nil {
+				t.Errorf("%s: unexpected TokenErr %v", split.name, s.TokenErr())
+			}
+			got = append(got, tok)
+		}
+		if s.Err() != nil {
+			t.Errorf("%s: Err = %v", split.name, s.Err())
+		}
+		if strings.Join(got, ",") != strings.Join(split.want, ",") {
+			t.Errorf("%s: got %q, want %q", split.name, got, split.want)
+		}
+	}
+}
+
+// Test that the token after one truncated exactly at the buffer
+// boundary is not dropped as the rest of the truncated token.
+func TestSkipTooLongBoundary(t *testing.T) {
+	s := NewScanner(strings.NewReader(strings.Repeat("a", 16) + " next other"))
+	s.Split(ScanWords)
+	s.Buffer(nil, 16)
+	s.SkipTooLong(true)
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	want := []string{strings.Repeat("a", 16), "next", "other"}
+	if s.Err() != nil || strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %q, %v; want %q", got, s.Err(), want)
+	}
+}
+
+func TestScannerReset(t *testing.T) {
+	s := NewScanner(strings.NewReader("a b\nc"))
+	s.Split(ScanWords)
+	s.Buffer(make([]byte, 0, 32), 32)
+	for s.Scan() {
+	}
+	s.Reset(strings.NewReader(strings.Repeat("x", 40) + " d e"))
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	// The split function and maximum token size are kept.
+	if len(got) != 0 || s.Err() != ErrTooLong {
+		t.Fatalf("after Reset: got %q, %v; want ErrTooLong", got, s.Err())
+	}
+
+	s.Reset(strings.NewReader("\n f  g"))
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	offset, line, column := s.Position()
+	if s.Err() != nil || strings.Join(got, ",") != "f,g" || offset != 5 || line != 2 || column != 5 {
+		t.Fatalf("after second Reset: got %q, %v at %d:%d:%d", got, s.Err(), offset, line, column)
+	}
+
+	// Split may be called again after Reset.
+	s.Reset(strings.NewReader("h i"))
+	s.Split(ScanLines)
+	if !s.Scan() || s.Text() != "h i" {
+		t.Fatalf("after Split: got %q, %v", s.Text(), s.Err())
+	}
+}
+
+func TestScannerResetAllocs(t *testing.T) {
+	r := strings.NewReader("")
+	s := NewScanner(r)
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset("one\ntwo\n")
+		s.Reset(r)
+		for s.Scan() {
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocs per Reset and scan, want 0", allocs)
+	}
+}
+
+func TestScanQuotedWords(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  []string
+		err   error
+	}{
+		{`cmd "two words" it\'s 'a\b' "say \"hi\"" ""`, []string{"cmd", "two words", "it's", `a\b`, `say "hi"`, ""}, nil},
+		{"  plain\twords\n here ", []string{"plain", "words", "here"}, nil},
+		{`a"b c"d 'e''f' x\ y`, []string{"ab cd", "ef", "x y"}, nil},
+		{`"日本 語" \é 'ü'`, []string{"日本 語", "é", "ü"}, nil},
+		{`trailing\`, []string{`trailing\`}, nil},
+		{`ok "open`, []string{"ok"}, ErrUnclosedQuote},
+		{`'single \' open'`, []string{`single \`}, ErrUnclosedQuote},
+		{"", nil, nil},
+	} {
+		// Read one byte at a time so quotes and escapes straddle reads.
+		s := NewScanner(&slowReader{1, strings.NewReader(test.input)})
+		s.Split(ScanQuotedWords)
+		var got []string
+		for s.Scan() {
+			got = append(got, s.Text())
+		}
+		if s.Err() != test.err {
+			t.Errorf("%q: got error %v, want %v", test.input, s.Err(), test.err)
+		}
+		if len(got) != len(test.want) || strings.Join(got, "|") != strings.Join(test.want, "|") {
+			t.Errorf("%q: got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestMaxTokenSize(t *testing.T) {
+	s := NewScanner(strings.NewReader(""))
+	if got := s.MaxTokenSize(); got != MaxScanTokenSize {
+		t.Errorf("default MaxTokenSize = %d, want %d", got, MaxScanTokenSize)
+	}
+	s.SetMaxTokenSize(100)
+	if got := s.MaxTokenSize(); got != 100 {
+		t.Errorf("after SetMaxTokenSize(100), MaxTokenSize = %d", got)
+	}
+	// The buffer set by Buffer is kept, and bounds the maximum from below.
+	s.Buffer(make([]byte, 200), 50)
+	if got := s.MaxTokenSize(); got != 200 {
+		t.Errorf("after Buffer, MaxTokenSize = %d, want 200", got)
+	}
+	s.SetMaxTokenSize(1000)
+	if got := s.MaxTokenSize(); got != 1000 {
+		t.Errorf("after SetMaxTokenSize(1000), MaxTokenSize = %d", got)
+	}
+	if got := NewScannerSize(nil, 10, 20).MaxTokenSize(); got != 20 {
+		t.Errorf("NewScannerSize: MaxTokenSize = %d, want 20", got)
+	}
+
+	s = NewScanner(strings.NewReader(strings.Repeat("x", 300) + "\n"))
+	s.SetMaxTokenSize(64)
+	if s.Scan() || s.Err() != ErrTooLong {
+		t.Errorf("Scan with small maximum: %v", s.Err())
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("SetMaxTokenSize after Scan did not panic")
+		}
+	}()
+	s.SetMaxTokenSize(1000)
+}
+
+func mustPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s did not panic", name)
+		}
+	}()
+	f()
+}
+
+func TestScannerToken(t *testing.T) {
+	s := NewScanner(strings.NewReader("one two three"))
+	s.Split(ScanWords)
+	var got []string
+	for s.Scan() {
+		b, release := s.Token()
+		got = append(got, string(b))
+		release()
+	}
+	if want := "one two three"; strings.Join(got, " ") != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	s = NewScanner(strings.NewReader("a\nb\n"))
+	s.Scan()
+	_, release := s.Token()
+	mustPanic(t, "Scan while borrowed", func() { s.Scan() })
+	mustPanic(t, "Reset while borrowed", func() { s.Reset(strings.NewReader("")) })
+	release()
+	mustPanic(t, "second release", release)
+	if !s.Scan() || s.Text() != "b" {
+		t.Errorf("Scan after release: %q, %v", s.Text(), s.Err())
+	}
+}
+
+func TestScannerDebugTokens(t *testing.T) {
+	s := NewScanner(strings.NewReader("alpha\nbeta\ngamma\n"))
+	s.DebugTokens(true)
+	s.Scan()
+	kept := s.Bytes()
+	s.Scan()
+	if string(kept) == "alpha" {
+		t.Errorf("token retained past Scan still reads %q", kept)
+	}
+	if s.Text() != "beta" {
+		t.Errorf("second token = %q, want beta", s.Text())
+	}
+	b, release := s.Token()
+	release()
+	if string(b) == "beta" {
+		t.Errorf("token retained past release still reads %q", b)
+	}
+	if !s.Scan() || s.Text() != "gamma" {
+		t.Errorf("third token = %q, want gamma", s.Text())
+	}
+
+	// The setting survives Reset.
+	s.Reset(strings.NewReader("x\ny\n"))
+	s.Scan()
+	kept = s.Bytes()
+	s.Scan()
+	if string(kept) == "x" {
+		t.Errorf("after Reset, token retained past Scan still reads %q", kept)
+	}
+}
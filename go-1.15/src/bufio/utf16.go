@@ -0,0 +1,142 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ScanUTF16Lines returns a split function for a Scanner that returns
+// each line of UTF-16 text, transcoded to UTF-8 and stripped of any
+// trailing end-of-line marker, as ScanLines does for UTF-8 text. The
+// text is big-endian if bigEndian is set and little-endian otherwise,
+// unless it starts with a byte order mark, which then sets the byte
+// order and is dropped. Invalid UTF-16, such as an unpaired surrogate
+// or an odd final byte, is transcoded to U+FFFD.
+//
+// The returned function remembers whether it has seen the start of the
+// input, so it must be used for only one Scanner and input.
+func ScanUTF16Lines(bigEndian bool) SplitFunc {
+	started := false
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if !started {
+			if len(data) < 2 && !atEOF {
+				// Request more data.
+				return 0, nil, nil
+			}
+			started = true
+			if order, ok := utf16BOM(data); ok {
+				bigEndian = order
+				return 2, nil, nil
+			}
+		}
+		return scanUTF16Line(data, atEOF, bigEndian)
+	}
+}
+
+// ScanLinesBOM returns a split function for a Scanner that returns each
+// line of text, stripped of any trailing end-of-line marker, detecting
+// the encoding of the text from its byte order mark. Text that starts
+// with a UTF-16 byte order mark is scanned as by ScanUTF16Lines and
+// transcoded to UTF-8. Otherwise the text is taken to be UTF-8 and
+// scanned as by ScanLines, after dropping any UTF-8 byte order mark.
+//
+// The returned function remembers whether it has seen the start of the
+// input, so it must be used for only one Scanner and input.
+func ScanLinesBOM() SplitFunc {
+	started := false
+	split := SplitFunc(ScanLines)
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if !started {
+			if len(data) < 3 && !atEOF {
+				// Request more data.
+				return 0, nil, nil
+			}
+			started = true
+			if bigEndian, ok := utf16BOM(data); ok {
+				split = func(data []byte, atEOF bool) (int, []byte, error) {
+					return scanUTF16Line(data, atEOF, bigEndian)
+				}
+				return 2, nil, nil
+			}
+			if len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF {
+				return 3, nil, nil
+			}
+		}
+		return split(data, atEOF)
+	}
+}
+
+// utf16BOM reports whether data starts with a UTF-16 byte order mark and,
+// if so, whether it is big-endian.
+func utf16BOM(data []byte) (bigEndian, ok bool) {
+	if len(data) < 2 {
+		return false, false
+	}
+	switch {
+	case data[0] == 0xFE && data[1] == 0xFF:
+		return true, true
+	case data[0] == 0xFF && data[1] == 0xFE:
+		return false, true
+	}
+	return false, false
+}
+
+// scanUTF16Line is ScanLines for UTF-16 text.
+func scanUTF16Line(data []byte, atEOF, bigEndian bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	for i := 0; i+1 < len(data); i += 2 {
+		if utf16Unit(data[i:], bigEndian) == '\n' {
+			// We have a full newline-terminated line.
+			return i + 2, decodeUTF16Line(data[:i], bigEndian), nil
+		}
+	}
+	// If we're at EOF, we have a final, non-terminated line. Return it.
+	if atEOF {
+		return len(data), decodeUTF16Line(data, bigEndian), nil
+	}
+	// Request more data.
+	return 0, nil, nil
+}
+
+func utf16Unit(b []byte, bigEndian bool) rune {
+	if bigEndian {
+		return rune(b[0])<<8 | rune(b[1])
+	}
+	return rune(b[1])<<8 | rune(b[0])
+}
+
+// decodeUTF16Line transcodes a line of UTF-16 text to UTF-8, dropping a
+// final carriage return.
+func decodeUTF16Line(data []byte, bigEndian bool) []byte {
+	odd := len(data)%2 == 1
+	n := len(data) &^ 1
+	if !odd && n >= 2 && utf16Unit(data[n-2:], bigEndian) == '\r' {
+		n -= 2
+	}
+	line := make([]byte, 0, n+n/2)
+	var tmp [utf8.UTFMax]byte
+	for i := 0; i < n; i += 2 {
+		r := utf16Unit(data[i:], bigEndian)
+		if utf16.IsSurrogate(r) {
+			r2 := utf8.RuneError
+			if i+3 < n {
+				r2 = utf16Unit(data[i+2:], bigEndian)
+			}
+			if r = utf16.DecodeRune(r, r2); r != utf8.RuneError {
+				i += 2
+			}
+		}
+		w := utf8.EncodeRune(tmp[:], r)
+		line = append(line, tmp[:w]...)
+	}
+	if odd {
+		line = append(line, string(utf8.RuneError)...)
+	}
+	return line
+}
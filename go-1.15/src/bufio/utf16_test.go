@@ -0,0 +1,92 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bufio_test
+
+import (
+	. "bufio"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodeUTF16 encodes s as UTF-16 in the given byte order.
+func encodeUTF16(s string, bigEndian bool) string {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return string(b)
+}
+
+func scanAll(t *testing.T, input string, split SplitFunc) []string {
+	t.Helper()
+	// Read one byte at a time so code units straddle reads.
+	s := NewScanner(&slowReader{1, strings.NewReader(input)})
+	s.Split(split)
+	var got []string
+	for s.Scan() {
+		got = append(got, s.Text())
+	}
+	if s.Err() != nil {
+		t.Fatal(s.Err())
+	}
+	return got
+}
+
+func TestScanUTF16Lines(t *testing.T) {
+	const text = "first\r\nsecond 日本\n\n𝄞 last"
+	want := []string{"first", "second 日本", "", "𝄞 last"}
+	for _, test := range []struct {
+		name      string
+		input     string
+		bigEndian bool
+	}{
+		{"LE", encodeUTF16(text, false), false},
+		{"BE", encodeUTF16(text, true), true},
+		{"LE BOM", encodeUTF16("\uFEFF"+text, false), true},
+		{"BE BOM", encodeUTF16("\uFEFF"+text, true), false},
+	} {
+		got := scanAll(t, test.input, ScanUTF16Lines(test.bigEndian))
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s: got %q, want %q", test.name, got, want)
+		}
+	}
+
+	// Invalid UTF-16 becomes U+FFFD.
+	input := encodeUTF16("a", false) + "\x00\xD8" + encodeUTF16("b\n", false) + "c"
+	got := scanAll(t, input, ScanUTF16Lines(false))
+	if want := []string{"a�b", "�"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("invalid input: got %q, want %q", got, want)
+	}
+}
+
+func TestScanLinesBOM(t *testing.T) {
+	const text = "one\r\ntwo\nthree"
+	want := []string{"one", "two", "three"}
+	for _, test := range []struct {
+		name  string
+		input string
+	}{
+		{"UTF-8", text},
+		{"UTF-8 BOM", "\uFEFF" + text},
+		{"UTF-16LE", encodeUTF16("\uFEFF"+text, false)},
+		{"UTF-16BE", encodeUTF16("\uFEFF"+text, true)},
+	} {
+		got := scanAll(t, test.input, ScanLinesBOM())
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s: got %q, want %q", test.name, got, want)
+		}
+	}
+	for _, input := range []string{"", "a", "ab", "\xEF\xBB"} {
+		got := scanAll(t, input, ScanLinesBOM())
+		if strings.Join(got, "") != input {
+			t.Errorf("%q: got %q", input, got)
+		}
+	}
+}
@@ -96,7 +96,14 @@ func TestIntendedInlining(t *testing.T) {
 		},
 		"runtime/internal/sys": {},
 		"runtime/internal/math": {
+			"AddUintptr",
+			"AlignUp",
+			"IsPow2",
+			"LeadingZeros",
+			"Log2",
 			"MulUintptr",
+			"SubUintptr",
+			"TrailingZeros",
 		},
 		"bytes": {
 			"(*Buffer).Bytes",
@@ -0,0 +1,202 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bloom implements Bloom filters, compact sets that answer
+// membership queries with no false negatives and a bounded rate of false
+// positives. They suit pre-checks that avoid a more expensive lookup,
+// such as of a cache or of the messages already logged.
+//
+// A Filter only grows; a CountingFilter uses more memory but also
+// supports removal.
+//
+// The filters hash their data with a fixed function, so an encoded filter
+// can be decoded and queried by another process.
+package bloom
+
+import (
+	"errors"
+	"math"
+)
+
+// A Filter is a Bloom filter.
+// A Filter is not safe for concurrent use by multiple goroutines.
+type Filter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    int    // number of hash functions
+}
+
+// New returns an empty filter of m bits using k hash functions.
+// It panics if m or k is less than 1.
+func New(m uint64, k int) *Filter {
+	if m < 1 || k < 1 {
+		panic("bloom: invalid filter size")
+	}
+	return &Filter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// NewWithEstimate returns an empty filter sized so that, once n items
+// have been added, its false positive rate is about p.
+// It panics if p is not between 0 and 1.
+func NewWithEstimate(n int, p float64) *Filter {
+	m, k := Estimate(n, p)
+	return New(m, k)
+}
+
+// Estimate returns the number of bits m and of hash functions k of a
+// filter whose false positive rate is about p once n items are added.
+// It panics if p is not between 0 and 1.
+func Estimate(n int, p float64) (m uint64, k int) {
+	if !(p > 0 && p < 1) {
+		panic("bloom: false positive rate out of range")
+	}
+	if n < 1 {
+		n = 1
+	}
+	fm := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k = int(math.Round(fm / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return uint64(fm), k
+}
+
+// Cap returns the number of bits of f.
+func (f *Filter) Cap() uint64 { return f.m }
+
+// K returns the number of hash functions of f.
+func (f *Filter) K() int { return f.k }
+
+// Add adds data to f.
+func (f *Filter) Add(data []byte) {
+	h1, h2 := hash(data)
+	for i := 0; i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % f.m
+		f.bits[b/64] |= 1 << (b % 64)
+	}
+}
+
+// Test reports whether data may have been added to f. If Test returns
+// false, data was certainly not added.
+func (f *Filter) Test(data []byte) bool {
+	h1, h2 := hash(data)
+	for i := 0; i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % f.m
+		if f.bits[b/64]&(1<<(b%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset empties f.
+func (f *Filter) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+}
+
+// EstimatedFalsePositiveRate returns the false positive rate of f given
+// the fraction of its bits that are set.
+func (f *Filter) EstimatedFalsePositiveRate() float64 {
+	set := 0
+	for _, w := range f.bits {
+		for ; w != 0; w &= w - 1 {
+			set++
+		}
+	}
+	return math.Pow(float64(set)/float64(f.m), float64(f.k))
+}
+
+const (
+	filterMagic   = "blm\x01"
+	countingMagic = "cbl\x01"
+	headerLen     = 4 + 4 + 8 // magic, k, m
+)
+
+var errEncoding = errors.New("bloom: invalid encoding")
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	b := appendHeader(make([]byte, 0, headerLen+8*len(f.bits)), filterMagic, f.m, f.k)
+	for _, w := range f.bits {
+		b = appendUint64(b, w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	m, k, data, err := readHeader(data, filterMagic)
+	if err != nil {
+		return err
+	}
+	n := m / 64
+	if m%64 != 0 {
+		n++ // not (m+63)/64, which overflows for m near 1<<64
+	}
+	if uint64(len(data)) != 8*n {
+		return errEncoding
+	}
+	bits := make([]uint64, n)
+	for i := range bits {
+		bits[i] = readUint64(data[8*i:])
+	}
+	*f = Filter{bits: bits, m: m, k: k}
+	return nil
+}
+
+// hash returns the two hashes of data from which the positions of data
+// in a filter are derived, following Kirsch and Mitzenmacher.
+func hash(data []byte) (h1, h2 uint64) {
+	// FNV-1a, with its output mixed for better high bits.
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for _, c := range data {
+		h ^= uint64(c)
+		h *= prime64
+	}
+	h1 = mix(h)
+	h2 = mix(h1) | 1
+	return h1, h2
+}
+
+// mix is the finalizer of splitmix64.
+func mix(z uint64) uint64 {
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+func appendHeader(b []byte, magic string, m uint64, k int) []byte {
+	b = append(b, magic...)
+	b = append(b, byte(k), byte(k>>8), byte(k>>16), byte(k>>24))
+	return appendUint64(b, m)
+}
+
+func readHeader(data []byte, magic string) (m uint64, k int, rest []byte, err error) {
+	if len(data) < headerLen || string(data[:4]) != magic {
+		return 0, 0, nil, errEncoding
+	}
+	k = int(uint32(data[4]) | uint32(data[5])<<8 | uint32(data[6])<<16 | uint32(data[7])<<24)
+	m = readUint64(data[8:])
+	if m < 1 || k < 1 {
+		return 0, 0, nil, errEncoding
+	}
+	return m, k, data[headerLen:], nil
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24),
+		byte(v>>32), byte(v>>40), byte(v>>48), byte(v>>56))
+}
+
+func readUint64(b []byte) uint64 {
+	_ = b[7] // bounds check hint to compiler; see golang.org/issue/14808
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
+		uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
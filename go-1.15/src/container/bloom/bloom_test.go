@@ -0,0 +1,165 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+func key(prefix string, i int) []byte {
+	return []byte(prefix + strconv.Itoa(i))
+}
+
+func TestEstimate(t *testing.T) {
+	m, k := Estimate(1000, 0.01)
+	// m = -n ln p / (ln 2)^2, k = m/n ln 2
+	if m != 9586 || k != 7 {
+		t.Errorf("Estimate(1000, 0.01) = %d, %d; want 9586, 7", m, k)
+	}
+	if _, k := Estimate(1, 0.9); k != 1 {
+		t.Errorf("Estimate(1, 0.9) uses %d hash functions; want 1", k)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	const n, p = 10000, 0.01
+	f := NewWithEstimate(n, p)
+	for i := 0; i < n; i++ {
+		f.Add(key("in", i))
+	}
+	for i := 0; i < n; i++ {
+		if !f.Test(key("in", i)) {
+			t.Fatalf("false negative for %q", key("in", i))
+		}
+	}
+	fp := 0
+	for i := 0; i < n; i++ {
+		if f.Test(key("out", i)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / n; rate > 2*p {
+		t.Errorf("false positive rate %.4f; want about %.4f", rate, p)
+	}
+	if rate := f.EstimatedFalsePositiveRate(); rate > 2*p || rate < p/2 {
+		t.Errorf("EstimatedFalsePositiveRate() = %.4f; want about %.4f", rate, p)
+	}
+
+	f.Reset()
+	if f.Test(key("in", 0)) {
+		t.Errorf("Test after Reset = true")
+	}
+}
+
+func TestFilterMarshal(t *testing.T) {
+	f := New(1000, 3)
+	for i := 0; i < 100; i++ {
+		f.Add(key("in", i))
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g Filter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if g.Cap() != f.Cap() || g.K() != f.K() {
+		t.Errorf("decoded filter has size %d, %d; want %d, %d", g.Cap(), g.K(), f.Cap(), f.K())
+	}
+	for i := 0; i < 1000; i++ {
+		if f.Test(key("x", i)) != g.Test(key("x", i)) {
+			t.Fatalf("decoded filter differs for %q", key("x", i))
+		}
+	}
+
+	// A header claiming 1<<64-1 bits, with no data.
+	huge := append([]byte(nil), data[:headerLen]...)
+	for i := 8; i < headerLen; i++ {
+		huge[i] = 0xff
+	}
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append([]byte("cbl\x01"), data[4:]...), huge} {
+		if err := g.UnmarshalBinary(bad); err == nil {
+			t.Errorf("UnmarshalBinary(%q...) succeeded", bad[:min(len(bad), 8)])
+		}
+	}
+}
+
+func TestCountingFilter(t *testing.T) {
+	const n, p = 1000, 0.01
+	f := NewCountingWithEstimate(n, p)
+	for i := 0; i < n; i++ {
+		f.Add(key("in", i))
+	}
+	for i := 0; i < n; i += 2 {
+		f.Remove(key("in", i))
+	}
+	fp := 0
+	for i := 0; i < n; i++ {
+		in := f.Test(key("in", i))
+		if i%2 == 1 && !in {
+			t.Fatalf("false negative for %q", key("in", i))
+		}
+		if i%2 == 0 && in {
+			fp++
+		}
+	}
+	if rate := float64(fp) / (n / 2); rate > 2*p {
+		t.Errorf("false positive rate after removal %.4f; want about %.4f", rate, p)
+	}
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g CountingFilter
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < n; i += 2 {
+		if !g.Test(key("in", i)) {
+			t.Fatalf("decoded filter: false negative for %q", key("in", i))
+		}
+	}
+}
+
+func TestCountingSaturation(t *testing.T) {
+	f := NewCounting(1, 1)
+	for i := 0; i < 300; i++ {
+		f.Add([]byte("a"))
+	}
+	for i := 0; i < 300; i++ {
+		f.Remove([]byte("a"))
+	}
+	if !f.Test([]byte("a")) {
+		t.Errorf("saturated counter was decremented")
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func BenchmarkAdd(b *testing.B) {
+	f := NewWithEstimate(b.N, 0.01)
+	data := []byte("some moderately long key for benchmarking")
+	for i := 0; i < b.N; i++ {
+		data[0] = byte(i)
+		f.Add(data)
+	}
+}
+
+func BenchmarkTest(b *testing.B) {
+	f := NewWithEstimate(1<<20, 0.01)
+	data := []byte("some moderately long key for benchmarking")
+	for i := 0; i < b.N; i++ {
+		data[0] = byte(i)
+		f.Test(data)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bloom
+
+// A CountingFilter is a Bloom filter that supports removal, at the cost of
+// a byte per position instead of a bit. A counter that reaches 255 sticks
+// there, so that removals cannot cause false negatives.
+// A CountingFilter is not safe for concurrent use by multiple goroutines.
+type CountingFilter struct {
+	counts []uint8
+	k      int
+}
+
+// NewCounting returns an empty counting filter of m counters using k
+// hash functions. It panics if m or k is less than 1.
+func NewCounting(m uint64, k int) *CountingFilter {
+	if m < 1 || k < 1 {
+		panic("bloom: invalid filter size")
+	}
+	return &CountingFilter{counts: make([]uint8, m), k: k}
+}
+
+// NewCountingWithEstimate returns an empty counting filter sized so that,
+// once n items have been added, its false positive rate is about p.
+// It panics if p is not between 0 and 1.
+func NewCountingWithEstimate(n int, p float64) *CountingFilter {
+	m, k := Estimate(n, p)
+	return NewCounting(m, k)
+}
+
+// Cap returns the number of counters of f.
+func (f *CountingFilter) Cap() uint64 { return uint64(len(f.counts)) }
+
+// K returns the number of hash functions of f.
+func (f *CountingFilter) K() int { return f.k }
+
+// Add adds data to f.
+func (f *CountingFilter) Add(data []byte) {
+	h1, h2 := hash(data)
+	m := uint64(len(f.counts))
+	for i := 0; i < f.k; i++ {
+		c := &f.counts[(h1+uint64(i)*h2)%m]
+		if *c < 255 {
+			*c++
+		}
+	}
+}
+
+// Remove removes data from f. data must have been added to f, as
+// removing data that was not added may cause false negatives.
+func (f *CountingFilter) Remove(data []byte) {
+	h1, h2 := hash(data)
+	m := uint64(len(f.counts))
+	for i := 0; i < f.k; i++ {
+		c := &f.counts[(h1+uint64(i)*h2)%m]
+		if *c > 0 && *c < 255 {
+			*c--
+		}
+	}
+}
+
+// Test reports whether data may have been added to f. If Test returns
+// false, data was certainly not added.
+func (f *CountingFilter) Test(data []byte) bool {
+	h1, h2 := hash(data)
+	m := uint64(len(f.counts))
+	for i := 0; i < f.k; i++ {
+		if f.counts[(h1+uint64(i)*h2)%m] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset empties f.
+func (f *CountingFilter) Reset() {
+	for i := range f.counts {
+		f.counts[i] = 0
+	}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	b := appendHeader(make([]byte, 0, headerLen+len(f.counts)), countingMagic, uint64(len(f.counts)), f.k)
+	return append(b, f.counts...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (f *CountingFilter) UnmarshalBinary(data []byte) error {
+	m, k, data, err := readHeader(data, countingMagic)
+	if err != nil {
+		return err
+	}
+	if uint64(len(data)) != m {
+		return errEncoding
+	}
+	*f = CountingFilter{counts: append([]uint8(nil), data...), k: k}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bloom_test
+
+import (
+	"container/bloom"
+	"fmt"
+)
+
+func Example() {
+	// Suppress repeated log messages, at the cost of
+	// occasionally dropping a new one.
+	seen := bloom.NewWithEstimate(10000, 0.001)
+	for _, msg := range []string{"disk full", "retrying", "disk full"} {
+		if seen.Test([]byte(msg)) {
+			continue
+		}
+		seen.Add([]byte(msg))
+		fmt.Println(msg)
+	}
+
+	// Output:
+	// disk full
+	// retrying
+}
@@ -0,0 +1,350 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package btree implements an in-memory B-tree, a map that keeps its keys
+// in order. Unlike the built-in map, a B-tree can be iterated in key order
+// and queried for the keys in a range.
+//
+// To iterate over the keys in [from, to) of a tree t:
+//	t.AscendRange(from, to, func(key, value interface{}) bool {
+//		// do something with key and value
+//		return true
+//	})
+//
+package btree
+
+// degree is the minimum degree of the tree: every node but the root holds
+// between degree-1 and 2*degree-1 items, and an internal node has one
+// more child than it has items.
+const degree = 16
+
+const maxItems = 2*degree - 1
+
+// A BTree is an ordered map from keys to values.
+// A BTree must be created with New.
+// It is not safe for concurrent use by multiple goroutines.
+type BTree struct {
+	less   func(a, b interface{}) bool
+	root   *node
+	length int
+}
+
+type item struct {
+	key, value interface{}
+}
+
+type node struct {
+	items    []item
+	children []*node // nil for a leaf
+}
+
+// New returns an empty B-tree whose keys are ordered by less, which
+// reports whether key a sorts before key b. less must define a strict
+// weak ordering; keys for which neither sorts before the other are the
+// same key.
+func New(less func(a, b interface{}) bool) *BTree {
+	return &BTree{less: less}
+}
+
+// Len returns the number of keys in t.
+// The complexity is O(1).
+func (t *BTree) Len() int { return t.length }
+
+// Get returns the value of key in t and whether key is present.
+// The complexity is O(log n) where n = t.Len().
+func (t *BTree) Get(key interface{}) (value interface{}, ok bool) {
+	n := t.root
+	for n != nil {
+		i, found := t.find(n, key)
+		if found {
+			return n.items[i].value, true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return nil, false
+}
+
+// Put sets the value of key in t to value. If key was already present,
+// Put returns its previous value and true.
+// The complexity is O(log n) where n = t.Len().
+func (t *BTree) Put(key, value interface{}) (old interface{}, replaced bool) {
+	if t.root == nil {
+		t.root = &node{items: make([]item, 0, maxItems)}
+	}
+	if len(t.root.items) == maxItems {
+		t.root = &node{children: []*node{t.root}}
+		t.root.splitChild(0)
+	}
+	old, replaced = t.insert(t.root, key, value)
+	if !replaced {
+		t.length++
+	}
+	return old, replaced
+}
+
+// Delete removes key from t. If key was present, Delete returns its value
+// and true.
+// The complexity is O(log n) where n = t.Len().
+func (t *BTree) Delete(key interface{}) (value interface{}, ok bool) {
+	if t.root == nil {
+		return nil, false
+	}
+	value, ok = t.delete(t.root, key)
+	if len(t.root.items) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+	if ok {
+		t.length--
+	}
+	return value, ok
+}
+
+// Ascend calls f for each key and value of t in ascending key order,
+// until f returns false. t must not be modified during the iteration.
+func (t *BTree) Ascend(f func(key, value interface{}) bool) {
+	if t.root != nil {
+		t.ascend(t.root, nil, false, nil, false, f)
+	}
+}
+
+// AscendRange calls f for each key and value of t with from <= key < to
+// in ascending key order, until f returns false. t must not be modified
+// during the iteration.
+func (t *BTree) AscendRange(from, to interface{}, f func(key, value interface{}) bool) {
+	if t.root != nil {
+		t.ascend(t.root, from, true, to, true, f)
+	}
+}
+
+// ascend calls f for the items of the subtree n within the bounds that
+// are set, and reports whether the iteration should continue.
+func (t *BTree) ascend(n *node, from interface{}, hasFrom bool, to interface{}, hasTo bool, f func(key, value interface{}) bool) bool {
+	i := 0
+	if hasFrom {
+		i, _ = t.find(n, from)
+	}
+	for ; i < len(n.items); i++ {
+		if !n.leaf() && !t.ascend(n.children[i], from, hasFrom, to, hasTo, f) {
+			return false
+		}
+		it := &n.items[i]
+		if hasTo && !t.less(it.key, to) {
+			return false
+		}
+		if !f(it.key, it.value) {
+			return false
+		}
+	}
+	if !n.leaf() {
+		return t.ascend(n.children[len(n.items)], from, hasFrom, to, hasTo, f)
+	}
+	return true
+}
+
+// find returns the index of the first item of n whose key does not sort
+// before key, and whether that item's key is key.
+func (t *BTree) find(n *node, key interface{}) (int, bool) {
+	// Binary search, as in sort.Search.
+	i, j := 0, len(n.items)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if t.less(n.items[h].key, key) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i, i < len(n.items) && !t.less(key, n.items[i].key)
+}
+
+// insert inserts key into the subtree n, which is not full.
+func (t *BTree) insert(n *node, key, value interface{}) (old interface{}, replaced bool) {
+	for {
+		i, found := t.find(n, key)
+		if found {
+			old = n.items[i].value
+			n.items[i].value = value
+			return old, true
+		}
+		if n.leaf() {
+			n.insertItem(i, item{key, value})
+			return nil, false
+		}
+		if len(n.children[i].items) == maxItems {
+			n.splitChild(i)
+			// The median of the child moved up to n.items[i].
+			switch {
+			case t.less(n.items[i].key, key):
+				i++
+			case !t.less(key, n.items[i].key):
+				old = n.items[i].value
+				n.items[i].value = value
+				return old, true
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// delete removes key from the subtree n, which holds at least degree
+// items unless it is the root.
+func (t *BTree) delete(n *node, key interface{}) (value interface{}, ok bool) {
+	for {
+		i, found := t.find(n, key)
+		if n.leaf() {
+			if !found {
+				return nil, false
+			}
+			value = n.items[i].value
+			n.removeItem(i)
+			return value, true
+		}
+		if !found {
+			n = n.children[n.grow(i)]
+			continue
+		}
+		value = n.items[i].value
+		switch {
+		case len(n.children[i].items) >= degree:
+			n.items[i] = n.children[i].removeMax()
+		case len(n.children[i+1].items) >= degree:
+			n.items[i] = n.children[i+1].removeMin()
+		default:
+			// Both neighbors are minimal: merge them around the
+			// item and delete it from the merged child.
+			n.merge(i)
+			t.delete(n.children[i], key)
+		}
+		return value, true
+	}
+}
+
+func (n *node) leaf() bool { return n.children == nil }
+
+// removeMax removes and returns the last item of the subtree n, which
+// holds at least degree items.
+func (n *node) removeMax() item {
+	for !n.leaf() {
+		n = n.children[n.grow(len(n.children)-1)]
+	}
+	it := n.items[len(n.items)-1]
+	n.removeItem(len(n.items) - 1)
+	return it
+}
+
+// removeMin removes and returns the first item of the subtree n, which
+// holds at least degree items.
+func (n *node) removeMin() item {
+	for !n.leaf() {
+		n = n.children[n.grow(0)]
+	}
+	it := n.items[0]
+	n.removeItem(0)
+	return it
+}
+
+// splitChild splits the full child i of n in two around its median,
+// which moves up into n.
+func (n *node) splitChild(i int) {
+	c := n.children[i]
+	median := c.items[degree-1]
+	right := &node{items: make([]item, degree-1, maxItems)}
+	copy(right.items, c.items[degree:])
+	for j := degree - 1; j < len(c.items); j++ {
+		c.items[j] = item{}
+	}
+	c.items = c.items[:degree-1]
+	if !c.leaf() {
+		right.children = make([]*node, degree, maxItems+1)
+		copy(right.children, c.children[degree:])
+		for j := degree; j < len(c.children); j++ {
+			c.children[j] = nil
+		}
+		c.children = c.children[:degree]
+	}
+	n.insertItem(i, median)
+	n.insertChild(i+1, right)
+}
+
+// grow makes sure that child i of n holds at least degree items, by
+// moving an item from a sibling or by merging it with a sibling, and
+// returns the index of the child now covering the keys of child i.
+func (n *node) grow(i int) int {
+	c := n.children[i]
+	if len(c.items) >= degree {
+		return i
+	}
+	if i > 0 && len(n.children[i-1].items) >= degree {
+		// Rotate an item from the left sibling through n.
+		left := n.children[i-1]
+		c.insertItem(0, n.items[i-1])
+		n.items[i-1] = left.items[len(left.items)-1]
+		left.removeItem(len(left.items) - 1)
+		if !left.leaf() {
+			c.insertChild(0, left.children[len(left.children)-1])
+			left.removeChild(len(left.children) - 1)
+		}
+		return i
+	}
+	if i < len(n.items) && len(n.children[i+1].items) >= degree {
+		// Rotate an item from the right sibling through n.
+		right := n.children[i+1]
+		c.items = append(c.items, n.items[i])
+		n.items[i] = right.items[0]
+		right.removeItem(0)
+		if !right.leaf() {
+			c.children = append(c.children, right.children[0])
+			right.removeChild(0)
+		}
+		return i
+	}
+	if i > 0 {
+		i--
+	}
+	n.merge(i)
+	return i
+}
+
+// merge merges child i+1 of n and the item between them into child i.
+func (n *node) merge(i int) {
+	left, right := n.children[i], n.children[i+1]
+	left.items = append(left.items, n.items[i])
+	left.items = append(left.items, right.items...)
+	if !left.leaf() {
+		left.children = append(left.children, right.children...)
+	}
+	n.removeItem(i)
+	n.removeChild(i + 1)
+}
+
+func (n *node) insertItem(i int, it item) {
+	n.items = append(n.items, item{})
+	copy(n.items[i+1:], n.items[i:])
+	n.items[i] = it
+}
+
+func (n *node) removeItem(i int) {
+	copy(n.items[i:], n.items[i+1:])
+	n.items[len(n.items)-1] = item{} // allow GC of the key and value
+	n.items = n.items[:len(n.items)-1]
+}
+
+func (n *node) insertChild(i int, c *node) {
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+func (n *node) removeChild(i int) {
+	copy(n.children[i:], n.children[i+1:])
+	n.children[len(n.children)-1] = nil
+	n.children = n.children[:len(n.children)-1]
+}
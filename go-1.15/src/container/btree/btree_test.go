@@ -0,0 +1,188 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool { return a.(int) < b.(int) }
+
+// checkTree checks the invariants of t and that it holds exactly the
+// keys of want, with value -key.
+func checkTree(t *testing.T, tr *BTree, want map[int]bool) {
+	t.Helper()
+	if tr.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", tr.Len(), len(want))
+	}
+	if tr.root != nil {
+		if len(tr.root.items) == 0 {
+			t.Fatalf("empty root")
+		}
+		n, _ := checkNode(t, tr.root, true)
+		if n != len(want) {
+			t.Fatalf("tree holds %d items, want %d", n, len(want))
+		}
+	}
+	var keys []int
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	i := 0
+	tr.Ascend(func(key, value interface{}) bool {
+		if i >= len(keys) || key.(int) != keys[i] || value.(int) != -keys[i] {
+			t.Fatalf("Ascend item %d = %v, %v", i, key, value)
+		}
+		i++
+		return true
+	})
+	if i != len(keys) {
+		t.Fatalf("Ascend visited %d items, want %d", i, len(keys))
+	}
+}
+
+// checkNode checks the invariants of the subtree n and returns its
+// number of items and its height.
+func checkNode(t *testing.T, n *node, root bool) (count, height int) {
+	if !root && len(n.items) < degree-1 || len(n.items) > maxItems {
+		t.Fatalf("node with %d items", len(n.items))
+	}
+	for i := 1; i < len(n.items); i++ {
+		if !intLess(n.items[i-1].key, n.items[i].key) {
+			t.Fatalf("unordered items %v, %v", n.items[i-1].key, n.items[i].key)
+		}
+	}
+	count = len(n.items)
+	if n.leaf() {
+		return count, 1
+	}
+	if len(n.children) != len(n.items)+1 {
+		t.Fatalf("node with %d items and %d children", len(n.items), len(n.children))
+	}
+	for i, c := range n.children {
+		if i > 0 && !intLess(n.items[i-1].key, c.items[0].key) {
+			t.Fatalf("child %d starts before item %v", i, n.items[i-1].key)
+		}
+		if i < len(n.items) && !intLess(c.items[len(c.items)-1].key, n.items[i].key) {
+			t.Fatalf("child %d ends after item %v", i, n.items[i].key)
+		}
+		cn, ch := checkNode(t, c, false)
+		if i == 0 {
+			height = ch
+		} else if ch != height {
+			t.Fatalf("children of heights %d and %d", height, ch)
+		}
+		count += cn
+	}
+	return count, height + 1
+}
+
+func TestBTree(t *testing.T) {
+	tr := New(intLess)
+	want := make(map[int]bool)
+	checkTree(t, tr, want)
+	const max = 2000
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(max)
+		if r.Intn(3) == 0 {
+			v, ok := tr.Delete(k)
+			if ok != want[k] || ok && v.(int) != -k {
+				t.Fatalf("Delete(%d) = %v, %v want %v", k, v, ok, want[k])
+			}
+			delete(want, k)
+		} else {
+			old, replaced := tr.Put(k, -k)
+			if replaced != want[k] || replaced && old.(int) != -k {
+				t.Fatalf("Put(%d) = %v, %v want %v", k, old, replaced, want[k])
+			}
+			want[k] = true
+		}
+		if i%500 == 0 {
+			checkTree(t, tr, want)
+		}
+	}
+	checkTree(t, tr, want)
+	for k := 0; k < max; k++ {
+		v, ok := tr.Get(k)
+		if ok != want[k] || ok && v.(int) != -k {
+			t.Fatalf("Get(%d) = %v, %v want %v", k, v, ok, want[k])
+		}
+	}
+	for _, k := range r.Perm(max) {
+		tr.Delete(k)
+		delete(want, k)
+		if len(want)%100 == 0 {
+			checkTree(t, tr, want)
+		}
+	}
+	if tr.root != nil {
+		t.Fatalf("root of empty tree is not nil")
+	}
+}
+
+func TestAscendRange(t *testing.T) {
+	tr := New(intLess)
+	for i := 0; i < 1000; i += 2 {
+		tr.Put(i, -i)
+	}
+	for _, test := range []struct {
+		from, to   int
+		first, end int
+	}{
+		{0, 1000, 0, 1000},
+		{-10, 10, 0, 10},
+		{1, 11, 2, 12},
+		{500, 500, 500, 500},
+		{600, 500, 600, 600},
+		{997, 2000, 998, 1000},
+		{1000, 2000, 1000, 1000},
+	} {
+		next := test.first
+		tr.AscendRange(test.from, test.to, func(key, value interface{}) bool {
+			if key.(int) != next || value.(int) != -next {
+				t.Fatalf("AscendRange(%d, %d) visited %v, %v want %d", test.from, test.to, key, value, next)
+			}
+			next += 2
+			return true
+		})
+		if next != test.end {
+			t.Errorf("AscendRange(%d, %d) stopped before %d, want %d", test.from, test.to, next, test.end)
+		}
+	}
+
+	n := 0
+	tr.AscendRange(100, 900, func(key, value interface{}) bool {
+		n++
+		return key.(int) < 200
+	})
+	if n != 51 {
+		t.Errorf("AscendRange stopped after %d calls, want 51", n)
+	}
+}
+
+func BenchmarkPut(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(b.N)
+	tr := New(intLess)
+	b.ResetTimer()
+	for _, k := range keys {
+		tr.Put(k, k)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	const n = 1 << 16
+	tr := New(intLess)
+	for _, k := range rand.New(rand.NewSource(1)).Perm(n) {
+		tr.Put(k, k)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.Get(i % n)
+	}
+}
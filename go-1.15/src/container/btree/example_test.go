@@ -0,0 +1,29 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package btree_test
+
+import (
+	"container/btree"
+	"fmt"
+)
+
+func Example() {
+	// Create a tree of string keys and insert some entries.
+	t := btree.New(func(a, b interface{}) bool { return a.(string) < b.(string) })
+	t.Put("pear", 3)
+	t.Put("apple", 5)
+	t.Put("fig", 1)
+	t.Put("kiwi", 2)
+
+	// Print the entries with keys from "b" up to "m".
+	t.AscendRange("b", "m", func(key, value interface{}) bool {
+		fmt.Println(key, value)
+		return true
+	})
+
+	// Output:
+	// fig 1
+	// kiwi 2
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package heap_test
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// This example uses a Heap as a priority queue of tasks, raising the
+// priority of one task after it has been queued.
+func ExampleHeap() {
+	type task struct {
+		name     string
+		priority int
+		index    int // maintained through SetIndex
+	}
+	h := &heap.Heap{
+		// Highest priority first.
+		Less:     func(a, b interface{}) bool { return a.(*task).priority > b.(*task).priority },
+		SetIndex: func(x interface{}, i int) { x.(*task).index = i },
+	}
+	backup := &task{name: "backup", priority: 1}
+	for _, t := range []*task{{name: "email", priority: 3}, backup, {name: "deploy", priority: 5}} {
+		h.Push(t)
+	}
+
+	backup.priority = 10
+	h.Fix(backup.index)
+
+	for h.Len() > 0 {
+		t := h.Pop().(*task)
+		fmt.Println(t.priority, t.name)
+	}
+	// Output:
+	// 10 backup
+	// 5 deploy
+	// 3 email
+}
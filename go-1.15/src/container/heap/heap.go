@@ -9,6 +9,8 @@ import "sort"
 //
 //	!h.Less(j, i) for 0 <= i < h.Len() and 2*i+1 <= j <= 2*i+2 and j < h.Len()
 //
+// 不变量成立时，最小元素(根据Less)位于索引0处，可以直接读取底层数据中索引0处(即Peek返回的索引)的元素来查看它而不移除它。
+//
 // 注意，这个接口中的Push和Pop是为了调用包堆的实现。要从堆中添加和删除内容，请使用heap.Push和heap.Pop。
 type Interface interface {
 	sort.Interface // 扩展排序接口
@@ -40,6 +42,16 @@ func Pop(h Interface) interface{} {
 	return h.Pop()
 }
 
+// Peek返回堆中最小元素(根据Less)的索引而不移除它，即0；如果堆为空则返回-1。
+// Interface没有读取元素的方法，调用者用该索引读取自己的底层数据，例如(*h)[heap.Peek(h)]。
+// Peek只调用h.Len，不调用h.Swap、h.Push或h.Pop，因此不修改堆也不分配内存。复杂度为O(1)。
+func Peek(h Interface) int {
+	if h.Len() == 0 {
+		return -1
+	}
+	return 0
+}
+
 // Remove移除并返回堆中索引i处的元素。复杂度是O(log n)其中n = h.Len()
 func Remove(h Interface, i int) interface{} {
 	n := h.Len() - 1
@@ -89,3 +101,107 @@ func down(h Interface, i0, n int) bool {
 	}
 	return i > i0
 }
+
+// Heap是一个按Less排序的最小堆。与Interface不同，使用Heap不需要为每种元素类型定义一个新类型并实现五个方法，
+// 也不会混淆Heap自身的方法与包级函数Push和Pop。Heap的零值在设置Less之后即可使用。
+type Heap struct {
+	// Less报告元素a是否应排在元素b之前。它必须在第一次使用Heap之前设置。
+	Less func(a, b interface{}) bool
+
+	// SetIndex如果不为nil，每当一个元素被放到堆中的某个位置时以该位置调用，在元素被Pop或Remove移除时以-1调用。
+	// 需要对特定元素调用Fix或Remove的调用方可以用它在元素中记录其位置。
+	SetIndex func(x interface{}, i int)
+
+	data []interface{}
+}
+
+// Len返回堆h中的元素数。
+func (h *Heap) Len() int { return len(h.data) }
+
+// Push将元素x添加到堆h上。复杂度为O(log n)，其中n = h.Len()。
+func (h *Heap) Push(x interface{}) {
+	h.data = append(h.data, x)
+	h.setIndex(len(h.data) - 1)
+	h.up(len(h.data) - 1)
+}
+
+// Peek返回堆h中的最小元素(根据Less)而不移除它；如果堆为空则返回nil。复杂度为O(1)。
+func (h *Heap) Peek() interface{} {
+	if len(h.data) == 0 {
+		return nil
+	}
+	return h.data[0]
+}
+
+// Pop从堆h中移除并返回最小元素(根据Less)。堆不能为空。复杂度为O(log n)，其中n = h.Len()。Pop相当于Remove(0)。
+func (h *Heap) Pop() interface{} {
+	return h.Remove(0)
+}
+
+// Remove移除并返回堆h中位置i处的元素。复杂度为O(log n)，其中n = h.Len()。
+func (h *Heap) Remove(i int) interface{} {
+	n := len(h.data) - 1
+	if n != i {
+		h.swap(i, n)
+		if !h.down(i, n) {
+			h.up(i)
+		}
+	}
+	x := h.data[n]
+	h.data[n] = nil // 避免内存泄漏
+	h.data = h.data[:n]
+	if h.SetIndex != nil {
+		h.SetIndex(x, -1)
+	}
+	return x
+}
+
+// Fix在位置i处的元素改变其值后重新建立堆排序。复杂度为O(log n)，其中n = h.Len()。
+func (h *Heap) Fix(i int) {
+	if !h.down(i, len(h.data)) {
+		h.up(i)
+	}
+}
+
+func (h *Heap) setIndex(i int) {
+	if h.SetIndex != nil {
+		h.SetIndex(h.data[i], i)
+	}
+}
+
+func (h *Heap) swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.setIndex(i)
+	h.setIndex(j)
+}
+
+func (h *Heap) up(j int) {
+	for {
+		i := (j - 1) / 2 // parent
+		if i == j || !h.Less(h.data[j], h.data[i]) {
+			break
+		}
+		h.swap(i, j)
+		j = i
+	}
+}
+
+func (h *Heap) down(i0, n int) bool {
+	i := i0
+	for {
+		j1 := 2*i + 1
+		if j1 >= n || j1 < 0 { // j1 < 0 after int overflow
+			break
+		}
+		j := j1 // left child
+		if j2 := j1 + 1; j2 < n && h.Less(h.data[j2], h.data[j1]) {
+			j = j2 // = 2*i + 2  // right child
+		}
+		if !h.Less(h.data[j], h.data[i]) {
+			break
+		}
+		h.swap(i, j)
+		i = j
+	}
+	return i > i0
+}
@@ -212,3 +212,127 @@ func TestFix(t *testing.T) {
 		h.verify(t, 0)
 	}
 }
+
+func intLess(a, b interface{}) bool { return a.(int) < b.(int) }
+
+func (h *Heap) verify(t *testing.T) {
+	t.Helper()
+	for i := 1; i < len(h.data); i++ {
+		if h.Less(h.data[i], h.data[(i-1)/2]) {
+			t.Fatalf("heap invariant invalidated [%d] = %v < [%d] = %v", i, h.data[i], (i-1)/2, h.data[(i-1)/2])
+		}
+	}
+}
+
+func TestHeap(t *testing.T) {
+	h := &Heap{Less: intLess}
+	for i := 20; i > 10; i-- {
+		h.Push(i)
+	}
+	h.verify(t)
+	for i := 10; i > 0; i-- {
+		h.Push(i)
+		h.verify(t)
+	}
+	for i := 1; h.Len() > 0; i++ {
+		x := h.Pop().(int)
+		if x != i {
+			t.Errorf("%d.th pop got %d; want %d", i, x, i)
+		}
+		h.verify(t)
+	}
+}
+
+func TestHeapRemoveFix(t *testing.T) {
+	type item struct {
+		prio, index int
+	}
+	h := &Heap{
+		Less:     func(a, b interface{}) bool { return a.(*item).prio < b.(*item).prio },
+		SetIndex: func(x interface{}, i int) { x.(*item).index = i },
+	}
+	r := rand.New(rand.NewSource(1))
+	var items []*item
+	for i := 0; i < 100; i++ {
+		it := &item{prio: r.Intn(1000)}
+		items = append(items, it)
+		h.Push(it)
+	}
+	check := func() {
+		t.Helper()
+		h.verify(t)
+		for i, x := range h.data {
+			if x.(*item).index != i {
+				t.Fatalf("item at %d records index %d", i, x.(*item).index)
+			}
+		}
+	}
+	check()
+	for i := 0; i < 200; i++ {
+		it := items[r.Intn(len(items))]
+		if it.index < 0 {
+			continue
+		}
+		if i%3 == 0 {
+			if got := h.Remove(it.index); got != it || it.index != -1 {
+				t.Fatalf("Remove returned %v, index %d", got, it.index)
+			}
+		} else {
+			it.prio = r.Intn(1000)
+			h.Fix(it.index)
+		}
+		check()
+	}
+	last := -1
+	for h.Len() > 0 {
+		it := h.Pop().(*item)
+		if it.prio < last || it.index != -1 {
+			t.Fatalf("Pop returned priority %d after %d, index %d", it.prio, last, it.index)
+		}
+		last = it.prio
+	}
+}
+
+func BenchmarkHeapPushPop(b *testing.B) {
+	h := &Heap{Less: intLess}
+	for i := 0; i < 1000; i++ {
+		h.Push(i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Push(h.Pop())
+	}
+}
+
+func TestPeek(t *testing.T) {
+	h := new(myHeap)
+	if i := Peek(h); i != -1 {
+		t.Errorf("Peek of empty heap = %d, want -1", i)
+	}
+	for _, v := range []int{5, 3, 8, 1, 9, 2} {
+		Push(h, v)
+	}
+	before := append(myHeap(nil), *h...)
+	if i := Peek(h); i != 0 || (*h)[i] != 1 {
+		t.Errorf("Peek = %d, want 0 indexing 1", i)
+	}
+	if len(*h) != len(before) {
+		t.Fatalf("Peek changed the length from %d to %d", len(before), len(*h))
+	}
+	for i := range before {
+		if (*h)[i] != before[i] {
+			t.Fatalf("Peek changed the heap from %v to %v", before, *h)
+		}
+	}
+
+	hh := &Heap{Less: intLess}
+	if x := hh.Peek(); x != nil {
+		t.Errorf("Heap.Peek of empty heap = %v, want nil", x)
+	}
+	for _, v := range []int{5, 3, 8} {
+		hh.Push(v)
+	}
+	if x := hh.Peek(); x != 3 || hh.Len() != 3 {
+		t.Errorf("Heap.Peek = %v with %d elements left, want 3 with 3", x, hh.Len())
+	}
+}
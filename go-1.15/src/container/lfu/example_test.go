@@ -0,0 +1,29 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lfu_test
+
+import (
+	"container/lfu"
+	"fmt"
+)
+
+func Example() {
+	c := lfu.New(2)
+	c.OnEvict = func(key, value interface{}) {
+		fmt.Println("evicted", key)
+	}
+	c.Put("home", "<html>home</html>")
+	c.Put("about", "<html>about</html>")
+	c.Get("home")
+
+	// "about" has been used less often than "home".
+	c.Put("news", "<html>news</html>")
+
+	_, ok := c.Get("home")
+	fmt.Println("home cached:", ok)
+	// Output:
+	// evicted about
+	// home cached: true
+}
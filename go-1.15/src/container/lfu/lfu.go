@@ -0,0 +1,180 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lfu implements a cache that evicts its least frequently used
+// entries. Where some keys stay popular while many others are used once,
+// as in a scan mixed with a hot working set, an LFU cache keeps the
+// popular keys that a cache evicting the least recently used entries
+// would let a scan push out.
+package lfu
+
+import "container/list"
+
+// The entries are kept in buckets of equal use counts, in a list ordered
+// by count, and each bucket lists its entries from most to least recently
+// used. Counting a use moves an entry to the following bucket, creating
+// it if needed, so every operation takes O(1) time.
+
+// A Cache maps keys to values, holding entries up to a total cost and
+// evicting the least frequently used entries to make room for new ones.
+// Among entries used equally often, the least recently used is evicted
+// first. Keys must be comparable, as for the built-in map.
+// A Cache must be created with New.
+// A Cache is not safe for concurrent use by multiple goroutines.
+type Cache struct {
+	// OnEvict, if not nil, is called with the key and value of each
+	// entry evicted to make room for another. It is not called for
+	// entries removed by Delete or replaced by Put.
+	OnEvict func(key, value interface{})
+
+	capacity int64
+	cost     int64
+	m        map[interface{}]*entry
+	buckets  list.List // of *bucket, in increasing order of uses
+}
+
+type bucket struct {
+	uses    uint64
+	entries list.List // of *entry, most recently used first
+}
+
+type entry struct {
+	key, value interface{}
+	cost       int64
+	bucket     *list.Element // in Cache.buckets
+	elem       *list.Element // in bucket.entries
+}
+
+// New returns an empty cache holding entries up to a total cost of
+// capacity. It panics if capacity is less than 1.
+func New(capacity int64) *Cache {
+	if capacity < 1 {
+		panic("lfu: capacity less than 1")
+	}
+	return &Cache{capacity: capacity, m: make(map[interface{}]*entry)}
+}
+
+// Len returns the number of entries in c.
+func (c *Cache) Len() int { return len(c.m) }
+
+// Cost returns the total cost of the entries in c.
+func (c *Cache) Cost() int64 { return c.cost }
+
+// Get returns the value of key in c and whether key is present, and
+// counts a use of the entry.
+func (c *Cache) Get(key interface{}) (value interface{}, ok bool) {
+	e, ok := c.m[key]
+	if !ok {
+		return nil, false
+	}
+	c.use(e)
+	return e.value, true
+}
+
+// Put sets the value of key in c to value, with a cost of 1.
+func (c *Cache) Put(key, value interface{}) {
+	c.PutCost(key, value, 1)
+}
+
+// PutCost sets the value of key in c to value, with the given cost,
+// evicting entries until the total cost of the entries fits the capacity
+// of c. Setting a key already present counts a use of its entry. An entry
+// whose cost exceeds the capacity of c is not cached, and the key's
+// previous entry, if any, is deleted. PutCost panics if cost is negative.
+func (c *Cache) PutCost(key, value interface{}, cost int64) {
+	if cost < 0 {
+		panic("lfu: negative cost")
+	}
+	if cost > c.capacity {
+		c.Delete(key)
+		return
+	}
+	e, ok := c.m[key]
+	if ok {
+		c.cost += cost - e.cost
+		e.value, e.cost = value, cost
+		c.use(e)
+	} else {
+		e = &entry{key: key, value: value, cost: cost}
+		c.m[key] = e
+		c.cost += cost
+		first := c.buckets.Front()
+		if first == nil || first.Value.(*bucket).uses != 1 {
+			first = c.buckets.PushFront(&bucket{uses: 1})
+		}
+		e.bucket = first
+		e.elem = first.Value.(*bucket).entries.PushFront(e)
+	}
+	c.evict(e)
+}
+
+// Delete removes key from c. If key was present, Delete returns its value
+// and true.
+func (c *Cache) Delete(key interface{}) (value interface{}, ok bool) {
+	e, ok := c.m[key]
+	if !ok {
+		return nil, false
+	}
+	c.remove(e)
+	return e.value, true
+}
+
+// Uses returns the number of uses counted for key, which is 0 if key is
+// not present. An entry's first Put counts as its first use.
+func (c *Cache) Uses(key interface{}) uint64 {
+	if e, ok := c.m[key]; ok {
+		return e.bucket.Value.(*bucket).uses
+	}
+	return 0
+}
+
+// use moves e to the bucket for one more use.
+func (c *Cache) use(e *entry) {
+	b := e.bucket.Value.(*bucket)
+	next := e.bucket.Next()
+	if next == nil || next.Value.(*bucket).uses != b.uses+1 {
+		next = c.buckets.InsertAfter(&bucket{uses: b.uses + 1}, e.bucket)
+	}
+	b.entries.Remove(e.elem)
+	if b.entries.Len() == 0 {
+		c.buckets.Remove(e.bucket)
+	}
+	e.bucket = next
+	e.elem = next.Value.(*bucket).entries.PushFront(e)
+}
+
+// remove removes e from c.
+func (c *Cache) remove(e *entry) {
+	b := e.bucket.Value.(*bucket)
+	b.entries.Remove(e.elem)
+	if b.entries.Len() == 0 {
+		c.buckets.Remove(e.bucket)
+	}
+	e.bucket, e.elem = nil, nil
+	delete(c.m, e.key)
+	c.cost -= e.cost
+}
+
+// evict evicts entries other than keep until the cost of c fits its
+// capacity.
+func (c *Cache) evict(keep *entry) {
+	for c.cost > c.capacity {
+		// The victim is the least recently used entry of the bucket
+		// with the fewest uses, skipping keep, whose cost by itself
+		// fits the capacity.
+		var victim *entry
+		for b := c.buckets.Front(); victim == nil; b = b.Next() {
+			for el := b.Value.(*bucket).entries.Back(); el != nil; el = el.Prev() {
+				if e := el.Value.(*entry); e != keep {
+					victim = e
+					break
+				}
+			}
+		}
+		c.remove(victim)
+		if c.OnEvict != nil {
+			c.OnEvict(victim.key, victim.value)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lfu
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// check verifies the invariants of c's buckets and entries.
+func check(t *testing.T, c *Cache) {
+	t.Helper()
+	n := 0
+	cost := int64(0)
+	var uses uint64
+	for b := c.buckets.Front(); b != nil; b = b.Next() {
+		bk := b.Value.(*bucket)
+		if bk.uses <= uses {
+			t.Fatalf("bucket for %d uses follows bucket for %d uses", bk.uses, uses)
+		}
+		uses = bk.uses
+		if bk.entries.Len() == 0 {
+			t.Fatalf("bucket for %d uses is empty", bk.uses)
+		}
+		for el := bk.entries.Front(); el != nil; el = el.Next() {
+			e := el.Value.(*entry)
+			if e.bucket != b || e.elem != el {
+				t.Fatalf("entry %v has wrong links", e.key)
+			}
+			if c.m[e.key] != e {
+				t.Fatalf("entry %v is not in the map", e.key)
+			}
+			n++
+			cost += e.cost
+		}
+	}
+	if n != c.Len() {
+		t.Fatalf("buckets hold %d entries, Len = %d", n, c.Len())
+	}
+	if cost != c.Cost() {
+		t.Fatalf("entries cost %d, Cost = %d", cost, c.Cost())
+	}
+	if cost > c.capacity {
+		t.Fatalf("cost %d exceeds capacity %d", cost, c.capacity)
+	}
+}
+
+func TestEvictLeastFrequent(t *testing.T) {
+	var evicted []interface{}
+	c := New(3)
+	c.OnEvict = func(key, value interface{}) {
+		if key.(string)+"!" != value.(string) {
+			t.Errorf("OnEvict(%v, %v)", key, value)
+		}
+		evicted = append(evicted, key)
+	}
+	c.Put("a", "a!")
+	c.Put("b", "b!")
+	c.Put("c", "c!")
+	c.Get("a")
+	c.Get("a")
+	c.Get("c")
+	// b has the fewest uses.
+	c.Put("d", "d!")
+	check(t, c)
+	// d and a new e have one use each; d is older.
+	c.Put("e", "e!")
+	check(t, c)
+	if len(evicted) != 2 || evicted[0] != "b" || evicted[1] != "d" {
+		t.Fatalf("evicted %v, want [b d]", evicted)
+	}
+	for key, uses := range map[string]uint64{"a": 3, "c": 2, "e": 1, "b": 0} {
+		if got := c.Uses(key); got != uses {
+			t.Errorf("Uses(%q) = %d, want %d", key, got, uses)
+		}
+	}
+}
+
+func TestScanResistance(t *testing.T) {
+	c := New(100)
+	for i := 0; i < 50; i++ {
+		c.Put(i, i)
+		c.Get(i)
+	}
+	// A scan over many keys used once must not evict the hot keys.
+	for i := 1000; i < 10000; i++ {
+		c.Put(i, i)
+	}
+	check(t, c)
+	for i := 0; i < 50; i++ {
+		if v, ok := c.Get(i); !ok || v != i {
+			t.Fatalf("Get(%d) = %v, %v after scan", i, v, ok)
+		}
+	}
+}
+
+func TestCost(t *testing.T) {
+	c := New(10)
+	var evicted int
+	c.OnEvict = func(key, value interface{}) { evicted++ }
+	c.PutCost("a", 1, 4)
+	c.PutCost("b", 2, 4)
+	c.Get("b")
+	c.PutCost("c", 3, 4) // evicts a
+	check(t, c)
+	if _, ok := c.Get("a"); ok || evicted != 1 || c.Cost() != 8 {
+		t.Fatalf("after overflow: a present %v, %d evicted, cost %d", ok, evicted, c.Cost())
+	}
+	// Growing an entry evicts others, but never the entry itself.
+	c.PutCost("c", 30, 10)
+	check(t, c)
+	if v, ok := c.Get("c"); !ok || v != 30 || c.Len() != 1 || evicted != 2 {
+		t.Fatalf("after growing c: Get = %v, %v, Len = %d, %d evicted", v, ok, c.Len(), evicted)
+	}
+	// An entry too costly to cache replaces nothing.
+	c.PutCost("c", 300, 11)
+	check(t, c)
+	if _, ok := c.Get("c"); ok || c.Len() != 0 || evicted != 2 {
+		t.Fatalf("after oversized put: c present %v, Len = %d, %d evicted", ok, c.Len(), evicted)
+	}
+	c.PutCost("free", 0, 0)
+	if c.Len() != 1 || c.Cost() != 0 {
+		t.Fatalf("after zero-cost put: Len = %d, Cost = %d", c.Len(), c.Cost())
+	}
+}
+
+func TestRandom(t *testing.T) {
+	c := New(200)
+	ref := make(map[int]int)
+	c.OnEvict = func(key, value interface{}) {
+		if ref[key.(int)] != value.(int) {
+			t.Fatalf("evicted %v=%v, want %v", key, value, ref[key.(int)])
+		}
+		delete(ref, key.(int))
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(500)
+		switch r.Intn(4) {
+		case 0, 1:
+			v, ok := c.Get(k)
+			if w, present := ref[k]; ok != present || ok && v != w {
+				t.Fatalf("Get(%d) = %v, %v, want %v, %v", k, v, ok, w, present)
+			}
+		case 2:
+			c.PutCost(k, i, int64(r.Intn(10)))
+			ref[k] = i
+		case 3:
+			c.Delete(k)
+			delete(ref, k)
+		}
+		if i%100 == 0 {
+			check(t, c)
+		}
+	}
+	check(t, c)
+	if len(ref) != c.Len() {
+		t.Fatalf("cache holds %d entries, want %d", c.Len(), len(ref))
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	c := New(1 << 10)
+	for i := 0; i < 1<<10; i++ {
+		c.Put(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(i & (1<<10 - 1))
+	}
+}
+
+func BenchmarkPutEvict(b *testing.B) {
+	c := New(1 << 10)
+	for i := 0; i < b.N; i++ {
+		c.Put(i, i)
+	}
+}
@@ -37,8 +37,31 @@ func (e *Element) Prev() *Element {
 type List struct {
 	root Element // sentinel列表元素，仅使用&root、root.next和root.prev
 	len  int     // 不包括(这个)标记元素的当前列表长度
+	lim  *limits // 由NewWithFreeList或NewBounded创建时非nil
 }
 
+// limits保存重用元素和有界列表的状态。它单独分配，因此普通列表(包括嵌入在其他结构中的列表)只多占一个指针。
+type limits struct {
+	free    *Element // 被移除后等待重用的元素，通过next链接
+	nfree   int      // free中的元素数
+	maxFree int      // free中最多保留的元素数，为0时不重用元素
+
+	max    int // 元素数的上限，为0时没有上限
+	policy OverflowPolicy
+}
+
+// OverflowPolicy决定向已满的有界列表插入元素时的行为。有界列表被视为从前到后、由旧到新的队列。
+type OverflowPolicy int
+
+const (
+	// Reject不插入新元素，插入方法返回nil。
+	Reject OverflowPolicy = iota
+	// DropOldest先移除列表的第一个元素，再插入新元素。
+	DropOldest
+	// DropNewest先移除列表的最后一个元素，再插入新元素。
+	DropNewest
+)
+
 // Init初始化或清除列表l。
 func (l *List) Init() *List {
 	l.root.next = &l.root
@@ -50,6 +73,32 @@ func (l *List) Init() *List {
 // New返回一个初始化的列表。
 func New() *List { return new(List).Init() }
 
+// NewWithFreeList返回一个初始化的列表，它把被Remove移除的元素最多保留cap个，在之后插入值时重用，
+// 从而让频繁插入和移除元素的队列不必为每个值分配一个新元素。
+//
+// 重用意味着被移除的元素不能再被使用：Remove返回后，元素的Value被清除，元素随后可能以另一个值重新出现在列表中。
+// 调用方不能在移除元素后继续持有它，或把它传给列表的方法。
+// 同样，ForEach和ForEachReverse的f在移除它被调用的元素之后不能再插入元素；使用Iterator.Remove移除当前元素则不受此限制。
+func NewWithFreeList(cap int) *List {
+	l := New()
+	l.lim = &limits{maxFree: cap}
+	return l
+}
+
+// NewBounded返回一个初始化的有界列表，它最多包含max个元素。向已满的列表插入元素时按policy处理：
+// 拒绝插入，或先移除最旧(第一个)或最新(最后一个)的元素。例如，使用DropOldest并总是PushBack的列表就是一个保存最近max个值的环形缓冲区。
+//
+// 所有插入元素的方法都遵守上限，包括批量插入和拼接：它们逐个插入元素，使用Reject时在列表满后停止，
+// 拼接时未被移动的元素留在原列表中。如果max小于1，NewBounded会引发panic。
+func NewBounded(max int, policy OverflowPolicy) *List {
+	if max < 1 {
+		panic("list: NewBounded with max less than 1")
+	}
+	l := New()
+	l.lim = &limits{max: max, policy: policy}
+	return l
+}
+
 // Len返回列表l的元素数量，复杂度为O(1)。
 func (l *List) Len() int { return l.len }
 
@@ -87,9 +136,58 @@ func (l *List) insert(e, at *Element) *Element {
 	return e
 }
 
-// insertValue是一个方便的insert(&Element{Value: v}， at)包装器。
+// insertValue是一个方便的insert(l.newElement(v)， at)包装器。如果有界列表已满且不能腾出空间，则返回nil。
 func (l *List) insertValue(v interface{}, at *Element) *Element {
-	return l.insert(&Element{Value: v}, at)
+	at, ok := l.makeRoom(at)
+	if !ok {
+		return nil
+	}
+	return l.insert(l.newElement(v), at)
+}
+
+// makeRoom在有界列表l已满时按溢出策略移除一个元素，返回调整后的插入位置at，以及是否可以插入。
+func (l *List) makeRoom(at *Element) (*Element, bool) {
+	if !l.bounded() || l.len < l.lim.max {
+		return at, true
+	}
+	var victim *Element
+	switch l.lim.policy {
+	case DropOldest:
+		victim = l.root.next
+	case DropNewest:
+		victim = l.root.prev
+	default:
+		return at, false
+	}
+	if victim == at {
+		at = at.prev
+	}
+	l.Remove(victim)
+	return at, true
+}
+
+// bounded报告l是否为有界列表。
+func (l *List) bounded() bool {
+	return l.lim != nil && l.lim.max > 0
+}
+
+// copyBound让l与other有相同的上限和溢出策略，但不复制other的空闲列表。
+func (l *List) copyBound(other *List) {
+	if other.bounded() {
+		l.lim = &limits{max: other.lim.max, policy: other.lim.policy}
+	}
+}
+
+// newElement返回一个值为v、尚未链接的元素，它优先重用空闲列表中的元素。
+func (l *List) newElement(v interface{}) *Element {
+	if l.lim == nil || l.lim.free == nil {
+		return &Element{Value: v}
+	}
+	e := l.lim.free
+	l.lim.free = e.next
+	l.lim.nfree--
+	e.Value = v
+	return e
 }
 
 // remove从它的列表中移除e，递减l.len，并返回e。
@@ -120,27 +218,38 @@ func (l *List) move(e, at *Element) *Element {
 }
 
 // 如果e是列表l的一个元素，那么Remove将e从l中移除。它返回元素值e. value。元素不能为nil。
+// 如果l由NewWithFreeList创建，e可能被放入空闲列表以供重用，此后不能再使用e。
 func (l *List) Remove(e *Element) interface{} {
 	if e.list == l {
 		// 如果e.list == l，则l必须在e插入l时已经初始化，或者l == nil (e是一个零元素)，并且l.remove将崩溃
 		l.remove(e)
+		if l.lim != nil && l.lim.nfree < l.lim.maxFree {
+			// 放入空闲列表，清除Value以免保留它引用的内存。
+			v := e.Value
+			e.Value = nil
+			e.next = l.lim.free
+			l.lim.free = e
+			l.lim.nfree++
+			return v
+		}
 	}
 	return e.Value
 }
 
-// PushFront在列表l的前面插入一个新元素e，并返回e。
+// PushFront在列表l的前面插入一个新元素e，并返回e。如果l是由NewBounded创建的已满列表且溢出策略为Reject，则不插入元素并返回nil。
 func (l *List) PushFront(v interface{}) *Element {
 	l.lazyInit()
 	return l.insertValue(v, &l.root)
 }
 
-// PushBack在列表l的后面插入一个新元素e，其值为v，并返回e。
+// PushBack在列表l的后面插入一个新元素e，其值为v，并返回e。如果l是由NewBounded创建的已满列表且溢出策略为Reject，则不插入元素并返回nil。
 func (l *List) PushBack(v interface{}) *Element {
 	l.lazyInit()
 	return l.insertValue(v, l.root.prev)
 }
 
 // InsertBefore在标记的前面插入一个值为v的新元素e，并返回e。如果标记不是l的元素，则列表不会被修改。标记不得为零。
+// 如果l是由NewBounded创建的已满列表且溢出策略为Reject，则不插入元素并返回nil。
 func (l *List) InsertBefore(v interface{}, mark *Element) *Element {
 	if mark.list != l {
 		return nil
@@ -149,6 +258,7 @@ func (l *List) InsertBefore(v interface{}, mark *Element) *Element {
 }
 
 // InsertAfter 在标记后面插入一个新元素e，值为v，然后返回e。如果标记不是l的元素，列表不会被修改。标记不得为零。
+// 如果l是由NewBounded创建的已满列表且溢出策略为Reject，则不插入元素并返回nil。
 func (l *List) InsertAfter(v interface{}, mark *Element) *Element {
 	if mark.list != l {
 		return nil
@@ -194,6 +304,11 @@ func (l *List) MoveAfter(e, mark *Element) {
 // PushBackList在列表l的后面插入另一个列表的副本。列表l和其他列表可能是相同的。它们不能是零。
 func (l *List) PushBackList(other *List) {
 	l.lazyInit()
+	if l.bounded() {
+		// 溢出时移除的元素可能正是other中下一个要复制的元素。
+		l.insertList(other, l.root.prev)
+		return
+	}
 	for i, e := other.Len(), other.Front(); i > 0; i, e = i-1, e.Next() {
 		l.insertValue(e.Value, l.root.prev)
 	}
@@ -202,7 +317,555 @@ func (l *List) PushBackList(other *List) {
 // PushFrontList在列表l的前面插入另一个列表的副本。列表l和其他列表可能是相同的。它们不能是零。
 func (l *List) PushFrontList(other *List) {
 	l.lazyInit()
+	if l.bounded() {
+		l.insertList(other, &l.root)
+		return
+	}
 	for i, e := other.Len(), other.Back(); i > 0; i, e = i-1, e.Prev() {
 		l.insertValue(e.Value, &l.root)
 	}
 }
+
+// ForEach从前到后对列表l的每个元素e调用f，直到f返回false。
+// f可以移除e，也可以在l中插入元素；插入到e之后的元素会被访问到，如果f移除了e，插入到e之前的元素也会被访问到。
+// f不能移除e以外的元素。
+func (l *List) ForEach(f func(e *Element) bool) {
+	for it := (Iterator{l: l}); it.Next(); {
+		if !f(it.e) {
+			return
+		}
+	}
+}
+
+// Iterator从前到后遍历一个列表，并允许在遍历过程中移除当前元素，因此调用方不必在移除前先保存e.Next()。
+//
+// 遍历列表l的写法(其中l是*List):
+//	for it := l.Iterator(); it.Next(); {
+//		if it.Element().Value == x {
+//			it.Remove()
+//		}
+//	}
+//
+// 遍历过程中可以移除当前元素(通过Iterator.Remove或List.Remove)，也可以插入元素，但不能移除当前元素以外的元素。
+// 插入到当前元素之后的元素会被访问到。如果当前元素被List.Remove移除，遍历从它原来的前一个元素之后继续，
+// 因此插入到它之前的元素也会被访问到。
+type Iterator struct {
+	l       *List
+	e       *Element // 当前元素
+	prev    *Element // 到达e时e的前一个元素(可能是&l.root)，用于e被List.Remove移除之后继续遍历
+	next    *Element // e被Iterator.Remove移除之前的下一个元素
+	started bool
+	removed bool // e已被Iterator.Remove移除，可能已被重用
+}
+
+// Iterator返回一个位于列表l第一个元素之前的Iterator。
+func (l *List) Iterator() *Iterator {
+	return &Iterator{l: l}
+}
+
+// Next将迭代器前进到下一个元素，并报告该元素是否存在。第一次调用Next前进到列表的第一个元素。
+func (it *Iterator) Next() bool {
+	var e *Element
+	switch {
+	case !it.started:
+		it.started = true
+		e = it.l.Front()
+	case it.e == nil:
+		return false
+	case it.removed:
+		e = it.next
+	case it.e.list == it.l:
+		e = it.e.Next()
+	default:
+		// 当前元素已被List.Remove移除，前一个元素仍在列表中。
+		if e = it.prev.next; e == &it.l.root {
+			e = nil
+		}
+	}
+	it.e = e
+	it.removed = false
+	if e == nil {
+		return false
+	}
+	it.prev = e.prev
+	return true
+}
+
+// Element返回当前元素，在第一次调用Next之前或遍历结束之后返回nil。
+func (it *Iterator) Element() *Element {
+	return it.e
+}
+
+// Remove从列表中移除当前元素并返回其值。下一次调用Next前进到被移除元素原来的下一个元素。
+// 当前元素不能为nil。
+func (it *Iterator) Remove() interface{} {
+	it.next = it.e.Next()
+	it.removed = true
+	return it.l.Remove(it.e)
+}
+
+// Sort按less对列表l的元素进行稳定排序，less报告值a是否应排在值b之前。
+// 排序是在链接本身上进行的自底向上的归并排序，不分配内存，时间复杂度为O(n log n)；
+// 元素只是被重新链接，因此调用方持有的*Element仍然有效，并且仍属于l。
+// less不能修改列表。如果less引发panic，列表将处于不一致的状态。
+func (l *List) Sort(less func(a, b interface{}) bool) {
+	if l.len < 2 {
+		return
+	}
+	// 将环断开为以nil结尾的单链表，排序时只维护next指针。
+	head := l.root.next
+	l.root.prev.next = nil
+	for k := 1; ; k *= 2 {
+		// 将长度为k的有序段两两归并。
+		p := head
+		head = nil
+		var tail *Element
+		merges := 0
+		for p != nil {
+			merges++
+			q := p
+			psize := 0
+			for psize < k && q != nil {
+				psize++
+				q = q.next
+			}
+			qsize := k
+			for psize > 0 || qsize > 0 && q != nil {
+				var e *Element
+				switch {
+				case psize == 0:
+					e, q = q, q.next
+					qsize--
+				case qsize == 0 || q == nil || !less(q.Value, p.Value):
+					// 相等时先取前一段的元素，以保持稳定。
+					e, p = p, p.next
+					psize--
+				default:
+					e, q = q, q.next
+					qsize--
+				}
+				if tail == nil {
+					head = e
+				} else {
+					tail.next = e
+				}
+				tail = e
+			}
+			p = q
+		}
+		tail.next = nil
+		if merges <= 1 {
+			break
+		}
+	}
+	// 恢复prev指针和环。
+	prev := &l.root
+	for e := head; e != nil; e = e.next {
+		e.prev = prev
+		prev.next = e
+		prev = e
+	}
+	prev.next = &l.root
+	l.root.prev = prev
+}
+
+// Reverse原地反转列表l中元素的顺序，时间复杂度为O(n)，不分配内存。元素仍属于l。
+func (l *List) Reverse() {
+	if l.len < 2 {
+		return
+	}
+	// 交换每个元素(包括root)的next和prev指针。
+	e := &l.root
+	for {
+		e.next, e.prev = e.prev, e.next
+		e = e.prev // 原来的next
+		if e == &l.root {
+			return
+		}
+	}
+}
+
+// SpliceBack将列表other的全部元素移动到列表l的后面，other变为空列表。
+// 与PushBackList不同，元素不会被复制：调用方持有的other的*Element仍然有效，此后属于l。
+// 列表l和other可以相同，此时列表不被修改。它们不能是nil。
+//
+// 拼接本身只需常数次指针操作，但每个元素都记录着它所属的列表，因此复杂度为O(n)，n为other的长度；SpliceBack不分配内存。
+func (l *List) SpliceBack(other *List) {
+	l.lazyInit()
+	l.splice(other, l.root.prev)
+}
+
+// SpliceFront将列表other的全部元素移动到列表l的前面，other变为空列表。其他方面与SpliceBack相同。
+func (l *List) SpliceFront(other *List) {
+	l.lazyInit()
+	l.splice(other, &l.root)
+}
+
+// splice将other的元素链接到at之后，并清空other。
+func (l *List) splice(other *List, at *Element) {
+	if other == l || other.len == 0 {
+		return
+	}
+	if l.bounded() {
+		for other.len > 0 {
+			var ok bool
+			if at, ok = l.makeRoom(at); !ok {
+				return
+			}
+			e := other.remove(other.root.next)
+			at = l.insert(e, at)
+		}
+		return
+	}
+	for e := other.root.next; e != &other.root; e = e.next {
+		e.list = l
+	}
+	l.link(other.root.next, other.root.prev, at, other.len)
+	other.Init()
+}
+
+// Clone返回列表l的一个副本，其元素与l的元素顺序相同但相互独立。
+// 如果copyValue不为nil，每个新元素的值为copyValue对原元素值的返回值，可用于深度复制值；否则值被直接复制。
+// 副本与l有相同的上限和溢出策略，但不重用被移除的元素。
+func (l *List) Clone(copyValue func(interface{}) interface{}) *List {
+	c := New()
+	c.copyBound(l)
+	for e := l.Front(); e != nil; e = e.Next() {
+		v := e.Value
+		if copyValue != nil {
+			v = copyValue(v)
+		}
+		c.insertValue(v, c.root.prev)
+	}
+	return c
+}
+
+// Find从前到后查找列表l中第一个值满足match的元素，如果没有则返回nil。复杂度为O(n)。
+func (l *List) Find(match func(interface{}) bool) *Element {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if match(e.Value) {
+			return e
+		}
+	}
+	return nil
+}
+
+// IndexOf返回元素e在列表l中的位置，第一个元素的位置为0；如果e不是l的元素则返回-1。
+// 它从前面开始计数，复杂度为O(n)，不适合在循环中对同一个长列表反复调用。
+// 元素不能为nil。
+func (l *List) IndexOf(e *Element) int {
+	if e.list != l {
+		return -1
+	}
+	i := 0
+	for p := l.root.next; p != e; p = p.next {
+		i++
+	}
+	return i
+}
+
+// RemoveIf从列表l中移除所有值满足pred的元素，并返回移除的元素数。它只遍历列表一次，复杂度为O(n)。
+// pred不能修改列表。
+func (l *List) RemoveIf(pred func(interface{}) bool) int {
+	n := 0
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		if pred(e.Value) {
+			l.Remove(e)
+			n++
+		}
+		e = next
+	}
+	return n
+}
+
+// InsertListBefore在标记的前面插入另一个列表的副本。如果标记不是l的元素，则列表不会被修改。
+// 列表l和other可能是相同的。它们和标记都不能是nil。
+func (l *List) InsertListBefore(other *List, mark *Element) {
+	if mark.list != l {
+		return
+	}
+	l.insertList(other, mark.prev)
+}
+
+// InsertListAfter在标记的后面插入另一个列表的副本。如果标记不是l的元素，则列表不会被修改。
+// 列表l和other可能是相同的。它们和标记都不能是nil。
+func (l *List) InsertListAfter(other *List, mark *Element) {
+	if mark.list != l {
+		return
+	}
+	l.insertList(other, mark)
+}
+
+// insertList在at之后插入other的副本。副本先被链接成一条独立的链，再整体接入l，因此other可以是l本身。
+func (l *List) insertList(other *List, at *Element) {
+	n := other.len
+	if n == 0 {
+		return
+	}
+	if l.bounded() {
+		// 先复制出值，因为溢出时other(可能就是l)的元素会被移除。
+		values := make([]interface{}, 0, n)
+		for i, e := 0, other.root.next; i < n; i, e = i+1, e.next {
+			values = append(values, e.Value)
+		}
+		l.insertSlice(values, at)
+		return
+	}
+	var first, last *Element
+	for i, e := 0, other.root.next; i < n; i, e = i+1, e.next {
+		c := l.newElement(e.Value)
+		c.list = l
+		if first == nil {
+			first = c
+		} else {
+			last.next = c
+			c.prev = last
+		}
+		last = c
+	}
+	l.link(first, last, at, n)
+}
+
+// link将由first到last的n个元素组成的链接入at之后。链中元素的list字段必须已是l。
+func (l *List) link(first, last, at *Element, n int) {
+	first.prev = at
+	last.next = at.next
+	at.next.prev = last
+	at.next = first
+	l.len += n
+}
+
+// SpliceBefore将列表other的全部元素移动到标记的前面，other变为空列表。
+// 与InsertListBefore不同，元素不会被复制，而是像SpliceBack那样转移给l，复杂度为O(n)，n为other的长度。
+// 如果标记不是l的元素，或者l和other相同，则列表不会被修改。它们和标记都不能是nil。
+func (l *List) SpliceBefore(other *List, mark *Element) {
+	if mark.list != l {
+		return
+	}
+	l.splice(other, mark.prev)
+}
+
+// SpliceAfter将列表other的全部元素移动到标记的后面，other变为空列表。其他方面与SpliceBefore相同。
+func (l *List) SpliceAfter(other *List, mark *Element) {
+	if mark.list != l {
+		return
+	}
+	l.splice(other, mark)
+}
+
+// PushBackSlice按顺序在列表l的后面插入值为values的新元素。
+// 它与对每个值调用PushBack的结果相同，但只初始化和更新列表一次。
+func (l *List) PushBackSlice(values []interface{}) {
+	l.lazyInit()
+	l.insertSlice(values, l.root.prev)
+}
+
+// PushFrontSlice在列表l的前面插入值为values的新元素，保持它们在values中的顺序，即values[0]成为第一个元素。
+func (l *List) PushFrontSlice(values []interface{}) {
+	l.lazyInit()
+	l.insertSlice(values, &l.root)
+}
+
+// insertSlice在at之后插入值为values的新元素。
+func (l *List) insertSlice(values []interface{}, at *Element) {
+	if len(values) == 0 {
+		return
+	}
+	if l.bounded() {
+		for _, v := range values {
+			if at = l.insertValue(v, at); at == nil {
+				return
+			}
+		}
+		return
+	}
+	var first, last *Element
+	for _, v := range values {
+		c := l.newElement(v)
+		c.list = l
+		if first == nil {
+			first = c
+		} else {
+			last.next = c
+			c.prev = last
+		}
+		last = c
+	}
+	l.link(first, last, at, len(values))
+}
+
+// At返回列表l中位置为i的元素，第一个元素的位置为0；如果i超出范围则返回nil。
+// 它从离i较近的一端开始查找，复杂度为O(min(i, n-i))。
+func (l *List) At(i int) *Element {
+	if i < 0 || i >= l.len {
+		return nil
+	}
+	p, _ := l.walk(i, nil)
+	return p
+}
+
+// MoveToIndex移动元素e，使它在列表l中的位置变为i，其他元素保持原有的相对顺序。
+// 如果e不是l的元素，或者i超出范围，则列表不会被修改。元素不能为nil。
+// 它从离i较近的一端开始查找，复杂度为O(min(i, n-i))。
+func (l *List) MoveToIndex(e *Element, i int) {
+	if e.list != l || i < 0 || i >= l.len {
+		return
+	}
+	p, before := l.walk(i, e)
+	switch {
+	case p == e:
+	case before:
+		// e移走后p前移一位，因此e应放在p之后。
+		l.move(e, p)
+	default:
+		l.move(e, p.prev)
+	}
+}
+
+// walk返回位置为i的元素p，以及e是否位于p之前。0 <= i < l.len。
+func (l *List) walk(i int, e *Element) (p *Element, before bool) {
+	if i < l.len/2 {
+		p = l.root.next
+		for ; i > 0; i-- {
+			if p == e {
+				before = true
+			}
+			p = p.next
+		}
+		return p, before
+	}
+	after := false
+	p = l.root.prev
+	for j := l.len - 1; j > i; j-- {
+		if p == e {
+			after = true
+		}
+		p = p.prev
+	}
+	return p, !after && p != e
+}
+
+// Swap交换元素a和b在列表l中的位置。元素是被重新链接而不是交换Value，因此调用方持有的*Element仍然指向原来的值。
+// 如果a或b不是l的元素，或a == b，则列表不会被修改。元素不能为nil。
+func (l *List) Swap(a, b *Element) {
+	if a.list != l || b.list != l || a == b {
+		return
+	}
+	switch {
+	case a.next == b:
+		l.move(a, b)
+	case b.next == a:
+		l.move(b, a)
+	default:
+		prev := a.prev
+		l.move(a, b)
+		l.move(b, prev)
+	}
+}
+
+// Split将列表l中从at到最后的元素移动到一个新列表中并返回它，l中只留下at之前的元素。
+// 元素不会被复制，调用方持有的*Element仍然有效，此后属于新列表。新列表与l有相同的上限和溢出策略。
+// 如果at不是l的元素，Split返回nil，列表不被修改。at不能为nil。
+//
+// 断开链接只需常数次指针操作，但每个元素都记录着它所属的列表，因此复杂度为O(k)，k为被移动的元素数。
+func (l *List) Split(at *Element) *List {
+	if at.list != l {
+		return nil
+	}
+	s := New()
+	s.copyBound(l)
+	first, last := at, l.root.prev
+	n := 0
+	for e := at; e != &l.root; e = e.next {
+		e.list = s
+		n++
+	}
+	// 在l中把at之前的元素与root连接起来。
+	first.prev.next = &l.root
+	l.root.prev = first.prev
+	l.len -= n
+	s.link(first, last, &s.root, n)
+	return s
+}
+
+// Adopt将另一个列表的元素e移动到列表l的后面并返回e。元素不会被复制，调用方持有的e仍然有效，此后属于l。
+// 如果e已经是l的元素，或者不属于任何列表，则列表不被修改，Adopt分别返回e和nil。
+// 如果l是已满的有界列表且溢出策略为Reject，e留在原列表中，Adopt返回nil。元素不能为nil。
+func (l *List) Adopt(e *Element) *Element {
+	if e.list == l {
+		return e
+	}
+	if e.list == nil {
+		return nil
+	}
+	l.lazyInit()
+	at, ok := l.makeRoom(l.root.prev)
+	if !ok {
+		return nil
+	}
+	e.list.remove(e)
+	return l.insert(e, at)
+}
+
+// ForEachReverse从后到前对列表l的每个元素e调用f，直到f返回false。
+// f可以移除e，也可以在l中插入元素；插入到e之前的元素会被访问到，如果f移除了e，插入到e之后的元素也会被访问到。
+// f不能移除e以外的元素。
+func (l *List) ForEachReverse(f func(e *Element) bool) {
+	for e := l.Back(); e != nil; {
+		next := e.next // 仍在列表中，可能是&l.root
+		if !f(e) {
+			return
+		}
+		if e.list == l {
+			e = e.Prev()
+		} else if e = next.prev; e == &l.root {
+			e = nil
+		}
+	}
+}
+
+// InsertSorted在按less排序的列表l中插入一个值为v的新元素e，使列表保持有序，并返回e。
+// e被插入到所有不排在v之后的元素之后，因此相等的值保持插入顺序。
+// 它从前面开始查找位置，复杂度为O(n)，适合较小的有序列表；较大的优先级队列应使用container/heap。
+// 如果l是已满的有界列表且溢出策略为Reject，则返回nil。
+func (l *List) InsertSorted(v interface{}, less func(a, b interface{}) bool) *Element {
+	l.lazyInit()
+	at := l.root.next
+	for at != &l.root && !less(v, at.Value) {
+		at = at.next
+	}
+	return l.insertValue(v, at.prev)
+}
+
+// RotateForward将列表l向前轮转n个位置：第一个元素移到最后，重复n次，位置为n的元素成为第一个元素。
+// n可以大于列表长度，也可以为负数，此时列表向后轮转-n个位置。
+// 轮转只重新链接哨兵root，元素不被移动或分配；复杂度为O(min(k, len-k))，其中k = n mod len。
+func (l *List) RotateForward(n int) {
+	if l.len < 2 {
+		return
+	}
+	k := n % l.len
+	if k < 0 {
+		k += l.len
+	}
+	if k == 0 {
+		return
+	}
+	front, _ := l.walk(k, nil)
+	// 将root从环中取出，再放到front之前。
+	l.root.prev.next = l.root.next
+	l.root.next.prev = l.root.prev
+	l.root.prev = front.prev
+	l.root.next = front
+	front.prev.next = &l.root
+	front.prev = &l.root
+}
+
+// RotateBackward将列表l向后轮转n个位置：最后一个元素移到最前，重复n次。它等价于RotateForward(-n)。
+func (l *List) RotateBackward(n int) {
+	if l.len < 2 {
+		return
+	}
+	l.RotateForward(-(n % l.len))
+}
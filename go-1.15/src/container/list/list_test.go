@@ -341,3 +341,806 @@ func TestMoveUnknownMark(t *testing.T) {
 	checkList(t, &l1, []interface{}{1})
 	checkList(t, &l2, []interface{}{2})
 }
+
+func TestForEach(t *testing.T) {
+	l := New()
+	for i := 1; i <= 6; i++ {
+		l.PushBack(i)
+	}
+	var seen []interface{}
+	l.ForEach(func(e *Element) bool {
+		seen = append(seen, e.Value)
+		switch e.Value.(int) {
+		case 2, 3:
+			l.Remove(e)
+		case 4:
+			l.InsertAfter(40, e)
+		case 5:
+			return false
+		}
+		return true
+	})
+	if want := []interface{}{1, 2, 3, 4, 40, 5}; !equalValues(seen, want) {
+		t.Errorf("ForEach visited %v, want %v", seen, want)
+	}
+	checkList(t, l, []interface{}{1, 4, 40, 5, 6})
+
+	// An element inserted after e is visited even if f then removes e.
+	seen = nil
+	l.ForEach(func(e *Element) bool {
+		seen = append(seen, e.Value)
+		if e.Value == 4 {
+			l.InsertAfter(41, e)
+			l.Remove(e)
+		}
+		return true
+	})
+	if want := []interface{}{1, 4, 41, 40, 5, 6}; !equalValues(seen, want) {
+		t.Errorf("ForEach visited %v, want %v", seen, want)
+	}
+	checkList(t, l, []interface{}{1, 41, 40, 5, 6})
+
+	// ForEach of an empty list, including its zero value.
+	var z List
+	z.ForEach(func(e *Element) bool {
+		t.Error("ForEach of empty list called f")
+		return true
+	})
+}
+
+func TestIterator(t *testing.T) {
+	var l List
+	it := l.Iterator()
+	if it.Next() || it.Element() != nil {
+		t.Error("Iterator of empty list has elements")
+	}
+
+	for i := 1; i <= 6; i++ {
+		l.PushBack(i)
+	}
+	// Remove the odd elements, the last one with List.Remove.
+	var seen []interface{}
+	for it := l.Iterator(); it.Next(); {
+		v := it.Element().Value.(int)
+		seen = append(seen, v)
+		switch {
+		case v == 5:
+			l.Remove(it.Element())
+		case v%2 == 1:
+			if got := it.Remove(); got != v {
+				t.Errorf("Remove() = %v, want %v", got, v)
+			}
+		}
+	}
+	if want := []interface{}{1, 2, 3, 4, 5, 6}; !equalValues(seen, want) {
+		t.Errorf("iteration visited %v, want %v", seen, want)
+	}
+	checkList(t, &l, []interface{}{2, 4, 6})
+
+	// Removing every element, including the last.
+	it = l.Iterator()
+	for it.Next() {
+		it.Remove()
+	}
+	checkList(t, &l, nil)
+	if it.Next() || it.Element() != nil {
+		t.Error("exhausted Iterator has elements")
+	}
+}
+
+func equalValues(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSort(t *testing.T) {
+	type item struct{ key, seq int }
+	less := func(a, b interface{}) bool { return a.(item).key < b.(item).key }
+	for n := 0; n <= 70; n++ {
+		l := New()
+		var es []*Element
+		for i := 0; i < n; i++ {
+			// Few distinct keys, to check stability.
+			es = append(es, l.PushBack(item{(i * 7919) % 5, i}))
+		}
+		l.Sort(less)
+		checkListLen(t, l, n)
+		var sorted []*Element
+		for e := l.Front(); e != nil; e = e.Next() {
+			sorted = append(sorted, e)
+		}
+		checkListPointers(t, l, sorted)
+		for i := 1; i < len(sorted); i++ {
+			a, b := sorted[i-1].Value.(item), sorted[i].Value.(item)
+			if a.key > b.key || a.key == b.key && a.seq > b.seq {
+				t.Fatalf("n=%d: %v before %v", n, a, b)
+			}
+		}
+		for _, e := range es {
+			if e.list != l {
+				t.Fatalf("n=%d: element %v no longer in list", n, e.Value)
+			}
+		}
+	}
+
+	var z List
+	z.Sort(less)
+	checkListPointers(t, &z, nil)
+}
+
+func BenchmarkSort(b *testing.B) {
+	l := New()
+	for i := 0; i < 1000; i++ {
+		l.PushBack((i * 7919) % 1000)
+	}
+	less := func(a, b interface{}) bool { return a.(int) < b.(int) }
+	greater := func(a, b interface{}) bool { return a.(int) > b.(int) }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%2 == 0 {
+			l.Sort(less)
+		} else {
+			l.Sort(greater)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	for n := 0; n <= 4; n++ {
+		l := New()
+		var es []*Element
+		for i := 0; i < n; i++ {
+			es = append(es, l.PushBack(i))
+		}
+		l.Reverse()
+		for i, j := 0, len(es)-1; i < j; i, j = i+1, j-1 {
+			es[i], es[j] = es[j], es[i]
+		}
+		checkListPointers(t, l, es)
+	}
+
+	var z List
+	z.Reverse()
+	checkListPointers(t, &z, nil)
+}
+
+func TestSplice(t *testing.T) {
+	var l1, l2 List
+	a := l1.PushBack(1)
+	b := l1.PushBack(2)
+	c := l2.PushBack(3)
+	d := l2.PushBack(4)
+
+	l1.SpliceBack(&l2)
+	checkListPointers(t, &l1, []*Element{a, b, c, d})
+	checkListPointers(t, &l2, []*Element{})
+	// The moved elements now belong to l1.
+	l1.MoveToFront(d)
+	checkListPointers(t, &l1, []*Element{d, a, b, c})
+	if l2.Remove(c) != 3 || l1.Len() != 4 {
+		t.Error("element moved by SpliceBack is still in the old list")
+	}
+
+	e := l2.PushBack(5)
+	l1.SpliceFront(&l2)
+	checkListPointers(t, &l1, []*Element{e, d, a, b, c})
+	checkListPointers(t, &l2, []*Element{})
+
+	// Splicing an empty list, or a list into itself, changes nothing.
+	l1.SpliceBack(&l2)
+	l1.SpliceFront(&l1)
+	checkListPointers(t, &l1, []*Element{e, d, a, b, c})
+
+	// Splicing into the zero value of a list.
+	var z List
+	z.SpliceFront(&l1)
+	checkListPointers(t, &z, []*Element{e, d, a, b, c})
+	checkListPointers(t, &l1, []*Element{})
+	l1.PushBack(6)
+	checkList(t, &l1, []interface{}{6})
+}
+
+func TestClone(t *testing.T) {
+	var z List
+	checkListPointers(t, z.Clone(nil), []*Element{})
+
+	l := New()
+	l.PushBack(1)
+	l.PushBack([]int{2})
+	l.PushBack(3)
+	c := l.Clone(nil)
+	if c.Len() != 3 || c.Front().Value != 1 || c.Back().Value != 3 {
+		t.Fatalf("Clone(nil) has %d elements, want 3 with the same values", c.Len())
+	}
+	// The copy is independent of the original.
+	c.Remove(c.Front())
+	checkListLen(t, l, 3)
+	l.Remove(c.Back())
+	checkListLen(t, l, 3)
+	// Without copyValue the values are shared.
+	c.Front().Value.([]int)[0] = 20
+	if l.Front().Next().Value.([]int)[0] != 20 {
+		t.Error("Clone(nil) copied a value")
+	}
+
+	d := l.Clone(func(v interface{}) interface{} {
+		if s, ok := v.([]int); ok {
+			return append([]int(nil), s...)
+		}
+		return v
+	})
+	d.Front().Next().Value.([]int)[0] = 200
+	if l.Front().Next().Value.([]int)[0] != 20 {
+		t.Error("Clone with copyValue shares a value")
+	}
+	checkListLen(t, d, 3)
+}
+
+func TestFindIndexOf(t *testing.T) {
+	var l List
+	isEven := func(v interface{}) bool { return v.(int)%2 == 0 }
+	if e := l.Find(isEven); e != nil {
+		t.Errorf("Find in empty list = %v, want nil", e.Value)
+	}
+	e1 := l.PushBack(1)
+	e2 := l.PushBack(2)
+	e3 := l.PushBack(3)
+	l.PushBack(4)
+	if e := l.Find(isEven); e != e2 {
+		t.Errorf("Find(isEven) = %v, want the element of 2", e)
+	}
+	if e := l.Find(func(v interface{}) bool { return v.(int) > 10 }); e != nil {
+		t.Errorf("Find of no match = %v, want nil", e.Value)
+	}
+
+	for i, e := range []*Element{e1, e2, e3} {
+		if got := l.IndexOf(e); got != i {
+			t.Errorf("IndexOf(%v) = %d, want %d", e.Value, got, i)
+		}
+	}
+	l.Remove(e2)
+	if got := l.IndexOf(e2); got != -1 {
+		t.Errorf("IndexOf(removed element) = %d, want -1", got)
+	}
+	if got := l.IndexOf(e3); got != 1 {
+		t.Errorf("IndexOf(%v) after removal = %d, want 1", e3.Value, got)
+	}
+	other := New()
+	if got := l.IndexOf(other.PushBack(1)); got != -1 {
+		t.Errorf("IndexOf(element of other list) = %d, want -1", got)
+	}
+}
+
+func TestFreeList(t *testing.T) {
+	l := NewWithFreeList(2)
+	a := l.PushBack(1)
+	b := l.PushBack(2)
+	c := l.PushBack(3)
+	for _, e := range []*Element{a, b, c} {
+		v := e.Value
+		if got := l.Remove(e); got != v {
+			t.Errorf("Remove() = %v, want %v", got, v)
+		}
+	}
+	if l.lim.nfree != 2 || a.Value != nil || b.Value != nil {
+		t.Fatalf("free list holds %d elements, want 2 with cleared values", l.lim.nfree)
+	}
+	// The last freed element is reused first.
+	d := l.PushFront(4)
+	e := l.PushBack(5)
+	f := l.PushBack(6)
+	if d != b || e != a || f == a || f == b || f == c {
+		t.Error("insertions did not reuse the freed elements")
+	}
+	checkListPointers(t, l, []*Element{d, e, f})
+	checkList(t, l, []interface{}{4, 5, 6})
+
+	// Removing the current element with Iterator.Remove
+	// is safe even when insertions reuse it.
+	var seen []interface{}
+	for it := l.Iterator(); it.Next(); {
+		v := it.Element().Value
+		seen = append(seen, v)
+		if v == 4 {
+			it.Remove()
+			l.PushBack(7)
+		}
+	}
+	if want := []interface{}{4, 5, 6, 7}; !equalValues(seen, want) {
+		t.Errorf("iteration visited %v, want %v", seen, want)
+	}
+	checkList(t, l, []interface{}{5, 6, 7})
+
+	// A list without a free list does not reuse elements.
+	m := New()
+	g := m.PushBack(1)
+	m.Remove(g)
+	if m.PushBack(2) == g || g.Value != 1 {
+		t.Error("list without free list reused an element")
+	}
+}
+
+func TestFreeListAllocs(t *testing.T) {
+	l := NewWithFreeList(1)
+	l.Remove(l.PushBack(nil))
+	if n := testing.AllocsPerRun(100, func() { l.Remove(l.PushBack(nil)) }); n != 0 {
+		t.Errorf("push and remove allocate %v times, want 0", n)
+	}
+}
+
+func BenchmarkPushRemove(b *testing.B) {
+	for _, bb := range []struct {
+		name string
+		l    *List
+	}{
+		{"New", New()},
+		{"NewWithFreeList", NewWithFreeList(16)},
+	} {
+		l := bb.l
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Remove(l.PushBack(nil))
+			}
+		})
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	var z List
+	if n := z.RemoveIf(func(interface{}) bool { return true }); n != 0 {
+		t.Errorf("RemoveIf on empty list = %d, want 0", n)
+	}
+
+	for _, l := range []*List{New(), NewWithFreeList(8)} {
+		var es []*Element
+		for i := 0; i < 8; i++ {
+			es = append(es, l.PushBack(i))
+		}
+		if n := l.RemoveIf(func(v interface{}) bool { return v.(int)%3 != 1 }); n != 5 {
+			t.Errorf("RemoveIf = %d, want 5", n)
+		}
+		checkListPointers(t, l, []*Element{es[1], es[4], es[7]})
+		if n := l.RemoveIf(func(interface{}) bool { return true }); n != 3 {
+			t.Errorf("RemoveIf of all = %d, want 3", n)
+		}
+		checkListPointers(t, l, []*Element{})
+	}
+}
+
+func TestInsertList(t *testing.T) {
+	l := New()
+	a := l.PushBack(1)
+	b := l.PushBack(2)
+	other := New()
+	other.PushBack(10)
+	other.PushBack(20)
+
+	l.InsertListAfter(other, a)
+	checkList(t, l, []interface{}{1, 10, 20, 2})
+	checkList(t, other, []interface{}{10, 20})
+	l.InsertListBefore(other, a)
+	checkList(t, l, []interface{}{10, 20, 1, 10, 20, 2})
+
+	// Inserting a list into itself.
+	m := New()
+	x := m.PushBack(1)
+	m.PushBack(2)
+	m.InsertListAfter(m, x)
+	checkList(t, m, []interface{}{1, 1, 2, 2})
+	m.InsertListBefore(m, x)
+	checkList(t, m, []interface{}{1, 1, 2, 2, 1, 1, 2, 2})
+
+	// An empty list or a mark of another list changes nothing.
+	l.InsertListAfter(New(), b)
+	l.InsertListBefore(other, other.Front())
+	checkList(t, l, []interface{}{10, 20, 1, 10, 20, 2})
+
+	// Copies reuse freed elements.
+	f := NewWithFreeList(4)
+	mark := f.PushBack(0)
+	f.Remove(f.PushBack(-1))
+	f.InsertListBefore(other, mark)
+	checkList(t, f, []interface{}{10, 20, 0})
+}
+
+func TestSpliceAtMark(t *testing.T) {
+	l := New()
+	a := l.PushBack(1)
+	b := l.PushBack(2)
+	other := New()
+	c := other.PushBack(10)
+	d := other.PushBack(20)
+
+	l.SpliceAfter(other, a)
+	checkListPointers(t, l, []*Element{a, c, d, b})
+	checkListPointers(t, other, []*Element{})
+
+	e := other.PushBack(30)
+	l.SpliceBefore(other, a)
+	checkListPointers(t, l, []*Element{e, a, c, d, b})
+	checkListPointers(t, other, []*Element{})
+	l.MoveToBack(e)
+	checkListPointers(t, l, []*Element{a, c, d, b, e})
+
+	// A mark of another list, or splicing a list into itself, changes nothing.
+	f := other.PushBack(40)
+	l.SpliceBefore(other, f)
+	l.SpliceAfter(l, a)
+	checkListPointers(t, l, []*Element{a, c, d, b, e})
+	checkListPointers(t, other, []*Element{f})
+}
+
+func TestPushSlice(t *testing.T) {
+	var l List
+	l.PushBackSlice(nil)
+	checkListPointers(t, &l, []*Element{})
+	l.PushBackSlice([]interface{}{3, 4})
+	checkList(t, &l, []interface{}{3, 4})
+	l.PushFrontSlice([]interface{}{1, 2})
+	checkList(t, &l, []interface{}{1, 2, 3, 4})
+	l.PushBackSlice([]interface{}{5})
+	checkList(t, &l, []interface{}{1, 2, 3, 4, 5})
+
+	f := NewWithFreeList(1)
+	f.Remove(f.PushBack(0))
+	f.PushFrontSlice([]interface{}{1, 2})
+	checkList(t, f, []interface{}{1, 2})
+}
+
+func BenchmarkPushBackSlice(b *testing.B) {
+	values := make([]interface{}, 1000)
+	for i := range values {
+		values[i] = i
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var l List
+		l.PushBackSlice(values)
+	}
+}
+
+func TestAt(t *testing.T) {
+	var l List
+	if l.At(0) != nil {
+		t.Error("At(0) of empty list is not nil")
+	}
+	var es []*Element
+	for i := 0; i < 5; i++ {
+		es = append(es, l.PushBack(i))
+	}
+	for i, e := range es {
+		if got := l.At(i); got != e {
+			t.Errorf("At(%d) = %v, want %v", i, got.Value, e.Value)
+		}
+	}
+	if l.At(-1) != nil || l.At(5) != nil {
+		t.Error("At out of range is not nil")
+	}
+}
+
+func TestMoveToIndex(t *testing.T) {
+	const n = 6
+	for from := 0; from < n; from++ {
+		for to := 0; to < n; to++ {
+			l := New()
+			var es []*Element
+			for i := 0; i < n; i++ {
+				es = append(es, l.PushBack(i))
+			}
+			e := es[from]
+			l.MoveToIndex(e, to)
+			want := append(append([]*Element(nil), es[:from]...), es[from+1:]...)
+			want = append(want[:to], append([]*Element{e}, want[to:]...)...)
+			checkListPointers(t, l, want)
+			if t.Failed() {
+				t.Fatalf("MoveToIndex(element %d, %d)", from, to)
+			}
+		}
+	}
+
+	l := New()
+	a := l.PushBack(1)
+	b := l.PushBack(2)
+	l.MoveToIndex(a, 2)
+	l.MoveToIndex(a, -1)
+	l.MoveToIndex(New().PushBack(3), 0)
+	checkListPointers(t, l, []*Element{a, b})
+}
+
+func TestSwap(t *testing.T) {
+	const n = 5
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			l := New()
+			var es []*Element
+			for k := 0; k < n; k++ {
+				es = append(es, l.PushBack(k))
+			}
+			l.Swap(es[i], es[j])
+			want := append([]*Element(nil), es...)
+			want[i], want[j] = want[j], want[i]
+			checkListPointers(t, l, want)
+			if t.Failed() {
+				t.Fatalf("Swap(element %d, element %d)", i, j)
+			}
+		}
+	}
+
+	l := New()
+	a := l.PushBack(1)
+	b := l.PushBack(2)
+	l.Swap(a, New().PushBack(3))
+	checkListPointers(t, l, []*Element{a, b})
+}
+
+func TestBounded(t *testing.T) {
+	l := NewBounded(3, Reject)
+	l.PushBackSlice([]interface{}{1, 2, 3, 4})
+	checkList(t, l, []interface{}{1, 2, 3})
+	if e := l.PushFront(0); e != nil {
+		t.Errorf("PushFront on full list with Reject = %v, want nil", e.Value)
+	}
+	if e := l.InsertAfter(0, l.Front()); e != nil {
+		t.Errorf("InsertAfter on full list with Reject = %v, want nil", e.Value)
+	}
+	l.Remove(l.Front())
+	if e := l.PushFront(0); e == nil {
+		t.Error("PushFront on list with room = nil")
+	}
+	checkList(t, l, []interface{}{0, 2, 3})
+
+	// DropOldest makes a ring buffer of the last values pushed.
+	r := NewBounded(3, DropOldest)
+	for i := 1; i <= 5; i++ {
+		r.PushBack(i)
+	}
+	checkList(t, r, []interface{}{3, 4, 5})
+	// Inserting after the element that is dropped.
+	r.InsertAfter(35, r.Front())
+	checkList(t, r, []interface{}{35, 4, 5})
+	r.PushFront(0)
+	checkList(t, r, []interface{}{0, 4, 5})
+
+	d := NewBounded(3, DropNewest)
+	d.PushBackSlice([]interface{}{1, 2, 3})
+	d.PushFront(0)
+	checkList(t, d, []interface{}{0, 1, 2})
+	d.InsertBefore(15, d.Back())
+	checkList(t, d, []interface{}{0, 1, 15})
+	d.PushBack(4)
+	checkList(t, d, []interface{}{0, 1, 4})
+}
+
+func TestBoundedBulk(t *testing.T) {
+	// Copying a full list into itself must not follow dropped elements.
+	l := NewBounded(4, DropOldest)
+	l.PushBackSlice([]interface{}{1, 2, 3, 4})
+	l.PushBackList(l)
+	checkList(t, l, []interface{}{1, 2, 3, 4})
+	// At the front, each value replaces the one inserted before it.
+	l.PushFrontList(l)
+	checkList(t, l, []interface{}{4, 2, 3, 4})
+	l.InsertListAfter(l, l.Front())
+	checkList(t, l, []interface{}{4, 2, 3, 4})
+
+	other := New()
+	other.PushBackSlice([]interface{}{5, 6})
+	l.PushBackList(other)
+	checkList(t, l, []interface{}{3, 4, 5, 6})
+
+	// Splicing into a bounded list moves elements one at a time.
+	src := New()
+	a := src.PushBack(7)
+	b := src.PushBack(8)
+	c := src.PushBack(9)
+	r := NewBounded(3, Reject)
+	r.PushBack(0)
+	r.SpliceBack(src)
+	checkList(t, r, []interface{}{0, 7, 8})
+	checkListPointers(t, src, []*Element{c})
+	if a.list != r || b.list != r {
+		t.Error("spliced elements do not belong to the bounded list")
+	}
+
+	s := NewBounded(2, DropOldest)
+	s.PushBackSlice([]interface{}{1, 2})
+	s.SpliceFront(src)
+	checkListPointers(t, s, []*Element{c, s.Back()})
+	checkList(t, s, []interface{}{9, 2})
+	checkListPointers(t, src, []*Element{})
+
+	if cl := r.Clone(nil); cl.PushBack(1) != nil {
+		t.Error("Clone of full Reject list accepted a value")
+	}
+}
+
+func TestNewBoundedPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewBounded(0, Reject) did not panic")
+		}
+	}()
+	NewBounded(0, Reject)
+}
+
+func TestSplit(t *testing.T) {
+	for n := 1; n <= 4; n++ {
+		for i := 0; i < n; i++ {
+			l := New()
+			var es []*Element
+			for k := 0; k < n; k++ {
+				es = append(es, l.PushBack(k))
+			}
+			s := l.Split(es[i])
+			checkListPointers(t, l, es[:i])
+			checkListPointers(t, s, es[i:])
+			if t.Failed() {
+				t.Fatalf("Split of %d elements at %d", n, i)
+			}
+			// Both lists remain usable.
+			l.PushBack("l")
+			s.PushFront("s")
+			if l.Len() != i+1 || s.Len() != n-i+1 || s.Front().Value != "s" || l.Back().Value != "l" {
+				t.Fatalf("lists unusable after Split of %d elements at %d", n, i)
+			}
+		}
+	}
+
+	l := New()
+	l.PushBack(1)
+	if s := l.Split(New().PushBack(2)); s != nil {
+		t.Error("Split at element of another list is not nil")
+	}
+	checkList(t, l, []interface{}{1})
+}
+
+func TestAdopt(t *testing.T) {
+	var l, other List
+	a := l.PushBack(1)
+	b := other.PushBack(2)
+	c := other.PushBack(3)
+
+	if got := l.Adopt(c); got != c {
+		t.Errorf("Adopt(c) = %v, want c", got)
+	}
+	checkListPointers(t, &l, []*Element{a, c})
+	checkListPointers(t, &other, []*Element{b})
+	l.MoveToFront(c)
+	checkListPointers(t, &l, []*Element{c, a})
+
+	// Adopting an own element or a removed one changes nothing.
+	if got := l.Adopt(a); got != a {
+		t.Errorf("Adopt of own element = %v, want it", got)
+	}
+	other.Remove(b)
+	if got := l.Adopt(b); got != nil {
+		t.Errorf("Adopt of removed element = %v, want nil", got)
+	}
+	checkListPointers(t, &l, []*Element{c, a})
+
+	// Into the zero value of a list.
+	var z List
+	z.Adopt(a)
+	checkListPointers(t, &z, []*Element{a})
+	checkListPointers(t, &l, []*Element{c})
+
+	// A full bounded list applies its policy.
+	r := NewBounded(1, Reject)
+	r.PushBack(0)
+	if got := r.Adopt(c); got != nil {
+		t.Errorf("Adopt into full Reject list = %v, want nil", got)
+	}
+	checkListPointers(t, &l, []*Element{c})
+	d := NewBounded(1, DropOldest)
+	d.PushBack(0)
+	d.Adopt(c)
+	checkListPointers(t, d, []*Element{c})
+	checkListPointers(t, &l, []*Element{})
+}
+
+func TestForEachReverse(t *testing.T) {
+	l := New()
+	for i := 1; i <= 6; i++ {
+		l.PushBack(i)
+	}
+	var seen []interface{}
+	l.ForEachReverse(func(e *Element) bool {
+		seen = append(seen, e.Value)
+		switch e.Value.(int) {
+		case 5, 4:
+			l.Remove(e)
+		case 3:
+			l.InsertBefore(30, e)
+		case 2:
+			return false
+		}
+		return true
+	})
+	if want := []interface{}{6, 5, 4, 3, 30, 2}; !equalValues(seen, want) {
+		t.Errorf("ForEachReverse visited %v, want %v", seen, want)
+	}
+	checkList(t, l, []interface{}{1, 2, 30, 3, 6})
+
+	// An element inserted before e is visited even if f then removes e.
+	seen = nil
+	l.ForEachReverse(func(e *Element) bool {
+		seen = append(seen, e.Value)
+		if e.Value == 3 {
+			l.InsertBefore(31, e)
+			l.Remove(e)
+		}
+		return true
+	})
+	if want := []interface{}{6, 3, 31, 30, 2, 1}; !equalValues(seen, want) {
+		t.Errorf("ForEachReverse visited %v, want %v", seen, want)
+	}
+	checkList(t, l, []interface{}{1, 2, 30, 31, 6})
+
+	var z List
+	z.ForEachReverse(func(e *Element) bool {
+		t.Error("ForEachReverse of empty list called f")
+		return true
+	})
+}
+
+func TestInsertSorted(t *testing.T) {
+	type item struct{ key, seq int }
+	less := func(a, b interface{}) bool { return a.(item).key < b.(item).key }
+	var l List
+	for i, k := range []int{5, 1, 3, 5, 0, 9, 3} {
+		if e := l.InsertSorted(item{k, i}, less); e == nil || e.Value != (item{k, i}) {
+			t.Fatalf("InsertSorted(%d) returned wrong element", k)
+		}
+	}
+	var got []interface{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		got = append(got, e.Value)
+	}
+	want := []interface{}{item{0, 4}, item{1, 1}, item{3, 2}, item{3, 6}, item{5, 0}, item{5, 3}, item{9, 5}}
+	if !equalValues(got, want) {
+		t.Errorf("list = %v, want %v", got, want)
+	}
+
+	r := NewBounded(2, Reject)
+	r.InsertSorted(item{1, 0}, less)
+	r.InsertSorted(item{2, 0}, less)
+	if e := r.InsertSorted(item{0, 0}, less); e != nil {
+		t.Error("InsertSorted into full Reject list is not nil")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	const n = 5
+	for k := -12; k <= 12; k++ {
+		l := New()
+		var es []*Element
+		for i := 0; i < n; i++ {
+			es = append(es, l.PushBack(i))
+		}
+		l.RotateForward(k)
+		m := ((k % n) + n) % n
+		want := append(append([]*Element(nil), es[m:]...), es[:m]...)
+		checkListPointers(t, l, want)
+		l.RotateBackward(k)
+		checkListPointers(t, l, es)
+		if t.Failed() {
+			t.Fatalf("rotation by %d", k)
+		}
+	}
+
+	var z List
+	z.RotateForward(3)
+	z.RotateBackward(3)
+	checkListPointers(t, &z, []*Element{})
+	a := z.PushBack(1)
+	z.RotateForward(1)
+	checkListPointers(t, &z, []*Element{a})
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orderedmap_test
+
+import (
+	"container/orderedmap"
+	"fmt"
+)
+
+func Example() {
+	m := orderedmap.New()
+	m.Set("name", "gopher")
+	m.Set("age", 11)
+	m.Set("lang", "go")
+	m.Set("age", 12) // keeps its place
+	m.Delete("lang")
+
+	m.Range(func(key, value interface{}) bool {
+		fmt.Println(key, value)
+		return true
+	})
+
+	// Output:
+	// name gopher
+	// age 12
+}
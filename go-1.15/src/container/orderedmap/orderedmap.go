@@ -0,0 +1,85 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package orderedmap implements a map that remembers the order in which
+// its keys were first set, such as the fields of a JSON object or the
+// entries of a configuration file.
+//
+// To iterate over a map (where m is a *Map):
+//	m.Range(func(key, value interface{}) bool {
+//		// do something with key and value
+//		return true
+//	})
+//
+package orderedmap
+
+import "container/list"
+
+// A Map is a map whose keys are iterated in insertion order. Keys must be
+// comparable, as for the built-in map. Get, Set and Delete take O(1) time.
+// The zero value for Map is an empty map ready to use.
+// A Map is not safe for concurrent use by multiple goroutines.
+type Map struct {
+	m map[interface{}]*list.Element // of *entry
+	l list.List
+}
+
+type entry struct {
+	key, value interface{}
+}
+
+// New returns an initialized map.
+func New() *Map { return new(Map) }
+
+// Len returns the number of keys in m.
+func (m *Map) Len() int { return len(m.m) }
+
+// Get returns the value of key in m and whether key is present.
+func (m *Map) Get(key interface{}) (value interface{}, ok bool) {
+	if e, ok := m.m[key]; ok {
+		return e.Value.(*entry).value, true
+	}
+	return nil, false
+}
+
+// Set sets the value of key in m to value. A new key is placed after all
+// the others; a key that was already present keeps its place, and Set
+// returns its previous value and true.
+func (m *Map) Set(key, value interface{}) (old interface{}, replaced bool) {
+	if e, ok := m.m[key]; ok {
+		en := e.Value.(*entry)
+		old, en.value = en.value, value
+		return old, true
+	}
+	if m.m == nil {
+		m.m = make(map[interface{}]*list.Element)
+	}
+	m.m[key] = m.l.PushBack(&entry{key, value})
+	return nil, false
+}
+
+// Delete removes key from m. If key was present, Delete returns its value
+// and true.
+func (m *Map) Delete(key interface{}) (value interface{}, ok bool) {
+	e, ok := m.m[key]
+	if !ok {
+		return nil, false
+	}
+	delete(m.m, key)
+	return m.l.Remove(e).(*entry).value, true
+}
+
+// Range calls f for each key and value of m in insertion order, until f
+// returns false. f may delete the key it is called for; the behavior of
+// Range is undefined if f otherwise modifies m.
+func (m *Map) Range(f func(key, value interface{}) bool) {
+	for e := m.l.Front(); e != nil; {
+		next := e.Next()
+		en := e.Value.(*entry)
+		if !f(en.key, en.value) {
+			return
+		}
+		e = next
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orderedmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+// checkMap checks that m holds keys with the values "v"+key, in order.
+func checkMap(t *testing.T, m *Map, keys ...string) {
+	t.Helper()
+	if m.Len() != len(keys) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(keys))
+	}
+	var got []string
+	m.Range(func(key, value interface{}) bool {
+		got = append(got, key.(string))
+		if value != "v"+key.(string) {
+			t.Errorf("value of %v = %v, want %q", key, value, "v"+key.(string))
+		}
+		return true
+	})
+	if fmt.Sprint(got) != fmt.Sprint(keys) {
+		t.Errorf("keys = %v, want %v", got, keys)
+	}
+}
+
+func TestMap(t *testing.T) {
+	var m Map
+	checkMap(t, &m)
+	if v, ok := m.Get("a"); ok {
+		t.Errorf("Get on empty map = %v, true", v)
+	}
+	if v, ok := m.Delete("a"); ok {
+		t.Errorf("Delete on empty map = %v, true", v)
+	}
+
+	for _, k := range []string{"c", "a", "d", "b"} {
+		if _, replaced := m.Set(k, "v"+k); replaced {
+			t.Errorf("Set(%q) replaced a value", k)
+		}
+	}
+	checkMap(t, &m, "c", "a", "d", "b")
+
+	m.Set("a", "x")
+	if old, replaced := m.Set("a", "va"); !replaced || old != "x" {
+		t.Errorf("Set(a) = %v, %v want x, true", old, replaced)
+	}
+	checkMap(t, &m, "c", "a", "d", "b")
+	if v, ok := m.Get("d"); !ok || v != "vd" {
+		t.Errorf("Get(d) = %v, %v want vd, true", v, ok)
+	}
+
+	if v, ok := m.Delete("a"); !ok || v != "va" {
+		t.Errorf("Delete(a) = %v, %v want va, true", v, ok)
+	}
+	checkMap(t, &m, "c", "d", "b")
+	if _, ok := m.Get("a"); ok {
+		t.Errorf("Get(a) found a deleted key")
+	}
+
+	// A deleted key goes to the back when set again.
+	m.Set("a", "va")
+	checkMap(t, &m, "c", "d", "b", "a")
+}
+
+func TestRangeDelete(t *testing.T) {
+	m := New()
+	for _, k := range []string{"a", "b", "c", "d"} {
+		m.Set(k, "v"+k)
+	}
+	n := 0
+	m.Range(func(key, value interface{}) bool {
+		n++
+		if key != "c" {
+			m.Delete(key)
+		}
+		return true
+	})
+	if n != 4 {
+		t.Errorf("Range called f %d times, want 4", n)
+	}
+	checkMap(t, m, "c")
+
+	n = 0
+	m.Set("e", "ve")
+	m.Range(func(key, value interface{}) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Range called f %d times after false, want 1", n)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package radix_test
+
+import (
+	"container/radix"
+	"fmt"
+)
+
+func ExampleTree_LongestPrefix() {
+	routes := radix.New()
+	routes.Put("/", "index")
+	routes.Put("/api/", "api")
+	routes.Put("/api/users/", "users")
+
+	for _, path := range []string{"/api/users/42", "/api/groups", "/about"} {
+		prefix, handler, _ := routes.LongestPrefix(path)
+		fmt.Println(path, "->", prefix, handler)
+	}
+
+	// Output:
+	// /api/users/42 -> /api/users/ users
+	// /api/groups -> /api/ api
+	// /about -> / index
+}
@@ -0,0 +1,277 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package radix implements a radix tree, a map from strings to values that
+// shares the storage of common key prefixes and supports queries by
+// prefix, such as the longest key that is a prefix of a path in a routing
+// table. Keys are ordered bytewise, as strings compare in Go; byte slices
+// can be used as keys by converting them to strings.
+package radix
+
+// A Tree is a radix tree.
+// The zero value for Tree is an empty tree ready to use.
+// A Tree is not safe for concurrent use by multiple goroutines.
+type Tree struct {
+	root   node
+	length int
+}
+
+// A node is a node of the tree. Its path, the concatenation of the labels
+// of the edges from the root, is a key of the tree if leaf is set. A node
+// other than the root has at least two children or is a leaf.
+type node struct {
+	path     string // the key of the node
+	label    string // the suffix of path below the parent
+	leaf     bool   // path is a key
+	value    interface{}
+	children []*node // sorted by the first byte of their labels
+}
+
+// New returns an empty tree.
+func New() *Tree { return new(Tree) }
+
+// Len returns the number of keys in t.
+func (t *Tree) Len() int { return t.length }
+
+// Get returns the value of key in t and whether key is present.
+func (t *Tree) Get(key string) (value interface{}, ok bool) {
+	n := &t.root
+	search := key
+	for search != "" {
+		_, c := n.child(search[0])
+		if c == nil || !hasPrefix(search, c.label) {
+			return nil, false
+		}
+		search = search[len(c.label):]
+		n = c
+	}
+	if !n.leaf {
+		return nil, false
+	}
+	return n.value, true
+}
+
+// Put sets the value of key in t to value. If key was already present,
+// Put returns its previous value and true.
+func (t *Tree) Put(key string, value interface{}) (old interface{}, replaced bool) {
+	n := &t.root
+	search := key
+	for search != "" {
+		i, c := n.child(search[0])
+		if c == nil {
+			n.insertChild(i, &node{path: key, label: search, leaf: true, value: value})
+			t.length++
+			return nil, false
+		}
+		l := commonPrefixLen(search, c.label)
+		if l < len(c.label) {
+			// Split the edge to c at the end of the common prefix.
+			mid := &node{
+				path:     c.path[:len(c.path)-len(c.label)+l],
+				label:    c.label[:l],
+				children: []*node{c},
+			}
+			c.label = c.label[l:]
+			n.children[i] = mid
+			c = mid
+		}
+		search = search[l:]
+		n = c
+	}
+	if n.leaf {
+		old = n.value
+		n.value = value
+		return old, true
+	}
+	n.leaf = true
+	n.value = value
+	t.length++
+	return nil, false
+}
+
+// Delete removes key from t. If key was present, Delete returns its value
+// and true.
+func (t *Tree) Delete(key string) (value interface{}, ok bool) {
+	var parent *node
+	parentIndex := 0
+	n := &t.root
+	search := key
+	for search != "" {
+		i, c := n.child(search[0])
+		if c == nil || !hasPrefix(search, c.label) {
+			return nil, false
+		}
+		parent, parentIndex = n, i
+		search = search[len(c.label):]
+		n = c
+	}
+	if !n.leaf {
+		return nil, false
+	}
+	value = n.value
+	n.leaf = false
+	n.value = nil
+	t.length--
+
+	if n == &t.root {
+		return value, true
+	}
+	switch len(n.children) {
+	case 0:
+		parent.removeChild(parentIndex)
+		if parent != &t.root && !parent.leaf && len(parent.children) == 1 {
+			parent.mergeChild()
+		}
+	case 1:
+		n.mergeChild()
+	}
+	return value, true
+}
+
+// LongestPrefix returns the longest key in t that is a prefix of s, with
+// its value. ok reports whether there is such a key.
+func (t *Tree) LongestPrefix(s string) (key string, value interface{}, ok bool) {
+	n := &t.root
+	search := s
+	for {
+		if n.leaf {
+			key, value, ok = n.path, n.value, true
+		}
+		if search == "" {
+			return
+		}
+		_, c := n.child(search[0])
+		if c == nil || !hasPrefix(search, c.label) {
+			return
+		}
+		search = search[len(c.label):]
+		n = c
+	}
+}
+
+// WalkPrefix calls f for each key in t that starts with prefix, with its
+// value, in ascending key order, until f returns false.
+// t must not be modified during the iteration.
+func (t *Tree) WalkPrefix(prefix string, f func(key string, value interface{}) bool) {
+	n := &t.root
+	search := prefix
+	for search != "" {
+		_, c := n.child(search[0])
+		if c == nil {
+			return
+		}
+		if hasPrefix(c.label, search) {
+			// All keys below c start with prefix.
+			n = c
+			break
+		}
+		if !hasPrefix(search, c.label) {
+			return
+		}
+		search = search[len(c.label):]
+		n = c
+	}
+	n.walk(f)
+}
+
+// Ascend calls f for each key and value of t in ascending key order,
+// until f returns false. t must not be modified during the iteration.
+func (t *Tree) Ascend(f func(key string, value interface{}) bool) {
+	t.root.walk(f)
+}
+
+// AscendRange calls f for each key and value of t with from <= key < to
+// in ascending key order, until f returns false. t must not be modified
+// during the iteration.
+func (t *Tree) AscendRange(from, to string, f func(key string, value interface{}) bool) {
+	t.root.walkRange(from, to, f)
+}
+
+// walk calls f for the keys of the subtree n in order, and reports
+// whether the iteration should continue.
+func (n *node) walk(f func(key string, value interface{}) bool) bool {
+	if n.leaf && !f(n.path, n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.walk(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkRange is like walk for the keys in [from, to).
+func (n *node) walkRange(from, to string, f func(key string, value interface{}) bool) bool {
+	if n.path >= to {
+		// So are all the keys that follow.
+		return false
+	}
+	if n.path < from && !hasPrefix(from, n.path) {
+		// So are all the keys below n.
+		return true
+	}
+	if n.leaf && n.path >= from && !f(n.path, n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !c.walkRange(from, to, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// child returns the child of n whose label starts with b, or nil and the
+// index at which to insert such a child.
+func (n *node) child(b byte) (int, *node) {
+	// Binary search, as in sort.Search.
+	i, j := 0, len(n.children)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if n.children[h].label[0] < b {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < len(n.children) && n.children[i].label[0] == b {
+		return i, n.children[i]
+	}
+	return i, nil
+}
+
+func (n *node) insertChild(i int, c *node) {
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+func (n *node) removeChild(i int) {
+	copy(n.children[i:], n.children[i+1:])
+	n.children[len(n.children)-1] = nil
+	n.children = n.children[:len(n.children)-1]
+}
+
+// mergeChild merges the only child of n into n, which is not a leaf.
+func (n *node) mergeChild() {
+	c := n.children[0]
+	n.label = c.path[len(c.path)-len(n.label)-len(c.label):]
+	n.path = c.path
+	n.leaf = c.leaf
+	n.value = c.value
+	n.children = c.children
+}
+
+func hasPrefix(s, prefix string) bool {
+	return len(s) >= len(prefix) && s[:len(prefix)] == prefix
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
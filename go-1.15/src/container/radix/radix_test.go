@@ -0,0 +1,176 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package radix
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// checkTree checks the invariants of t and that it holds exactly the
+// keys of want, with value "v"+key.
+func checkTree(t *testing.T, tr *Tree, want map[string]bool) {
+	t.Helper()
+	if tr.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", tr.Len(), len(want))
+	}
+	checkNode(t, &tr.root, true)
+	var keys []string
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	i := 0
+	tr.Ascend(func(key string, value interface{}) bool {
+		if i >= len(keys) || key != keys[i] || value != "v"+key {
+			t.Fatalf("Ascend item %d = %q, %v", i, key, value)
+		}
+		i++
+		return true
+	})
+	if i != len(keys) {
+		t.Fatalf("Ascend visited %d items, want %d", i, len(keys))
+	}
+}
+
+func checkNode(t *testing.T, n *node, root bool) {
+	if !root && !n.leaf && len(n.children) < 2 {
+		t.Fatalf("node %q is not a leaf and has %d children", n.path, len(n.children))
+	}
+	for i, c := range n.children {
+		if c.label == "" || c.path != n.path+c.label {
+			t.Fatalf("child %q, %q of %q", c.path, c.label, n.path)
+		}
+		if i > 0 && n.children[i-1].label[0] >= c.label[0] {
+			t.Fatalf("unordered children of %q", n.path)
+		}
+		checkNode(t, c, false)
+	}
+}
+
+func TestTree(t *testing.T) {
+	var tr Tree
+	want := make(map[string]bool)
+	r := rand.New(rand.NewSource(1))
+	randKey := func() string {
+		// Short keys over a small alphabet share many prefixes.
+		b := make([]byte, r.Intn(6))
+		for i := range b {
+			b[i] = "abc"[r.Intn(3)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 5000; i++ {
+		k := randKey()
+		if r.Intn(3) == 0 {
+			v, ok := tr.Delete(k)
+			if ok != want[k] || ok && v != "v"+k {
+				t.Fatalf("Delete(%q) = %v, %v want %v", k, v, ok, want[k])
+			}
+			delete(want, k)
+		} else {
+			old, replaced := tr.Put(k, "v"+k)
+			if replaced != want[k] || replaced && old != "v"+k {
+				t.Fatalf("Put(%q) = %v, %v want %v", k, old, replaced, want[k])
+			}
+			want[k] = true
+		}
+		if i%100 == 0 {
+			checkTree(t, &tr, want)
+		}
+		k = randKey()
+		if v, ok := tr.Get(k); ok != want[k] || ok && v != "v"+k {
+			t.Fatalf("Get(%q) = %v, %v want %v", k, v, ok, want[k])
+		}
+	}
+	checkTree(t, &tr, want)
+	for k := range want {
+		tr.Delete(k)
+		delete(want, k)
+		checkTree(t, &tr, want)
+	}
+}
+
+func newTree(keys ...string) *Tree {
+	t := New()
+	for _, k := range keys {
+		t.Put(k, "v"+k)
+	}
+	return t
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tr := newTree("", "/api", "/api/v1", "/api/v1/users", "/static")
+	for _, test := range []struct {
+		s, key string
+	}{
+		{"/", ""},
+		{"/api", "/api"},
+		{"/apix", "/api"},
+		{"/api/v1/user", "/api/v1"},
+		{"/api/v1/users/42", "/api/v1/users"},
+		{"/static/css", "/static"},
+	} {
+		key, value, ok := tr.LongestPrefix(test.s)
+		if !ok || key != test.key || value != "v"+test.key {
+			t.Errorf("LongestPrefix(%q) = %q, %v, %v; want %q", test.s, key, value, ok, test.key)
+		}
+	}
+
+	tr.Delete("")
+	if key, _, ok := tr.LongestPrefix("/index"); ok {
+		t.Errorf("LongestPrefix(/index) = %q, want none", key)
+	}
+}
+
+func collect(walk func(func(string, interface{}) bool)) string {
+	var keys []string
+	walk(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return strings.Join(keys, " ")
+}
+
+func TestWalkPrefix(t *testing.T) {
+	tr := newTree("romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus")
+	for _, test := range []struct {
+		prefix, keys string
+	}{
+		{"", "romane romanus romulus rubens ruber rubicon rubicundus"},
+		{"r", "romane romanus romulus rubens ruber rubicon rubicundus"},
+		{"rom", "romane romanus romulus"},
+		{"roma", "romane romanus"},
+		{"rube", "rubens ruber"},
+		{"rubicon", "rubicon"},
+		{"rubicons", ""},
+		{"x", ""},
+	} {
+		got := collect(func(f func(string, interface{}) bool) { tr.WalkPrefix(test.prefix, f) })
+		if got != test.keys {
+			t.Errorf("WalkPrefix(%q) visited %q, want %q", test.prefix, got, test.keys)
+		}
+	}
+}
+
+func TestAscendRange(t *testing.T) {
+	tr := newTree("a", "ab", "abc", "b", "ba", "c")
+	for _, test := range []struct {
+		from, to, keys string
+	}{
+		{"", "z", "a ab abc b ba c"},
+		{"ab", "b", "ab abc"},
+		{"aa", "bb", "ab abc b ba"},
+		{"abd", "c", "b ba"},
+		{"c", "c", ""},
+	} {
+		got := collect(func(f func(string, interface{}) bool) { tr.AscendRange(test.from, test.to, f) })
+		if got != test.keys {
+			t.Errorf("AscendRange(%q, %q) visited %q, want %q", test.from, test.to, got, test.keys)
+		}
+	}
+}
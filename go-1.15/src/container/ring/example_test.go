@@ -100,6 +100,27 @@ func ExampleRing_Do() {
 	// 4
 }
 
+func ExampleRing_DoIndexed() {
+	// Create a new ring of size 3
+	r := ring.New(3)
+
+	// Initialize the ring with some string values
+	for _, s := range []string{"a", "b", "c"} {
+		r.Value = s
+		r = r.Next()
+	}
+
+	// Iterate through the ring and print its contents with their positions
+	r.DoIndexed(func(i int, p interface{}) {
+		fmt.Println(i, p.(string))
+	})
+
+	// Output:
+	// 0 a
+	// 1 b
+	// 2 c
+}
+
 func ExampleRing_Move() {
 	// Create a new ring of size 5
 	r := ring.New(5)
@@ -9,12 +9,15 @@ package ring
 //
 type Ring struct {
 	next, prev *Ring
+	len        *int        // number of elements, shared by the ring; 0 once Link changes the ring
 	Value      interface{} // for use by client; untouched by this library
 }
 
 func (r *Ring) init() *Ring {
 	r.next = r
 	r.prev = r
+	r.len = new(int)
+	*r.len = 1
 	return r
 }
 
@@ -59,10 +62,12 @@ func New(n int) *Ring {
 	if n <= 0 {
 		return nil
 	}
-	r := new(Ring)
+	size := new(int)
+	*size = n
+	r := &Ring{len: size}
 	p := r
 	for i := 1; i < n; i++ {
-		p.next = &Ring{prev: p}
+		p.next = &Ring{prev: p, len: size}
 		p = p.next
 	}
 	p.next = r
@@ -90,6 +95,9 @@ func (r *Ring) Link(s *Ring) *Ring {
 	n := r.Next()
 	if s != nil {
 		p := s.Prev()
+		// The lengths of the rings change; Len counts them again.
+		*r.len = 0
+		*s.len = 0
 		// Note: Cannot use multiple assignment because
 		// evaluation order of LHS is not specified.
 		r.next = s
@@ -112,17 +120,29 @@ func (r *Ring) Unlink(n int) *Ring {
 }
 
 // Len computes the number of elements in ring r.
-// It executes in time proportional to the number of elements.
+// It executes in constant time, unless the ring was changed by Link
+// or Unlink since Len was last called on it; then it executes in time
+// proportional to the number of elements.
 //
 func (r *Ring) Len() int {
-	n := 0
-	if r != nil {
-		n = 1
-		for p := r.Next(); p != r; p = p.next {
-			n++
+	if r == nil {
+		return 0
+	}
+	if r.next == nil {
+		r.init()
+	}
+	if *r.len == 0 {
+		// Elements of a ring that Link split may share a count with
+		// the elements of the other part, so give the ring its own.
+		size := new(int)
+		r.len = size
+		*size = 1
+		for p := r.next; p != r; p = p.next {
+			p.len = size
+			*size++
 		}
 	}
-	return n
+	return *r.len
 }
 
 // Do calls function f on each element of the ring, in forward order.
@@ -135,3 +155,17 @@ func (r *Ring) Do(f func(interface{})) {
 		}
 	}
 }
+
+// DoIndexed calls function f on each element of the ring, in forward
+// order, with the element's position relative to r, starting at 0.
+// The behavior of DoIndexed is undefined if f changes *r.
+func (r *Ring) DoIndexed(f func(i int, value interface{})) {
+	if r != nil {
+		f(0, r.Value)
+		i := 1
+		for p := r.Next(); p != r; p = p.next {
+			f(i, p.Value)
+			i++
+		}
+	}
+}
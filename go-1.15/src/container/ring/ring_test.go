@@ -57,6 +57,9 @@ func verify(t *testing.T, r *Ring, N int, sum int) {
 			if p != nil && p != q.prev {
 				t.Errorf("prev = %p, expected q.prev = %p\n", p, q.prev)
 			}
+			if q.len != r.len {
+				t.Errorf("len = %p, expected r.len = %p\n", q.len, r.len)
+			}
 			p = q
 		}
 		if p != r.prev {
@@ -226,3 +229,26 @@ func TestMoveEmptyRing(t *testing.T) {
 	r.Move(1)
 	verify(t, &r, 1, 0)
 }
+
+func TestDoIndexed(t *testing.T) {
+	var r *Ring
+	r.DoIndexed(func(i int, v interface{}) {
+		t.Errorf("DoIndexed on empty ring called f(%d, %v)", i, v)
+	})
+
+	r = makeN(10)
+	r = r.Move(3)
+	n := 0
+	r.DoIndexed(func(i int, v interface{}) {
+		if i != n {
+			t.Errorf("DoIndexed index = %d, want %d", i, n)
+		}
+		if want := (i+3)%10 + 1; v.(int) != want {
+			t.Errorf("DoIndexed value at %d = %v, want %d", i, v, want)
+		}
+		n++
+	})
+	if n != 10 {
+		t.Errorf("DoIndexed called f %d times, want 10", n)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set_test
+
+import (
+	"container/set"
+	"fmt"
+)
+
+func ExampleOrdered() {
+	less := func(a, b interface{}) bool { return a.(string) < b.(string) }
+	admins := set.NewOrdered(less, "root", "alice")
+	users := set.NewOrdered(less, "bob", "alice", "carol")
+
+	users.Union(admins).Difference(set.NewOrdered(less, "root")).Ascend(func(e interface{}) bool {
+		fmt.Println(e)
+		return true
+	})
+
+	// Output:
+	// alice
+	// bob
+	// carol
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import "container/btree"
+
+// An Ordered is a set whose elements are kept in the order defined by a
+// less function. Add, Remove and Contains take O(log n) time.
+// An Ordered must be created with NewOrdered.
+// It is not safe for concurrent use by multiple goroutines.
+type Ordered struct {
+	less func(a, b interface{}) bool
+	t    *btree.BTree
+}
+
+// NewOrdered returns an ordered set of the given elements, ordered by
+// less, which reports whether element a sorts before element b. less must
+// define a strict weak ordering; elements for which neither sorts before
+// the other are the same element.
+func NewOrdered(less func(a, b interface{}) bool, elems ...interface{}) *Ordered {
+	s := &Ordered{less: less, t: btree.New(less)}
+	for _, e := range elems {
+		s.t.Put(e, nil)
+	}
+	return s
+}
+
+// Len returns the number of elements of s.
+func (s *Ordered) Len() int { return s.t.Len() }
+
+// Add adds elem to s and reports whether it was not already present.
+func (s *Ordered) Add(elem interface{}) bool {
+	_, replaced := s.t.Put(elem, nil)
+	return !replaced
+}
+
+// Remove removes elem from s and reports whether it was present.
+func (s *Ordered) Remove(elem interface{}) bool {
+	_, ok := s.t.Delete(elem)
+	return ok
+}
+
+// Contains reports whether elem is an element of s.
+func (s *Ordered) Contains(elem interface{}) bool {
+	_, ok := s.t.Get(elem)
+	return ok
+}
+
+// Ascend calls f for each element of s in ascending order, until f
+// returns false. s must not be modified during the iteration.
+func (s *Ordered) Ascend(f func(elem interface{}) bool) {
+	s.t.Ascend(func(key, _ interface{}) bool { return f(key) })
+}
+
+// AscendRange calls f for each element of s with from <= elem < to in
+// ascending order, until f returns false. s must not be modified during
+// the iteration.
+func (s *Ordered) AscendRange(from, to interface{}, f func(elem interface{}) bool) {
+	s.t.AscendRange(from, to, func(key, _ interface{}) bool { return f(key) })
+}
+
+// Union returns a new set of the elements of s or t, ordered as s.
+func (s *Ordered) Union(t *Ordered) *Ordered {
+	u := NewOrdered(s.less)
+	s.Ascend(func(e interface{}) bool { u.Add(e); return true })
+	t.Ascend(func(e interface{}) bool { u.Add(e); return true })
+	return u
+}
+
+// Intersect returns a new set of the elements of both s and t, ordered
+// as s.
+func (s *Ordered) Intersect(t *Ordered) *Ordered {
+	u := NewOrdered(s.less)
+	s.Ascend(func(e interface{}) bool {
+		if t.Contains(e) {
+			u.Add(e)
+		}
+		return true
+	})
+	return u
+}
+
+// Difference returns a new set of the elements of s that are not in t,
+// ordered as s.
+func (s *Ordered) Difference(t *Ordered) *Ordered {
+	u := NewOrdered(s.less)
+	s.Ascend(func(e interface{}) bool {
+		if !t.Contains(e) {
+			u.Add(e)
+		}
+		return true
+	})
+	return u
+}
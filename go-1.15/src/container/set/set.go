@@ -0,0 +1,111 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package set implements sets: Set, an unordered set of comparable
+// elements backed by a map, and Ordered, a set that keeps its elements in
+// order, backed by a B-tree.
+//
+// To iterate over a set (where s is a *Set):
+//	s.Range(func(elem interface{}) bool {
+//		// do something with elem
+//		return true
+//	})
+//
+package set
+
+// A Set is an unordered set of elements. Elements must be comparable, as
+// for the keys of the built-in map. Add, Remove and Contains take O(1)
+// time. The zero value for Set is an empty set ready to use.
+// A Set is not safe for concurrent use by multiple goroutines.
+type Set struct {
+	m map[interface{}]struct{}
+}
+
+// New returns a set of the given elements.
+func New(elems ...interface{}) *Set {
+	s := &Set{m: make(map[interface{}]struct{}, len(elems))}
+	for _, e := range elems {
+		s.m[e] = struct{}{}
+	}
+	return s
+}
+
+// Len returns the number of elements of s.
+func (s *Set) Len() int { return len(s.m) }
+
+// Add adds elem to s and reports whether it was not already present.
+func (s *Set) Add(elem interface{}) bool {
+	if _, ok := s.m[elem]; ok {
+		return false
+	}
+	if s.m == nil {
+		s.m = make(map[interface{}]struct{})
+	}
+	s.m[elem] = struct{}{}
+	return true
+}
+
+// Remove removes elem from s and reports whether it was present.
+func (s *Set) Remove(elem interface{}) bool {
+	if _, ok := s.m[elem]; !ok {
+		return false
+	}
+	delete(s.m, elem)
+	return true
+}
+
+// Contains reports whether elem is an element of s.
+func (s *Set) Contains(elem interface{}) bool {
+	_, ok := s.m[elem]
+	return ok
+}
+
+// Range calls f for each element of s, in no particular order, until f
+// returns false. f may remove the element it is called for; the behavior
+// of Range is undefined if f otherwise modifies s.
+func (s *Set) Range(f func(elem interface{}) bool) {
+	for e := range s.m {
+		if !f(e) {
+			return
+		}
+	}
+}
+
+// Union returns a new set of the elements of s or t.
+func (s *Set) Union(t *Set) *Set {
+	u := &Set{m: make(map[interface{}]struct{}, len(s.m)+len(t.m))}
+	for e := range s.m {
+		u.m[e] = struct{}{}
+	}
+	for e := range t.m {
+		u.m[e] = struct{}{}
+	}
+	return u
+}
+
+// Intersect returns a new set of the elements of both s and t.
+func (s *Set) Intersect(t *Set) *Set {
+	small, large := s, t
+	if len(small.m) > len(large.m) {
+		small, large = large, small
+	}
+	u := new(Set)
+	for e := range small.m {
+		if large.Contains(e) {
+			u.Add(e)
+		}
+	}
+	return u
+}
+
+// Difference returns a new set of the elements of s that are not in t.
+func (s *Set) Difference(t *Set) *Set {
+	u := new(Set)
+	for e := range s.m {
+		if !t.Contains(e) {
+			u.Add(e)
+		}
+	}
+	return u
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package set
+
+import (
+	"sort"
+	"testing"
+)
+
+// elems returns the elements of s, which are ints, in ascending order.
+func elems(s *Set) []int {
+	var es []int
+	s.Range(func(e interface{}) bool {
+		es = append(es, e.(int))
+		return true
+	})
+	sort.Ints(es)
+	return es
+}
+
+func orderedElems(s *Ordered) []int {
+	var es []int
+	s.Ascend(func(e interface{}) bool {
+		es = append(es, e.(int))
+		return true
+	})
+	return es
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func intLess(a, b interface{}) bool { return a.(int) < b.(int) }
+
+func TestSet(t *testing.T) {
+	var s Set
+	if s.Contains(1) || s.Remove(1) || s.Len() != 0 {
+		t.Fatalf("zero Set is not empty")
+	}
+	if !s.Add(1) || !s.Add(2) || s.Add(1) {
+		t.Errorf("Add reported wrong results")
+	}
+	if !s.Contains(1) || !s.Contains(2) || s.Contains(3) {
+		t.Errorf("Contains reported wrong results")
+	}
+	if !s.Remove(1) || s.Remove(1) {
+		t.Errorf("Remove reported wrong results")
+	}
+	if got := elems(&s); !equal(got, []int{2}) || s.Len() != 1 {
+		t.Errorf("elements = %v, Len() = %d; want [2], 1", got, s.Len())
+	}
+}
+
+var algebraTests = []struct {
+	a, b                     []interface{}
+	union, intersect, differ []int
+}{
+	{nil, nil, nil, nil, nil},
+	{[]interface{}{1, 2, 3}, nil, []int{1, 2, 3}, nil, []int{1, 2, 3}},
+	{nil, []interface{}{1, 2}, []int{1, 2}, nil, nil},
+	{[]interface{}{1, 2, 3}, []interface{}{2, 3, 4}, []int{1, 2, 3, 4}, []int{2, 3}, []int{1}},
+	{[]interface{}{1, 2}, []interface{}{1, 2}, []int{1, 2}, []int{1, 2}, nil},
+}
+
+func TestSetAlgebra(t *testing.T) {
+	for _, test := range algebraTests {
+		a, b := New(test.a...), New(test.b...)
+		if got := elems(a.Union(b)); !equal(got, test.union) {
+			t.Errorf("%v Union %v = %v, want %v", test.a, test.b, got, test.union)
+		}
+		if got := elems(a.Intersect(b)); !equal(got, test.intersect) {
+			t.Errorf("%v Intersect %v = %v, want %v", test.a, test.b, got, test.intersect)
+		}
+		if got := elems(a.Difference(b)); !equal(got, test.differ) {
+			t.Errorf("%v Difference %v = %v, want %v", test.a, test.b, got, test.differ)
+		}
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	s := NewOrdered(intLess, 5, 1, 3)
+	if !s.Add(2) || s.Add(3) {
+		t.Errorf("Add reported wrong results")
+	}
+	if !s.Contains(5) || s.Contains(4) {
+		t.Errorf("Contains reported wrong results")
+	}
+	if !s.Remove(5) || s.Remove(5) {
+		t.Errorf("Remove reported wrong results")
+	}
+	if got := orderedElems(s); !equal(got, []int{1, 2, 3}) || s.Len() != 3 {
+		t.Errorf("elements = %v, Len() = %d; want [1 2 3], 3", got, s.Len())
+	}
+	var got []int
+	s.AscendRange(2, 10, func(e interface{}) bool {
+		got = append(got, e.(int))
+		return true
+	})
+	if !equal(got, []int{2, 3}) {
+		t.Errorf("AscendRange(2, 10) visited %v, want [2 3]", got)
+	}
+}
+
+func TestOrderedAlgebra(t *testing.T) {
+	for _, test := range algebraTests {
+		a, b := NewOrdered(intLess, test.a...), NewOrdered(intLess, test.b...)
+		if got := orderedElems(a.Union(b)); !equal(got, test.union) {
+			t.Errorf("%v Union %v = %v, want %v", test.a, test.b, got, test.union)
+		}
+		if got := orderedElems(a.Intersect(b)); !equal(got, test.intersect) {
+			t.Errorf("%v Intersect %v = %v, want %v", test.a, test.b, got, test.intersect)
+		}
+		if got := orderedElems(a.Difference(b)); !equal(got, test.differ) {
+			t.Errorf("%v Difference %v = %v, want %v", test.a, test.b, got, test.differ)
+		}
+	}
+}
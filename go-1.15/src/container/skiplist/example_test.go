@@ -0,0 +1,27 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package skiplist_test
+
+import (
+	"container/skiplist"
+	"fmt"
+)
+
+func Example() {
+	l := skiplist.New(func(a, b interface{}) bool { return a.(int) < b.(int) })
+	for _, k := range []int{40, 10, 30, 20} {
+		l.Put(k, fmt.Sprint("v", k))
+	}
+	l.Delete(30)
+	l.Ascend(func(key, value interface{}) bool {
+		fmt.Println(key, value)
+		return true
+	})
+
+	// Output:
+	// 10 v10
+	// 20 v20
+	// 40 v40
+}
@@ -0,0 +1,334 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package skiplist implements a concurrent skip list, a map that keeps its
+// keys in order.
+//
+// A List is safe for concurrent use. Lookups and iteration take no locks,
+// and an update locks only the nodes it links around, so writers working
+// on different parts of the list do not contend with each other. This
+// makes a List better suited to many concurrent writers than a tree
+// guarded by a single mutex.
+package skiplist
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// The list is the lazy skip list of Herlihy, Lev, Luchangco and Shavit.
+// A node is in the list once it is fully linked and until it is marked;
+// an update locks the predecessors of the node at every level it links,
+// and validates that they are still unmarked and adjacent to it.
+
+const (
+	maxLevel = 16 // enough for 4^16 keys
+	pShift   = 2  // each level holds 1/(1<<pShift) of the nodes below
+
+	// rankSample is the number of nodes a level needs for Rank to
+	// start its estimate there.
+	rankSample = 64
+)
+
+// A List is an ordered map from keys to values.
+// A List must be created with New.
+type List struct {
+	// The 64-bit atomic fields come first for alignment.
+	counts [maxLevel]int64 // number of nodes linked at each level
+	seed   uint64
+
+	less func(a, b interface{}) bool
+	head node // sentinel before all keys; only next and mu are used
+}
+
+type node struct {
+	key         interface{}
+	value       unsafe.Pointer // *interface{}
+	next        []unsafe.Pointer
+	mu          sync.Mutex
+	marked      uint32 // removed or being removed (atomic)
+	fullyLinked uint32 // linked at all levels (atomic)
+}
+
+// New returns an empty list whose keys are ordered by less, which
+// reports whether key a sorts before key b. less must define a strict
+// weak ordering; keys for which neither sorts before the other are the
+// same key.
+func New(less func(a, b interface{}) bool) *List {
+	l := &List{less: less}
+	l.head.next = make([]unsafe.Pointer, maxLevel)
+	l.seed = uint64(uintptr(unsafe.Pointer(l)))
+	return l
+}
+
+// Len returns the number of keys in l.
+// The complexity is O(1).
+func (l *List) Len() int { return int(atomic.LoadInt64(&l.counts[0])) }
+
+// Get returns the value of key in l and whether key is present.
+// The expected complexity is O(log n) where n = l.Len().
+func (l *List) Get(key interface{}) (value interface{}, ok bool) {
+	pred := &l.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr := pred.loadNext(level)
+		for curr != nil && l.less(curr.key, key) {
+			pred = curr
+			curr = pred.loadNext(level)
+		}
+		if curr != nil && !l.less(key, curr.key) {
+			if atomic.LoadUint32(&curr.fullyLinked) == 0 || atomic.LoadUint32(&curr.marked) != 0 {
+				return nil, false
+			}
+			return curr.loadValue(), true
+		}
+	}
+	return nil, false
+}
+
+// Put sets the value of key in l to value. If key was already present,
+// Put returns its previous value and true.
+// The expected complexity is O(log n) where n = l.Len().
+func (l *List) Put(key, value interface{}) (old interface{}, replaced bool) {
+	var preds, succs [maxLevel]*node
+	top := l.randomLevel()
+	for {
+		if lFound := l.find(key, &preds, &succs); lFound >= 0 {
+			n := succs[lFound]
+			n.mu.Lock()
+			if atomic.LoadUint32(&n.marked) != 0 {
+				// Being removed; retry once it is gone.
+				n.mu.Unlock()
+				continue
+			}
+			for atomic.LoadUint32(&n.fullyLinked) == 0 {
+				runtime.Gosched()
+			}
+			old = n.loadValue()
+			n.storeValue(value)
+			n.mu.Unlock()
+			return old, true
+		}
+
+		locked, valid := l.lockPreds(&preds, top, func(level int, pred *node) bool {
+			succ := succs[level]
+			return atomic.LoadUint32(&pred.marked) == 0 &&
+				(succ == nil || atomic.LoadUint32(&succ.marked) == 0) &&
+				pred.loadNext(level) == succ
+		})
+		if !valid {
+			unlockPreds(&preds, locked)
+			continue
+		}
+		n := &node{key: key, next: make([]unsafe.Pointer, top)}
+		n.storeValue(value)
+		for level := 0; level < top; level++ {
+			n.next[level] = unsafe.Pointer(succs[level])
+		}
+		for level := 0; level < top; level++ {
+			atomic.StorePointer(&preds[level].next[level], unsafe.Pointer(n))
+		}
+		atomic.StoreUint32(&n.fullyLinked, 1)
+		for level := 0; level < top; level++ {
+			atomic.AddInt64(&l.counts[level], 1)
+		}
+		unlockPreds(&preds, locked)
+		return nil, false
+	}
+}
+
+// Delete removes key from l. If key was present, Delete returns its value
+// and true.
+// The expected complexity is O(log n) where n = l.Len().
+func (l *List) Delete(key interface{}) (value interface{}, ok bool) {
+	var preds, succs [maxLevel]*node
+	var victim *node
+	for {
+		lFound := l.find(key, &preds, &succs)
+		if victim == nil {
+			if lFound < 0 {
+				return nil, false
+			}
+			n := succs[lFound]
+			if atomic.LoadUint32(&n.fullyLinked) == 0 || len(n.next)-1 != lFound || atomic.LoadUint32(&n.marked) != 0 {
+				// Not yet fully linked, or being removed.
+				if atomic.LoadUint32(&n.marked) != 0 {
+					return nil, false
+				}
+				runtime.Gosched()
+				continue
+			}
+			n.mu.Lock()
+			if atomic.LoadUint32(&n.marked) != 0 {
+				n.mu.Unlock()
+				return nil, false
+			}
+			atomic.StoreUint32(&n.marked, 1)
+			victim = n
+		}
+
+		locked, valid := l.lockPreds(&preds, len(victim.next), func(level int, pred *node) bool {
+			return atomic.LoadUint32(&pred.marked) == 0 && pred.loadNext(level) == victim
+		})
+		if !valid {
+			unlockPreds(&preds, locked)
+			continue
+		}
+		for level := len(victim.next) - 1; level >= 0; level-- {
+			atomic.StorePointer(&preds[level].next[level], victim.next[level])
+		}
+		value = victim.loadValue()
+		for level := 0; level < len(victim.next); level++ {
+			atomic.AddInt64(&l.counts[level], -1)
+		}
+		victim.mu.Unlock()
+		unlockPreds(&preds, locked)
+		return value, true
+	}
+}
+
+// Ascend calls f for each key and value of l in ascending key order,
+// until f returns false. Keys added or removed during the iteration may
+// or may not be visited.
+func (l *List) Ascend(f func(key, value interface{}) bool) {
+	for n := l.head.loadNext(0); n != nil; n = n.loadNext(0) {
+		if !n.visit(f) {
+			return
+		}
+	}
+}
+
+// AscendRange calls f for each key and value of l with from <= key < to
+// in ascending key order, until f returns false. Keys added or removed
+// during the iteration may or may not be visited.
+func (l *List) AscendRange(from, to interface{}, f func(key, value interface{}) bool) {
+	var preds, succs [maxLevel]*node
+	l.find(from, &preds, &succs)
+	for n := succs[0]; n != nil && l.less(n.key, to); n = n.loadNext(0) {
+		if !n.visit(f) {
+			return
+		}
+	}
+}
+
+// Rank returns an estimate of the number of keys in l that sort before
+// key. Rather than counting the keys, which takes O(n) time where
+// n = l.Len(), Rank counts the nodes before key at the sparse upper
+// levels of the list and scales them by the number of keys each stands
+// for. It takes O(log n) time, and its error is typically a few percent
+// of n.
+func (l *List) Rank(key interface{}) int {
+	// Start at the highest level with enough nodes
+	// to make up a representative sample.
+	level := maxLevel - 1
+	for level > 0 && atomic.LoadInt64(&l.counts[level]) < rankSample {
+		level--
+	}
+	n := float64(l.Len())
+	rank := 0.0
+	pred := &l.head
+	for ; level >= 0; level-- {
+		// Each node at this level stands for
+		// n/count nodes at level 0.
+		w := 1.0
+		if c := atomic.LoadInt64(&l.counts[level]); c > 0 {
+			w = n / float64(c)
+		}
+		for curr := pred.loadNext(level); curr != nil && l.less(curr.key, key); curr = pred.loadNext(level) {
+			rank += w
+			pred = curr
+		}
+	}
+	if rank > n {
+		rank = n
+	}
+	return int(rank + 0.5)
+}
+
+// find fills preds and succs with the nodes around key at every level,
+// and returns the highest level at which a node with key was found, or -1.
+func (l *List) find(key interface{}, preds, succs *[maxLevel]*node) int {
+	lFound := -1
+	pred := &l.head
+	for level := maxLevel - 1; level >= 0; level-- {
+		curr := pred.loadNext(level)
+		for curr != nil && l.less(curr.key, key) {
+			pred = curr
+			curr = pred.loadNext(level)
+		}
+		if lFound < 0 && curr != nil && !l.less(key, curr.key) {
+			lFound = level
+		}
+		preds[level] = pred
+		succs[level] = curr
+	}
+	return lFound
+}
+
+// lockPreds locks the distinct nodes of preds[:top] from the bottom up
+// while valid reports that they are still in place. It returns the
+// number of levels whose predecessor it locked.
+func (l *List) lockPreds(preds *[maxLevel]*node, top int, valid func(level int, pred *node) bool) (int, bool) {
+	var prev *node
+	for level := 0; level < top; level++ {
+		pred := preds[level]
+		if pred != prev {
+			pred.mu.Lock()
+			prev = pred
+		}
+		if !valid(level, pred) {
+			return level + 1, false
+		}
+	}
+	return top, true
+}
+
+// unlockPreds unlocks the nodes locked by lockPreds.
+func unlockPreds(preds *[maxLevel]*node, locked int) {
+	var prev *node
+	for level := 0; level < locked; level++ {
+		if pred := preds[level]; pred != prev {
+			pred.mu.Unlock()
+			prev = pred
+		}
+	}
+}
+
+// randomLevel returns the number of levels of a new node, which is k with
+// probability 3/4 * (1/4)^(k-1).
+func (l *List) randomLevel() int {
+	// splitmix64
+	z := atomic.AddUint64(&l.seed, 0x9e3779b97f4a7c15)
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	z ^= z >> 31
+	level := 1
+	for level < maxLevel && z&(1<<pShift-1) == 0 {
+		level++
+		z >>= pShift
+	}
+	return level
+}
+
+func (n *node) loadNext(level int) *node {
+	return (*node)(atomic.LoadPointer(&n.next[level]))
+}
+
+func (n *node) loadValue() interface{} {
+	return *(*interface{})(atomic.LoadPointer(&n.value))
+}
+
+func (n *node) storeValue(v interface{}) {
+	atomic.StorePointer(&n.value, unsafe.Pointer(&v))
+}
+
+// visit calls f for n if n is in the list, and reports whether the
+// iteration should continue.
+func (n *node) visit(f func(key, value interface{}) bool) bool {
+	if atomic.LoadUint32(&n.fullyLinked) == 0 || atomic.LoadUint32(&n.marked) != 0 {
+		return true
+	}
+	return f(n.key, n.loadValue())
+}
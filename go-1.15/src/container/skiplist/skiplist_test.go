@@ -0,0 +1,237 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package skiplist
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool { return a.(int) < b.(int) }
+
+// checkList checks that l holds exactly the keys of want, with value -key.
+func checkList(t *testing.T, l *List, want map[int]bool) {
+	t.Helper()
+	if l.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", l.Len(), len(want))
+	}
+	var keys []int
+	for k := range want {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	i := 0
+	l.Ascend(func(key, value interface{}) bool {
+		if i >= len(keys) || key.(int) != keys[i] || value.(int) != -keys[i] {
+			t.Fatalf("Ascend item %d = %v, %v", i, key, value)
+		}
+		i++
+		return true
+	})
+	if i != len(keys) {
+		t.Fatalf("Ascend visited %d items, want %d", i, len(keys))
+	}
+	for level := 1; level < maxLevel; level++ {
+		for n := l.head.loadNext(level); n != nil; n = n.loadNext(level) {
+			if next := n.loadNext(level); next != nil && !intLess(n.key, next.key) {
+				t.Fatalf("unordered keys %v, %v at level %d", n.key, next.key, level)
+			}
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	l := New(intLess)
+	want := make(map[int]bool)
+	const max = 2000
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(max)
+		if r.Intn(3) == 0 {
+			v, ok := l.Delete(k)
+			if ok != want[k] || ok && v.(int) != -k {
+				t.Fatalf("Delete(%d) = %v, %v want %v", k, v, ok, want[k])
+			}
+			delete(want, k)
+		} else {
+			old, replaced := l.Put(k, -k)
+			if replaced != want[k] || replaced && old.(int) != -k {
+				t.Fatalf("Put(%d) = %v, %v want %v", k, old, replaced, want[k])
+			}
+			want[k] = true
+		}
+		if i%1000 == 0 {
+			checkList(t, l, want)
+		}
+	}
+	checkList(t, l, want)
+	for k := 0; k < max; k++ {
+		v, ok := l.Get(k)
+		if ok != want[k] || ok && v.(int) != -k {
+			t.Fatalf("Get(%d) = %v, %v want %v", k, v, ok, want[k])
+		}
+	}
+	for _, k := range r.Perm(max) {
+		l.Delete(k)
+		delete(want, k)
+	}
+	checkList(t, l, want)
+}
+
+func TestAscendRange(t *testing.T) {
+	l := New(intLess)
+	for i := 0; i < 1000; i += 2 {
+		l.Put(i, -i)
+	}
+	for _, test := range []struct {
+		from, to   int
+		first, end int
+	}{
+		{0, 1000, 0, 1000},
+		{-10, 10, 0, 10},
+		{1, 11, 2, 12},
+		{500, 500, 500, 500},
+		{997, 2000, 998, 1000},
+		{1000, 2000, 1000, 1000},
+	} {
+		next := test.first
+		l.AscendRange(test.from, test.to, func(key, value interface{}) bool {
+			if key.(int) != next || value.(int) != -next {
+				t.Fatalf("AscendRange(%d, %d) visited %v, %v want %d", test.from, test.to, key, value, next)
+			}
+			next += 2
+			return true
+		})
+		if next != test.end {
+			t.Errorf("AscendRange(%d, %d) stopped before %d, want %d", test.from, test.to, next, test.end)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	const n = 100000
+	l := New(intLess)
+	for _, k := range rand.New(rand.NewSource(1)).Perm(n) {
+		l.Put(k, k)
+	}
+	if got := l.Rank(-1); got != 0 {
+		t.Errorf("Rank(-1) = %d, want 0", got)
+	}
+	if got := l.Rank(n); got != n {
+		t.Errorf("Rank(%d) = %d, want %d", n, got, n)
+	}
+	maxErr := 0
+	for k := 0; k < n; k += 100 {
+		err := l.Rank(k) - k
+		if err < 0 {
+			err = -err
+		}
+		if err > maxErr {
+			maxErr = err
+		}
+	}
+	// The levels of the nodes are random, so allow for bad luck.
+	if maxErr > n/5 {
+		t.Errorf("Rank is off by up to %d, want at most %d", maxErr, n/5)
+	}
+
+	// Small lists are counted exactly.
+	l = New(intLess)
+	for k := 0; k < rankSample; k++ {
+		l.Put(k, k)
+	}
+	for k := 0; k <= rankSample; k++ {
+		if got := l.Rank(k); got != k {
+			t.Errorf("Rank(%d) in a list of %d = %d", k, rankSample, got)
+		}
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	const (
+		workers = 8
+		keys    = 1000
+	)
+	ops := 20000
+	if testing.Short() {
+		ops = 2000
+	}
+	l := New(intLess)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < ops; i++ {
+				k := r.Intn(keys)
+				switch r.Intn(4) {
+				case 0:
+					l.Delete(k)
+				case 1:
+					if v, ok := l.Get(k); ok && v.(int) != -k {
+						t.Errorf("Get(%d) = %v", k, v)
+						return
+					}
+				default:
+					l.Put(k, -k)
+				}
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+
+	want := make(map[int]bool)
+	for k := 0; k < keys; k++ {
+		if _, ok := l.Get(k); ok {
+			want[k] = true
+		}
+	}
+	checkList(t, l, want)
+
+	// Each key is added and removed by exactly one worker
+	// of each pair, so the list ends up empty.
+	l = New(intLess)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for k := w; k < keys; k += workers {
+				if _, replaced := l.Put(k, -k); replaced {
+					t.Errorf("Put(%d) replaced a value", k)
+				}
+			}
+			for k := w; k < keys; k += workers {
+				if _, ok := l.Delete(k); !ok {
+					t.Errorf("Delete(%d) found nothing", k)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	checkList(t, l, map[int]bool{})
+}
+
+func BenchmarkPut(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(b.N)
+	l := New(intLess)
+	b.ResetTimer()
+	for _, k := range keys {
+		l.Put(k, k)
+	}
+}
+
+func BenchmarkPutParallel(b *testing.B) {
+	l := New(intLess)
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			k := r.Intn(1 << 20)
+			l.Put(k, k)
+		}
+	})
+}
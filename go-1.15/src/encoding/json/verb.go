@@ -0,0 +1,39 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// RegisterFmtVerb defines the %j verb of package fmt, which prints
+// the JSON encoding of its operand, and %#j, which prints it indented.
+// HTML characters are not escaped. The verb is not defined unless a
+// program calls RegisterFmtVerb, typically from an init function.
+func RegisterFmtVerb() {
+	fmt.RegisterVerb('j', formatVerb)
+}
+
+// formatVerb is the fmt.VerbFunc for %j.
+func formatVerb(f fmt.State, verb rune, arg interface{}) {
+	e := newEncodeState()
+	defer encodeStatePool.Put(e)
+	if err := e.marshal(arg, encOpts{escapeHTML: false}); err != nil {
+		io.WriteString(f, "%!j(ERROR=")
+		io.WriteString(f, err.Error())
+		io.WriteString(f, ")")
+		return
+	}
+	b := e.Bytes()
+	if f.Flag('#') {
+		var buf bytes.Buffer
+		if err := Indent(&buf, b, "", "\t"); err == nil {
+			b = buf.Bytes()
+		}
+	}
+	f.Write(b)
+}
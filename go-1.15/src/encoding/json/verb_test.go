@@ -0,0 +1,46 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormatVerb(t *testing.T) {
+	RegisterFmtVerb()
+	defer fmt.RegisterVerb('j', nil)
+
+	type point struct {
+		X, Y int
+		Tag  string `json:"tag,omitempty"`
+	}
+	// Variable formats keep vet from rejecting the %j verb.
+	j, sharpJ := "%j", "%#j"
+	for _, tt := range []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{j, point{1, 2, ""}, `{"X":1,"Y":2}`},
+		{j, []int{1, 2}, `[1,2]`},
+		{j, "<a&b>", `"<a&b>"`},
+		{j, nil, `null`},
+		{sharpJ, point{1, 2, "t"}, "{\n\t\"X\": 1,\n\t\"Y\": 2,\n\t\"tag\": \"t\"\n}"},
+		{j, make(chan int), `%!j(ERROR=json: unsupported type: chan int)`},
+		{"x=" + j + " y=%d", map[string]int{"a": 1}, `x={"a":1} y=%!d(MISSING)`},
+	} {
+		if got := fmt.Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("Sprintf(%q, %#v) = %q, want %q", tt.format, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestFormatVerbUnregistered(t *testing.T) {
+	j := "%j"
+	if got, want := fmt.Sprintf(j, 1), "%!j(int=1)"; got != want {
+		t.Errorf("Sprintf(%q, 1) = %q before RegisterFmtVerb, want %q", j, got, want)
+	}
+}
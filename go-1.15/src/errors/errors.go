@@ -7,10 +7,16 @@
 //
 //	Unwrap() error
 //
-// If e.Unwrap() returns a non-nil error w, then we say that e wraps w.
+// or
+//
+//	Unwrap() []error
+//
+// If e.Unwrap() returns a non-nil error w or a slice containing w,
+// then we say that e wraps w. A nil error returned from e.Unwrap()
+// indicates that e does not wrap any error.
 //
 // Unwrap unpacks wrapped errors. If its argument's type has an
-// Unwrap method, it calls the method once. Otherwise, it returns nil.
+// Unwrap() error method, it calls the method once. Otherwise, it returns nil.
 //
 // A simple way to create wrapped errors is to call fmt.Errorf and apply the %w verb
 // to the error argument:
@@ -19,9 +25,9 @@
 //
 // returns err.
 //
-// Is unwraps its first argument sequentially looking for an error that matches the
-// second. It reports whether it finds a match. It should be used in preference to
-// simple equality checks:
+// Is examines its first argument and the tree of errors it wraps, depth first,
+// looking for an error that matches the second. It reports whether it finds a
+// match. It should be used in preference to simple equality checks:
 //
 //	if errors.Is(err, os.ErrExist)
 //
@@ -31,9 +37,10 @@
 //
 // because the former will succeed if err wraps os.ErrExist.
 //
-// As unwraps its first argument sequentially looking for an error that can be
-// assigned to its second argument, which must be a pointer. If it succeeds, it
-// performs the assignment and returns true. Otherwise, it returns false. The form
+// As examines its first argument and the tree of errors it wraps, depth first,
+// looking for an error that can be assigned to its second argument, which must
+// be a pointer. If it succeeds, it performs the assignment and returns true.
+// Otherwise, it returns false. The form
 //
 //	var perr *os.PathError
 //	if errors.As(err, &perr) {
@@ -0,0 +1,53 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import "unicode/utf8"
+
+// Appender is implemented by any value that has an AppendFormat method,
+// which appends the formatted value for the verb to b and returns the
+// extended buffer. The printer checks for it after Formatter and before
+// GoStringer, Stringer, error and reflection, and hands AppendFormat its
+// own output buffer, so a type can format itself without intermediate
+// allocations. It is not used for %#v.
+//
+// The printer pads the appended text to the width, if any, honoring the
+// - and 0 flags; the precision and other flags are not available to
+// AppendFormat. Types that need them should implement Formatter.
+type Appender interface {
+	AppendFormat(b []byte, verb rune) []byte
+}
+
+// appendFormat appends the output of a.AppendFormat to p.buf, padded to
+// the width.
+func (p *pp) appendFormat(a Appender, verb rune) {
+	start := len(p.buf)
+	p.buf = a.AppendFormat(p.buf, verb)
+	if !p.fmt.widPresent {
+		return
+	}
+	n := p.fmt.wid - utf8.RuneCount(p.buf[start:])
+	if n <= 0 {
+		return
+	}
+	if p.fmt.minus {
+		for ; n > 0; n-- {
+			p.buf = append(p.buf, ' ')
+		}
+		return
+	}
+	padByte := byte(' ')
+	if p.fmt.zero {
+		padByte = '0'
+	}
+	end := len(p.buf)
+	for i := 0; i < n; i++ {
+		p.buf = append(p.buf, 0)
+	}
+	copy(p.buf[start+n:], p.buf[start:end])
+	for i := start; i < start+n; i++ {
+		p.buf[i] = padByte
+	}
+}
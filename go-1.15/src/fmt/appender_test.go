@@ -0,0 +1,88 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	"errors"
+	. "fmt"
+	"io/ioutil"
+	"strconv"
+	"testing"
+)
+
+// point implements Appender, Stringer and GoStringer.
+type point struct{ x, y int }
+
+func (p point) AppendFormat(b []byte, verb rune) []byte {
+	base := 10
+	if verb == 'x' {
+		base = 16
+	}
+	b = append(b, '(')
+	b = strconv.AppendInt(b, int64(p.x), base)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, int64(p.y), base)
+	return append(b, ')')
+}
+
+func (p point) String() string   { return "String" }
+func (p point) GoString() string { return "GoString" }
+
+type panicAppender struct{}
+
+func (panicAppender) AppendFormat(b []byte, verb rune) []byte { panic("boom") }
+
+type appendError struct{}
+
+func (appendError) AppendFormat(b []byte, verb rune) []byte { return append(b, "appended"...) }
+func (appendError) Error() string                           { return "Error" }
+
+func TestAppender(t *testing.T) {
+	for _, tt := range []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{"%v", point{1, 20}, "(1,20)"},
+		{"%s", point{1, 20}, "(1,20)"},
+		{"%x", point{10, 20}, "(a,14)"},
+		{"%#v", point{1, 2}, "GoString"},
+		{"%9v|", point{1, 20}, "   (1,20)|"},
+		{"%-9v|", point{1, 20}, "(1,20)   |"},
+		{"%09v|", point{1, 20}, "000(1,20)|"},
+		{"%3v|", point{1, 20}, "(1,20)|"},
+		{"%v", []point{{1, 2}, {3, 4}}, "[(1,2) (3,4)]"},
+		{"%v", struct{ P point }{point{5, 6}}, "{(5,6)}"},
+		{"%v", &point{7, 8}, "(7,8)"},
+		{"%v", panicAppender{}, "%!v(PANIC=AppendFormat method: boom)"},
+	} {
+		if got := Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("Sprintf(%q, %#v) = %q, want %q", tt.format, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestAppenderErrorf(t *testing.T) {
+	inner := appendError{}
+	err := Errorf("outer %v: %w", point{1, 2}, inner)
+	if got, want := err.Error(), "outer (1,2): appended"; got != want {
+		t.Errorf("Errorf = %q, want %q", got, want)
+	}
+	if !errors.Is(err, inner) {
+		t.Errorf("Errorf does not wrap the Appender error")
+	}
+}
+
+func TestAppenderAllocs(t *testing.T) {
+	var pr Printer
+	p := &point{123, 456}
+	pr.Fprintf(ioutil.Discard, "%v", p)
+	n := testing.AllocsPerRun(100, func() {
+		pr.Fprintf(ioutil.Discard, "%8v %x", p, p)
+	})
+	if n != 0 {
+		t.Errorf("got %v allocs per run, want 0", n)
+	}
+}
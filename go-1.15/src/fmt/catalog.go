@@ -0,0 +1,59 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import "sync/atomic"
+
+// A Catalog translates format strings, allowing an application to
+// localize the output of Printf, Sprintf, Errorf and the other functions
+// taking a format string. A translation may use explicit argument indexes,
+// such as %[2]s, to reorder the arguments for the target language:
+//
+//	"%s owes %d dollars"  →  "%[2]d dollars are owed by %[1]s"
+//
+// Lookup may be called concurrently from multiple goroutines.
+type Catalog interface {
+	// Lookup returns the translation of format, or false
+	// if the catalog has none.
+	Lookup(format string) (translation string, ok bool)
+}
+
+// catalog holds the installed Catalog in a *catalogHolder, or nil.
+var catalog atomic.Value
+
+type catalogHolder struct{ c Catalog }
+
+// SetCatalog installs c as the catalog consulted for every format string
+// and returns the previously installed catalog. Format strings without a
+// translation in c are used as they are. SetCatalog(nil) removes the
+// catalog.
+//
+// The catalog applies to all formatting in the program, including that
+// done by other packages, so its keys should be specific enough not to
+// match format strings that are not meant to be translated.
+func SetCatalog(c Catalog) (old Catalog) {
+	var h *catalogHolder
+	if c != nil {
+		h = &catalogHolder{c}
+	}
+	if oh, _ := catalog.Load().(*catalogHolder); oh != nil {
+		old = oh.c
+	}
+	catalog.Store(h)
+	return old
+}
+
+// translate returns the translation of format in the installed catalog,
+// or format itself.
+func translate(format string) string {
+	h, _ := catalog.Load().(*catalogHolder)
+	if h == nil {
+		return format
+	}
+	if t, ok := h.c.Lookup(format); ok {
+		return t
+	}
+	return format
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	"errors"
+	. "fmt"
+	"testing"
+)
+
+type mapCatalog map[string]string
+
+func (c mapCatalog) Lookup(format string) (string, bool) {
+	t, ok := c[format]
+	return t, ok
+}
+
+func TestCatalog(t *testing.T) {
+	if old := SetCatalog(mapCatalog{
+		"%s owes %d dollars": "%[2]d Dollar schuldet %[1]s",
+		"open %s: %w":        "%[1]s kann nicht geöffnet werden: %[2]w",
+	}); old != nil {
+		t.Fatalf("SetCatalog returned a previous catalog %v", old)
+	}
+	defer SetCatalog(nil)
+
+	if got, want := Sprintf("%s owes %d dollars", "Hans", 5), "5 Dollar schuldet Hans"; got != want {
+		t.Errorf("translated Sprintf = %q, want %q", got, want)
+	}
+	if got, want := Sprintf("%s owes %d euros", "Hans", 5), "Hans owes 5 euros"; got != want {
+		t.Errorf("untranslated Sprintf = %q, want %q", got, want)
+	}
+	inner := errors.New("permission denied")
+	err := Errorf("open %s: %w", "/etc/shadow", inner)
+	if got, want := err.Error(), "/etc/shadow kann nicht geöffnet werden: permission denied"; got != want {
+		t.Errorf("translated Errorf = %q, want %q", got, want)
+	}
+	if !errors.Is(err, inner) {
+		t.Errorf("translated Errorf does not wrap its %%w operand")
+	}
+
+	// SprintfNamed translates its format once, before rewriting the names.
+	SetCatalog(mapCatalog{
+		"hello %{name}s": "hallo %{name}s",
+		"hallo %[1]s":    "translated twice",
+	})
+	if got, want := SprintfNamed("hello %{name}s", map[string]interface{}{"name": "Hans"}), "hallo Hans"; got != want {
+		t.Errorf("translated SprintfNamed = %q, want %q", got, want)
+	}
+
+	if old := SetCatalog(nil); old == nil {
+		t.Errorf("SetCatalog(nil) did not return the installed catalog")
+	}
+	if got, want := Sprintf("%s owes %d dollars", "Hans", 5), "Hans owes 5 dollars"; got != want {
+		t.Errorf("Sprintf after removing the catalog = %q, want %q", got, want)
+	}
+}
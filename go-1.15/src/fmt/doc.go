@@ -17,6 +17,8 @@
 			when printing structs, the plus flag (%+v) adds field names
 		%#v	a Go-syntax representation of the value
 		%T	a Go-syntax representation of the type of the value
+		%j	the JSON encoding of the value; %#j indents it
+			(only in programs that call json.RegisterFmtVerb)
 		%%	a literal percent sign; consumes no value
 
 	Boolean:
@@ -149,9 +151,17 @@
 	1. If the operand is a reflect.Value, the operand is replaced by the
 	concrete value that it holds, and printing continues with the next rule.
 
+	If the operand implements the Redactor interface, the string returned
+	by its Redact method is printed in place of the operand and none of
+	the following rules apply.
+
 	2. If an operand implements the Formatter interface, it will
 	be invoked. Formatter provides fine control of formatting.
 
+	Otherwise, unless the verb is %#v, if an operand implements the
+	Appender interface, its AppendFormat method appends the formatted
+	value directly to the output, which is then padded to the width.
+
 	3. If the %v verb is used with the # flag (%#v) and the operand
 	implements the GoStringer interface, that will be invoked.
 
@@ -166,6 +176,12 @@
 	will be invoked to convert the object to a string, which will then
 	be formatted as required by the verb (if any).
 
+	Verbs not defined by this package may be given a meaning with
+	RegisterVerb and RegisterTypeVerb. A function registered for the
+	operand's type and the verb is invoked before all of the rules above
+	except Redactor; one registered for the verb alone is invoked unless
+	the operand implements Redactor or Formatter.
+
 	For compound operands such as slices and structs, the format
 	applies to the elements of each operand, recursively, not to the
 	operand as a whole. Thus %q will quote each element of a slice
@@ -312,6 +328,15 @@
 	   Sscanf(" 12 34 567 ", "%5s%d", &s, &i)
 	will set s to "12" and i to 34.
 
+	A *time.Duration operand scanned with %v or %s, or without a
+	format, accepts the syntax of time.ParseDuration, such as "1h30m",
+	or an integer number of nanoseconds. A *time.Time operand is parsed
+	with time.Parse using the layout written in braces between the %
+	and the verb, or time.RFC3339 if there is none; the input consumed
+	is as many space-separated words as the layout has. For example,
+	   Sscanf("2020-09-09 17:10 after 1h30m", "%{2006-01-02 15:04}v after %v", &t, &d)
+	sets t to 17:10 on 9 September 2020, UTC, and d to 90 minutes.
+
 	In all the scanning functions, a carriage return followed
 	immediately by a newline is treated as a plain newline
 	(\r\n means the same as \n).
@@ -1,24 +1,147 @@
 package fmt
 
-import "errors"
+import (
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+)
 
-// Errorf根据格式说明符进行格式化，并将字符串作为满足错误的值返回。如果格式说明符包含带有错误操作数的%w谓词，则返回的错误将实现返回该操作数的解包装方法。
-// 包含一个以上的%w谓词或为其提供一个没有实现错误接口的操作数是无效的。动词%w在其他情况下是%v的同义词。
+// Errorf根据格式说明符进行格式化，并将字符串作为满足错误的值返回。
+// 如果格式说明符包含带有错误操作数的%w谓词，则返回的错误将实现返回该操作数的Unwrap() error方法。
+// 如果有多于一个的%w谓词，返回的错误将实现Unwrap() []error方法，按照参数在参数列表中的顺序返回所有%w操作数，errors.Is和errors.As会依次检查其中的每一个。
+// 为%w提供一个没有实现错误接口的操作数是无效的。动词%w在其他情况下是%v的同义词。
+// 以%+v格式化返回的错误时，在错误信息之后每行打印一个被包装的错误，从而显示完整的原因链；%v的输出不变。
 func Errorf(format string, a ...interface{}) error {
+	return errorf(format, a, false)
+}
+
+// ErrBadWrap是StrictErrorf在%w误用时返回的错误所包装的错误。
+var ErrBadWrap = errors.New("fmt: bad operand for %w")
+
+// StrictErrorf与Errorf相同，但在%w的操作数没有实现错误接口(包括nil操作数)时，
+// 不会把%!w(...)埋进错误信息中，而是返回一个满足errors.Is(err, ErrBadWrap)的错误，指明出错的操作数。
+// 测试中可以检查该错误并调用panic或t.Fatal，让误用立即暴露出来。
+// Errorf允许多个%w，因此多个%w不被视为误用。
+func StrictErrorf(format string, a ...interface{}) error {
+	return errorf(format, a, true)
+}
+
+// badWrapError报告StrictErrorf的一个不是错误的%w操作数。
+type badWrapError struct {
+	argNum int
+	arg    interface{}
+}
+
+func (e *badWrapError) Error() string {
+	return Sprintf("fmt: %%w operand %d is %T, not an error", e.argNum+1, e.arg)
+}
+
+func (e *badWrapError) Unwrap() error { return ErrBadWrap }
+
+func errorf(format string, a []interface{}, strict bool) error {
 	p := newPrinter()
 	p.wrapErrs = true
 	p.doPrintf(format, a)
+	if strict {
+		for _, argNum := range p.wrappedErrs {
+			if _, ok := a[argNum].(error); !ok {
+				p.free()
+				return &badWrapError{argNum, a[argNum]}
+			}
+		}
+	}
 	s := string(p.buf)
 	var err error
-	if p.wrappedErr == nil {
+	switch len(p.wrappedErrs) {
+	case 0:
 		err = errors.New(s)
-	} else {
-		err = &wrapError{s, p.wrappedErr}
+	case 1:
+		w := &wrapError{msg: s}
+		w.err, _ = a[p.wrappedErrs[0]].(error)
+		err = w
+	default:
+		if p.reordered {
+			sort.Ints(p.wrappedErrs)
+		}
+		var errs []error
+		for i, argNum := range p.wrappedErrs {
+			if i > 0 && p.wrappedErrs[i-1] == argNum {
+				// 同一个参数被%w引用多次时只包装一次。
+				continue
+			}
+			if e, ok := a[argNum].(error); ok {
+				errs = append(errs, e)
+			}
+		}
+		err = &wrapErrors{s, errs}
 	}
 	p.free()
 	return err
 }
 
+// LazyErrorf与Errorf一样返回一个错误，但把格式化推迟到第一次调用Error或Format时进行，
+// 因此在热路径上构造而通常被丢弃的错误(例如重试循环中的错误)不必付出格式化的代价。
+// 参数切片在调用时被复制，但参数引用的值在格式化时才被读取，调用方在此之前不应修改它们。
+// 返回的错误包装的错误与Errorf对同样参数返回的错误包装的错误相同：格式只含一个%w谓词时Unwrap方法返回该操作数，
+// 没有%w谓词时返回nil；含多个%w谓词时errors.Is和errors.As通过返回的错误的Is和As方法检查其中的每一个。
+func LazyErrorf(format string, a ...interface{}) error {
+	return &lazyError{format: format, args: append([]interface{}(nil), a...)}
+}
+
+type lazyError struct {
+	once   sync.Once
+	format string
+	args   []interface{}
+	err    error // Errorf的结果，由resolve设置。
+}
+
+// resolve在第一次调用时进行格式化，并返回结果。
+func (e *lazyError) resolve() error {
+	e.once.Do(func() {
+		e.err = Errorf(e.format, e.args...)
+		e.args = nil
+	})
+	return e.err
+}
+
+func (e *lazyError) Error() string {
+	return e.resolve().Error()
+}
+
+// Unwrap返回格式中唯一的%w谓词的操作数。是否有多个%w谓词要到格式化后才知道，
+// 因此lazyError不能实现Unwrap() []error，多个被包装的错误改由Is和As检查。
+func (e *lazyError) Unwrap() error {
+	if w, ok := e.resolve().(*wrapError); ok {
+		return w.err
+	}
+	return nil
+}
+
+func (e *lazyError) Is(target error) bool {
+	if w, ok := e.resolve().(*wrapErrors); ok {
+		return errors.Is(w, target)
+	}
+	return false
+}
+
+func (e *lazyError) As(target interface{}) bool {
+	if w, ok := e.resolve().(*wrapErrors); ok {
+		return errors.As(w, target)
+	}
+	return false
+}
+
+func (e *lazyError) Format(s State, verb rune) {
+	err := e.resolve()
+	if f, ok := err.(Formatter); ok {
+		f.Format(s, verb)
+		return
+	}
+	formatWrapped(s, verb, err)
+}
+
 type wrapError struct {
 	msg string
 	err error
@@ -31,3 +154,90 @@ func (e *wrapError) Error() string {
 func (e *wrapError) Unwrap() error {
 	return e.err
 }
+
+// Format在e只包装了e.err而没有添加任何内容时(如Errorf("%w", err))，以与e.err完全相同的方式格式化，
+// 包括e.err自己的Format方法以及宽度、精度和标志；否则按formatWrapped格式化。
+func (e *wrapError) Format(s State, verb rune) {
+	if e.err != nil && e.msg == e.err.Error() {
+		if p, ok := s.(*pp); ok {
+			p.printArg(e.err, verb)
+			return
+		}
+		if f, ok := e.err.(Formatter); ok {
+			f.Format(s, verb)
+			return
+		}
+	}
+	formatWrapped(s, verb, e)
+}
+
+// wrapErrors是包含多个%w谓词时Errorf返回的错误。
+type wrapErrors struct {
+	msg  string
+	errs []error
+}
+
+func (e *wrapErrors) Error() string {
+	return e.msg
+}
+
+func (e *wrapErrors) Unwrap() []error {
+	return e.errs
+}
+
+func (e *wrapErrors) Format(s State, verb rune) {
+	formatWrapped(s, verb, e)
+}
+
+// formatWrapped实现Errorf返回的错误的Format方法。%+v先打印错误信息，然后每行打印一个被包装的错误，
+// 多个%w包装的错误各自的原因链再缩进一层；其他动词的输出与没有Format方法时相同。
+func formatWrapped(s State, verb rune, err error) {
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, err.Error())
+		writeWrapped(s, err, 1)
+		return
+	}
+	p, ok := s.(*pp)
+	if !ok {
+		io.WriteString(s, err.Error())
+		return
+	}
+	// 与handleMethods中处理error的方式一致。
+	if !p.fmt.sharpV {
+		switch verb {
+		case 'v', 's', 'x', 'X', 'q':
+			p.fmtString(err.Error(), verb)
+			return
+		}
+	}
+	p.printValue(reflect.ValueOf(err), verb, 0)
+}
+
+// writeWrapped把err包装的错误写入w，每个占一行，以depth个制表符缩进。
+func writeWrapped(w io.Writer, err error, depth int) {
+	if l, ok := err.(*lazyError); ok {
+		err = l.resolve()
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if e := x.Unwrap(); e != nil {
+			writeIndented(w, e.Error(), depth)
+			writeWrapped(w, e, depth)
+		}
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if e != nil {
+				writeIndented(w, e.Error(), depth)
+				writeWrapped(w, e, depth+1)
+			}
+		}
+	}
+}
+
+func writeIndented(w io.Writer, s string, depth int) {
+	io.WriteString(w, "\n")
+	for i := 0; i < depth; i++ {
+		io.WriteString(w, "\t")
+	}
+	io.WriteString(w, s)
+}
@@ -7,6 +7,9 @@ package fmt_test
 import (
 	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -20,6 +23,7 @@ func TestErrorf(t *testing.T) {
 		err        error
 		wantText   string
 		wantUnwrap error
+		wantSplit  []error
 	}{{
 		err:        fmt.Errorf("%w", wrapped),
 		wantText:   "inner error",
@@ -53,11 +57,29 @@ func TestErrorf(t *testing.T) {
 		err:      noVetErrorf("%w is not an error", "not-an-error"),
 		wantText: "%!w(string=not-an-error) is not an error",
 	}, {
-		err:      noVetErrorf("wrapped two errors: %w %w", errString("1"), errString("2")),
-		wantText: "wrapped two errors: 1 %!w(fmt_test.errString=2)",
+		err:       noVetErrorf("wrapped two errors: %w %w", errString("1"), errString("2")),
+		wantText:  "wrapped two errors: 1 2",
+		wantSplit: []error{errString("1"), errString("2")},
 	}, {
-		err:      noVetErrorf("wrapped three errors: %w %w %w", errString("1"), errString("2"), errString("3")),
-		wantText: "wrapped three errors: 1 %!w(fmt_test.errString=2) %!w(fmt_test.errString=3)",
+		err:       noVetErrorf("wrapped three errors: %w %w %w", errString("1"), errString("2"), errString("3")),
+		wantText:  "wrapped three errors: 1 2 3",
+		wantSplit: []error{errString("1"), errString("2"), errString("3")},
+	}, {
+		err:       noVetErrorf("wrapped nil error: %w %w %w", errString("1"), nil, errString("2")),
+		wantText:  "wrapped nil error: 1 %!w(<nil>) 2",
+		wantSplit: []error{errString("1"), errString("2")},
+	}, {
+		err:       noVetErrorf("wrapped one non-error: %w %w %w", errString("1"), "not-an-error", errString("3")),
+		wantText:  "wrapped one non-error: 1 %!w(string=not-an-error) 3",
+		wantSplit: []error{errString("1"), errString("3")},
+	}, {
+		err:       noVetErrorf("wrapped errors out of order: %[3]w %[2]w %[1]w", errString("1"), errString("2"), errString("3")),
+		wantText:  "wrapped errors out of order: 3 2 1",
+		wantSplit: []error{errString("1"), errString("2"), errString("3")},
+	}, {
+		err:       noVetErrorf("wrapped several times: %[1]w %[1]w %[2]w %[1]w", errString("1"), errString("2")),
+		wantText:  "wrapped several times: 1 1 2 1",
+		wantSplit: []error{errString("1"), errString("2")},
 	}, {
 		err:        fmt.Errorf("%w", nil),
 		wantText:   "%!w(<nil>)",
@@ -66,12 +88,254 @@ func TestErrorf(t *testing.T) {
 		if got, want := errors.Unwrap(test.err), test.wantUnwrap; got != want {
 			t.Errorf("Formatted error: %v\nerrors.Unwrap() = %v, want %v", test.err, got, want)
 		}
+		if got, want := splitErr(test.err), test.wantSplit; !reflect.DeepEqual(got, want) {
+			t.Errorf("Formatted error: %v\nUnwrap() []error = %q, want %q", test.err, got, want)
+		}
 		if got, want := test.err.Error(), test.wantText; got != want {
 			t.Errorf("err.Error() = %q, want %q", got, want)
 		}
 	}
 }
 
+func TestErrorfMultipleIsAs(t *testing.T) {
+	err1 := errors.New("first")
+	err2 := fmt.Errorf("second: %w", errString("inner"))
+	// Vet does not yet know about multiple %w verbs.
+	noVetErrorf := fmt.Errorf
+	err := noVetErrorf("close failed: %w; after: %w", err1, err2)
+	if !errors.Is(err, err1) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", err, err1)
+	}
+	if !errors.Is(err, errString("inner")) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", err, errString("inner"))
+	}
+	var target errString
+	if !errors.As(err, &target) || target != "inner" {
+		t.Errorf("errors.As(%v, &target) set target to %q, want %q", err, target, "inner")
+	}
+	if errors.Unwrap(err) != nil {
+		t.Errorf("errors.Unwrap(%v) != nil", err)
+	}
+}
+
+func splitErr(err error) []error {
+	if e, ok := err.(interface{ Unwrap() []error }); ok {
+		return e.Unwrap()
+	}
+	return nil
+}
+
 type errString string
 
 func (e errString) Error() string { return string(e) }
+
+func TestErrorfFormatChain(t *testing.T) {
+	// noVetErrorf is an alias for fmt.Errorf that does not trigger vet warnings for
+	// multiple %w verbs.
+	noVetErrorf := fmt.Errorf
+
+	inner := errors.New("inner")
+	mid := fmt.Errorf("mid: %w", inner)
+	outer := fmt.Errorf("outer: %w", mid)
+	multi := noVetErrorf("both: %w, %w", outer, errString("other"))
+	for _, test := range []struct {
+		format string
+		err    error
+		want   string
+	}{
+		{"%v", outer, "outer: mid: inner"},
+		{"%s", outer, "outer: mid: inner"},
+		{"%q", outer, `"outer: mid: inner"`},
+		{"%20v", mid, "          mid: inner"},
+		{"%-12s|", mid, "mid: inner  |"},
+		{"%x", mid, "6d69643a20696e6e6572"},
+		{"%+v", inner, "inner"},
+		{"%+v", mid, "mid: inner\n\tinner"},
+		{"%+v", outer, "outer: mid: inner\n\tmid: inner\n\tinner"},
+		{"%+v", multi, "both: outer: mid: inner, other\n\touter: mid: inner\n\t\tmid: inner\n\t\tinner\n\tother"},
+		{"%v", fmt.Errorf("wrap: %w", outer), "wrap: outer: mid: inner"},
+	} {
+		if got := fmt.Sprintf(test.format, test.err); got != test.want {
+			t.Errorf("Sprintf(%q, %v) = %q, want %q", test.format, test.err, got, test.want)
+		}
+	}
+	if got, want := fmt.Sprintf("%#v", mid), "&fmt.wrapError{"; !strings.HasPrefix(got, want) {
+		t.Errorf("Sprintf(%%#v, %v) = %q, want prefix %q", mid, got, want)
+	}
+}
+
+type countingStringer struct{ n *int }
+
+func (c countingStringer) String() string {
+	*c.n++
+	return "counted"
+}
+
+func TestLazyErrorf(t *testing.T) {
+	var calls int
+	inner := errors.New("inner")
+	args := []interface{}{countingStringer{&calls}, inner}
+	err := fmt.LazyErrorf("%v: %w", args...)
+	args[1] = nil // LazyErrorf must have copied its arguments.
+	if calls != 0 {
+		t.Fatalf("LazyErrorf formatted its arguments eagerly")
+	}
+	if !errors.Is(err, inner) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", err, inner)
+	}
+	for i := 0; i < 2; i++ {
+		if got, want := err.Error(), "counted: inner"; got != want {
+			t.Errorf("err.Error() = %q, want %q", got, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("arguments formatted %d times, want 1", calls)
+	}
+	if got, want := fmt.Sprintf("%+v|%q", err, err), "counted: inner\n\tinner|\"counted: inner\""; got != want {
+		t.Errorf("Sprintf(%%+v|%%q) = %q, want %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%8v|", fmt.LazyErrorf("plain")), "   plain|"; got != want {
+		t.Errorf("Sprintf(%%8v|) = %q, want %q", got, want)
+	}
+}
+
+func TestLazyErrorfUnwrap(t *testing.T) {
+	if err := errors.Unwrap(fmt.LazyErrorf("plain %d", 1)); err != nil {
+		t.Errorf("errors.Unwrap of LazyErrorf without %%w = %v, want nil", err)
+	}
+	inner := errors.New("inner")
+	if err := errors.Unwrap(fmt.LazyErrorf("ctx: %w", inner)); err != inner {
+		t.Errorf("errors.Unwrap of LazyErrorf with %%w = %v, want %v", err, inner)
+	}
+
+	// The wrapped errors are printed once, as for Errorf.
+	for _, tt := range []struct{ lazy, eager error }{
+		{fmt.LazyErrorf("boom"), fmt.Errorf("boom")},
+		{fmt.LazyErrorf("%w", inner), fmt.Errorf("%w", inner)},
+	} {
+		got := fmt.Sprintf("%+v", fmt.Errorf("ctx: %w", tt.lazy))
+		want := fmt.Sprintf("%+v", fmt.Errorf("ctx: %w", tt.eager))
+		if got != want {
+			t.Errorf("%%+v of wrapped LazyErrorf = %q, want %q", got, want)
+		}
+	}
+
+	// Several %w verbs are reached through Is and As.
+	noVetErrorf := fmt.Errorf
+	other := &detailedError{"other"}
+	err := fmt.LazyErrorf("%w, %w", inner, fmt.Errorf("deep: %w", other))
+	if !errors.Is(err, inner) || !errors.Is(err, other) {
+		t.Errorf("errors.Is of LazyErrorf with two %%w did not find both errors")
+	}
+	var de *detailedError
+	if !errors.As(err, &de) || de != other {
+		t.Errorf("errors.As of LazyErrorf with two %%w = %v, want %v", de, other)
+	}
+	if errors.Is(err, errors.New("inner")) {
+		t.Errorf("errors.Is matched an unrelated error")
+	}
+	if got, want := fmt.Sprintf("%+v", err), fmt.Sprintf("%+v", noVetErrorf("%w, %w", inner, fmt.Errorf("deep: %w", other))); got != want {
+		t.Errorf("%%+v of LazyErrorf with two %%w = %q, want %q", got, want)
+	}
+}
+
+func TestLazyErrorfConcurrent(t *testing.T) {
+	err := fmt.LazyErrorf("%d %s", 1, "two")
+	done := make(chan string)
+	for i := 0; i < 4; i++ {
+		go func() { done <- err.Error() }()
+	}
+	for i := 0; i < 4; i++ {
+		if got := <-done; got != "1 two" {
+			t.Errorf("err.Error() = %q, want %q", got, "1 two")
+		}
+	}
+}
+
+// detailedError formats itself with extra detail for %+v, like errors
+// carrying a stack trace.
+type detailedError struct{ msg string }
+
+func (e *detailedError) Error() string { return e.msg }
+
+func (e *detailedError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s (detail)", e.msg)
+		return
+	}
+	fmt.Fprintf(s, "%"+string(verb), e.msg)
+}
+
+func TestErrorfFormatLikeWrapped(t *testing.T) {
+	for _, orig := range []error{errors.New("plain"), &detailedError{"detailed"}, errString("str")} {
+		wrapped := fmt.Errorf("%w", orig)
+		for _, format := range []string{"%v", "%+v", "%s", "%q", "%x", "%10v|", "%-10s|", "%.3s", "%#v"} {
+			if got, want := fmt.Sprintf(format, wrapped), fmt.Sprintf(format, orig); got != want {
+				t.Errorf("Sprintf(%q, Errorf(%%w, %v)) = %q, want %q", format, orig, got, want)
+			}
+		}
+	}
+	// Added context is still printed as a wrap chain.
+	if got, want := fmt.Sprintf("%+v", fmt.Errorf("ctx: %w", &detailedError{"d"})), "ctx: d\n\td"; got != want {
+		t.Errorf("Sprintf(%%+v) = %q, want %q", got, want)
+	}
+}
+
+type isTargetError struct{}
+
+func (isTargetError) Error() string { return "is target" }
+
+func (isTargetError) Is(target error) bool { return target == io.EOF }
+
+func TestErrorfIsAs(t *testing.T) {
+	// noVetErrorf is an alias for fmt.Errorf that does not trigger vet warnings for
+	// multiple %w verbs.
+	noVetErrorf := fmt.Errorf
+
+	inner := errString("inner")
+	mid := fmt.Errorf("mid: %w", inner)
+	outer := fmt.Errorf("outer: %w", mid)
+	if !errors.Is(outer, mid) || !errors.Is(outer, inner) {
+		t.Errorf("errors.Is did not find the errors wrapped by %v", outer)
+	}
+	if !errors.Is(fmt.Errorf("x: %w", isTargetError{}), io.EOF) {
+		t.Errorf("errors.Is did not use the Is method of the wrapped error")
+	}
+	var target errString
+	if !errors.As(outer, &target) || target != inner {
+		t.Errorf("errors.As(%v, &target) set target to %q, want %q", outer, target, inner)
+	}
+	multi := noVetErrorf("%w %w", io.EOF, outer)
+	if !errors.Is(multi, inner) || errors.Is(multi, errString("other")) {
+		t.Errorf("errors.Is of a multiple %%w error examined the wrong errors")
+	}
+}
+
+func TestStrictErrorf(t *testing.T) {
+	wrapped := errString("inner")
+	err := fmt.StrictErrorf("outer: %w, %w", wrapped, io.EOF)
+	if err == nil || err.Error() != "outer: inner, EOF" {
+		t.Fatalf("StrictErrorf = %v", err)
+	}
+	if errors.Is(err, fmt.ErrBadWrap) || !errors.Is(err, io.EOF) || !errors.Is(err, wrapped) {
+		t.Errorf("StrictErrorf wrapping is wrong: %v", err)
+	}
+	for _, test := range []struct {
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{"%w", []interface{}{"not-an-error"}, "fmt: %w operand 1 is string, not an error"},
+		{"%v %w", []interface{}{1, nil}, "fmt: %w operand 2 is <nil>, not an error"},
+		{"%[2]w %[1]w", []interface{}{wrapped, 3}, "fmt: %w operand 2 is int, not an error"},
+	} {
+		err := fmt.StrictErrorf(test.format, test.args...)
+		if !errors.Is(err, fmt.ErrBadWrap) {
+			t.Errorf("StrictErrorf(%q) = %v, want an ErrBadWrap error", test.format, err)
+			continue
+		}
+		if got := err.Error(); got != test.want {
+			t.Errorf("StrictErrorf(%q) = %q, want %q", test.format, got, test.want)
+		}
+	}
+}
@@ -203,6 +203,7 @@ var fmtTests = []struct {
 	{"%#q", "abc", "`abc`"},
 	{"%q", "日本語", `"日本語"`},
 	{"%+q", "日本語", `"\u65e5\u672c\u8a9e"`},
+	{"%+s", "日本語\n", "日本語\n"},
 	{"%#q", "日本語", "`日本語`"},
 	{"%#+q", "日本語", "`日本語`"},
 	{"%q", "\a\b\f\n\r\t\v\"\\", `"\a\b\f\n\r\t\v\"\\"`},
@@ -1421,6 +1422,7 @@ func BenchmarkFprintIntNoAlloc(b *testing.B) {
 
 var mallocBuf bytes.Buffer
 var mallocPointer *int // A pointer so we know the interface value won't allocate.
+var mallocAppendBuf = make([]byte, 0, 64)
 
 var mallocTest = []struct {
 	count int
@@ -1439,10 +1441,41 @@ var mallocTest = []struct {
 		mallocBuf.Reset()
 		Fprintf(&mallocBuf, "%x %x %x", mallocPointer, mallocPointer, mallocPointer)
 	}},
+	{0, `Appendf(buf, "%x %x %x")`, func() {
+		mallocAppendBuf = Appendf(mallocAppendBuf[:0], "%x %x %x", mallocPointer, mallocPointer, mallocPointer)
+	}},
 }
 
 var _ bytes.Buffer
 
+func TestAppendf(t *testing.T) {
+	const prefix = "prefix:"
+	for _, tt := range fmtTests {
+		want := prefix + Sprintf(tt.fmt, tt.val)
+		if got := string(Appendf([]byte(prefix), tt.fmt, tt.val)); got != want {
+			t.Errorf("Appendf(%q, %q, %v) = %q, want %q", prefix, tt.fmt, tt.val, got, want)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	b := make([]byte, 0, 4)
+	b = Append(b, "a", 1, 2, "b")
+	if got, want := string(b), Sprint("a", 1, 2, "b"); got != want {
+		t.Errorf("Append = %q, want %q", got, want)
+	}
+	b = Appendln(b, "c", 3)
+	if got, want := string(b), Sprint("a", 1, 2, "b")+Sprintln("c", 3); got != want {
+		t.Errorf("Appendln = %q, want %q", got, want)
+	}
+	// The printer's own buffer must not leak into the result.
+	s := Sprintf("%s", "unrelated")
+	b = Appendf(b[:0], "%d", 42)
+	if got, want := string(b), "42"; got != want || s != "unrelated" {
+		t.Errorf("Appendf after Sprintf = %q, want %q", got, want)
+	}
+}
+
 func TestCountMallocs(t *testing.T) {
 	switch {
 	case testing.Short():
@@ -42,6 +42,10 @@ type fmt struct {
 	wid  int // width
 	prec int // precision
 
+	// ascii is set by Printer.SetASCII and, unlike the flags, persists
+	// across verbs.
+	ascii bool
+
 	// intbuf is large enough to store %b of an int64 with a sign and
 	// avoids padding at the end of the struct on 32 bit architectures.
 	intbuf [68]byte
@@ -148,7 +152,7 @@ func (f *fmt) fmtUnicode(u uint64) {
 	i := len(buf)
 
 	// For %#U we want to add a space and a quoted character at the end of the buffer.
-	if f.sharp && u <= utf8.MaxRune && strconv.IsPrint(rune(u)) {
+	if f.sharp && u <= utf8.MaxRune && strconv.IsPrint(rune(u)) && (!f.ascii || u < utf8.RuneSelf) {
 		i--
 		buf[i] = '\''
 		i -= utf8.RuneLen(rune(u))
@@ -352,15 +356,46 @@ func (f *fmt) truncate(b []byte) []byte {
 // fmtS formats a string.
 func (f *fmt) fmtS(s string) {
 	s = f.truncateString(s)
+	if f.ascii {
+		f.pad(appendEscaped(f.intbuf[:0], s))
+		return
+	}
 	f.padString(s)
 }
 
 // fmtBs formats the byte slice b as if it was formatted as string with fmtS.
 func (f *fmt) fmtBs(b []byte) {
 	b = f.truncate(b)
+	if f.ascii {
+		f.pad(appendEscaped(f.intbuf[:0], string(b)))
+		return
+	}
 	f.pad(b)
 }
 
+// appendEscaped appends s with non-ASCII and control characters escaped
+// as by %+q. Unlike %+q, it adds no quotes and leaves the other ASCII
+// characters, including quotes and backslashes, alone.
+func appendEscaped(buf []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r < utf8.RuneSelf && strconv.IsPrint(r):
+			buf = append(buf, byte(r))
+		case r == utf8.RuneError && width == 1:
+			buf = append(buf, `\x`...)
+			buf = append(buf, ldigits[s[i]>>4], ldigits[s[i]&0xF])
+		default:
+			n := len(buf)
+			buf = strconv.AppendQuoteRuneToASCII(buf, r)
+			copy(buf[n:], buf[n+1:len(buf)-1])
+			buf = buf[:len(buf)-2]
+		}
+		i += width
+	}
+	return buf
+}
+
 // fmtSbx formats a string or byte slice as a hexadecimal encoding of its bytes.
 func (f *fmt) fmtSbx(s string, b []byte, digits string) {
 	length := len(b)
@@ -442,12 +477,12 @@ func (f *fmt) fmtBx(b []byte, digits string) {
 // if the string does not contain any control characters other than tab.
 func (f *fmt) fmtQ(s string) {
 	s = f.truncateString(s)
-	if f.sharp && strconv.CanBackquote(s) {
+	if f.sharp && strconv.CanBackquote(s) && (!f.ascii || isASCII(s)) {
 		f.padString("`" + s + "`")
 		return
 	}
 	buf := f.intbuf[:0]
-	if f.plus {
+	if f.plus || f.ascii {
 		f.pad(strconv.AppendQuoteToASCII(buf, s))
 	} else {
 		f.pad(strconv.AppendQuote(buf, s))
@@ -462,10 +497,25 @@ func (f *fmt) fmtC(c uint64) {
 		r = utf8.RuneError
 	}
 	buf := f.intbuf[:0]
+	if f.ascii && (r >= utf8.RuneSelf || !strconv.IsPrint(r)) {
+		q := strconv.AppendQuoteRuneToASCII(buf, r)
+		f.pad(q[1 : len(q)-1])
+		return
+	}
 	w := utf8.EncodeRune(buf[:utf8.UTFMax], r)
 	f.pad(buf[:w])
 }
 
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
 // fmtQc formats an integer as a single-quoted, escaped Go character constant.
 // If the character is not valid Unicode, it will print '\ufffd'.
 func (f *fmt) fmtQc(c uint64) {
@@ -474,7 +524,7 @@ func (f *fmt) fmtQc(c uint64) {
 		r = utf8.RuneError
 	}
 	buf := f.intbuf[:0]
-	if f.plus {
+	if f.plus || f.ascii {
 		f.pad(strconv.AppendQuoteRuneToASCII(buf, r))
 	} else {
 		f.pad(strconv.AppendQuoteRune(buf, r))
@@ -0,0 +1,69 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import "unicode/utf8"
+
+// Kv formats alternating keys and values in the logfmt style used by
+// structured logs, as in
+//
+//	Kv("user", name, "attempt", 3, "err", err)
+//
+// which returns a string like
+//
+//	user=gopher attempt=3 err="permission denied"
+//
+// Keys and values are formatted as for %v. A key or value that is empty
+// or contains spaces, control characters, '=' or '"' is printed as a
+// double-quoted Go string. A key without a value is given the value
+// %!v(MISSING).
+func Kv(pairs ...interface{}) string {
+	p := newPrinter()
+	p.doPrintKv(pairs)
+	s := string(p.buf)
+	p.free()
+	return s
+}
+
+func (p *pp) doPrintKv(a []interface{}) {
+	for i := 0; i < len(a); i += 2 {
+		if i > 0 {
+			p.buf.writeByte(' ')
+		}
+		p.printKvItem(a[i])
+		p.buf.writeByte('=')
+		if i+1 < len(a) {
+			p.printKvItem(a[i+1])
+		} else {
+			p.buf.writeString(percentBangString)
+			p.buf.writeRune('v')
+			p.buf.writeString(missingString)
+		}
+	}
+}
+
+// printKvItem prints arg with %v, quoting the result if needed.
+func (p *pp) printKvItem(arg interface{}) {
+	start := len(p.buf)
+	p.printArg(arg, 'v')
+	if b := p.buf[start:]; needsKvQuote(b) {
+		s := string(b)
+		p.buf = p.buf[:start]
+		p.fmt.fmtQ(s)
+	}
+}
+
+// needsKvQuote reports whether b must be quoted as a logfmt key or value.
+func needsKvQuote(b []byte) bool {
+	if len(b) == 0 {
+		return true
+	}
+	for _, c := range b {
+		if c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			return true
+		}
+	}
+	return !utf8.Valid(b)
+}
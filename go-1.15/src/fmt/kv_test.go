@@ -0,0 +1,36 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	"errors"
+	. "fmt"
+	"testing"
+)
+
+func TestKv(t *testing.T) {
+	for _, tt := range []struct {
+		pairs []interface{}
+		want  string
+	}{
+		{nil, ""},
+		{[]interface{}{"a", 1}, "a=1"},
+		{[]interface{}{"user", "gopher", "attempt", 3, "ok", false}, "user=gopher attempt=3 ok=false"},
+		{[]interface{}{"err", errors.New("permission denied")}, `err="permission denied"`},
+		{[]interface{}{"empty", ""}, `empty=""`},
+		{[]interface{}{"eq", "a=b", "quote", `say "hi"`}, `eq="a=b" quote="say \"hi\""`},
+		{[]interface{}{"nl", "a\nb", "tab", "a\tb"}, `nl="a\nb" tab="a\tb"`},
+		{[]interface{}{"utf8", "héllo", "bad", "\xff"}, `utf8=héllo bad="\xff"`},
+		{[]interface{}{"my key", 1}, `"my key"=1`},
+		{[]interface{}{"list", []int{1, 2}}, `list="[1 2]"`},
+		{[]interface{}{"nil", nil}, "nil=<nil>"},
+		{[]interface{}{"a", 1, "dangling"}, "a=1 dangling=%!v(MISSING)"},
+		{[]interface{}{1, 2}, "1=2"},
+	} {
+		if got := Kv(tt.pairs...); got != tt.want {
+			t.Errorf("Kv(%#v) = %q, want %q", tt.pairs, got, tt.want)
+		}
+	}
+}
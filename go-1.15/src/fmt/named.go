@@ -0,0 +1,127 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import "unicode/utf8"
+
+// SprintfNamed is like Sprintf, but its operands are taken from args by
+// name. A name in braces takes the place of an explicit argument index:
+//
+//	SprintfNamed("user %{name}s owes %{amt}d", map[string]interface{}{
+//		"name": "gopher",
+//		"amt":  42,
+//	})
+//
+// returns "user gopher owes 42". As with an explicit index, the name
+// follows any flags, width and precision, as in %-8.2{amt}f. A name may
+// be used any number of times.
+// A verb whose name is not in args prints %!verb(MISSING=name), and a
+// verb without a name prints %!verb(MISSING), as it has no operand.
+func SprintfNamed(format string, args map[string]interface{}) string {
+	format, a := namedToPositional(translate(format), args)
+	p := newPrinter()
+	p.doPrintfTranslated(format, a)
+	s := string(p.buf)
+	p.free()
+	return s
+}
+
+// namedToPositional rewrites each %{name} in format to an explicit
+// argument index and returns the new format with the operands it
+// refers to. Verbs without a name are rewritten to the text they
+// print, %!verb(MISSING).
+func namedToPositional(format string, args map[string]interface{}) (string, []interface{}) {
+	var (
+		buf   []byte
+		a     []interface{}
+		index map[string]int
+	)
+	for i := 0; i < len(format); {
+		c := format[i]
+		buf = append(buf, c)
+		i++
+		if c != '%' {
+			continue
+		}
+		// Copy the flags, width, precision and indexes up to the verb,
+		// replacing names by indexes.
+		start := len(buf) - 1
+		named := false
+		for i < len(format) {
+			c := format[i]
+			if c != '{' {
+				buf = append(buf, c)
+				i++
+				if c == '%' || c != '[' && c != ']' && c != '.' && c != '*' &&
+					c != '+' && c != '-' && c != '#' && c != ' ' && (c < '0' || c > '9') {
+					// That was the verb.
+					if !named && c != '%' {
+						_, size := utf8.DecodeRuneInString(format[i-1:])
+						buf = append(buf[:start], "%%!"...)
+						buf = append(buf, format[i-1:i-1+size]...)
+						buf = append(buf, "(MISSING)"...)
+						i += size - 1
+					}
+					// Otherwise multibyte verbs are copied byte by
+					// byte by the outer loop.
+					break
+				}
+				continue
+			}
+			named = true
+			end := i + 1
+			for end < len(format) && format[end] != '}' {
+				end++
+			}
+			if end == len(format) {
+				// No closing brace; let doPrintf report the bad verb.
+				buf = append(buf, format[i:]...)
+				i = end
+				break
+			}
+			name := format[i+1 : end]
+			i = end + 1
+			n, ok := index[name]
+			if !ok {
+				if index == nil {
+					index = make(map[string]int)
+				}
+				v, present := args[name]
+				if !present {
+					v = missingName(name)
+				}
+				a = append(a, v)
+				n = len(a)
+				index[name] = n
+			}
+			buf = append(buf, '[')
+			buf = appendDecimal(buf, n)
+			buf = append(buf, ']')
+		}
+	}
+	return string(buf), a
+}
+
+func appendDecimal(b []byte, n int) []byte {
+	var d [20]byte
+	i := len(d)
+	for {
+		i--
+		d[i] = byte('0' + n%10)
+		n /= 10
+		if n == 0 {
+			break
+		}
+	}
+	return append(b, d[i:]...)
+}
+
+// missingName is the operand used for a name that is not in the map
+// passed to SprintfNamed.
+type missingName string
+
+func (m missingName) Format(s State, verb rune) {
+	Fprintf(s, "%%!%c(MISSING=%s)", verb, string(m))
+}
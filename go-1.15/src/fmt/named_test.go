@@ -0,0 +1,39 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	. "fmt"
+	"testing"
+)
+
+func TestSprintfNamed(t *testing.T) {
+	args := map[string]interface{}{
+		"name": "gopher",
+		"amt":  42,
+		"pi":   3.14159,
+	}
+	for _, tt := range []struct {
+		format string
+		want   string
+	}{
+		{"user %{name}s owes %{amt}d", "user gopher owes 42"},
+		{"%{amt}d %{name}s %{amt}x", "42 gopher 2a"},
+		{"%5{amt}d|%-8{name}s|%.2{pi}f|%8.3{pi}f", "   42|gopher  |3.14|   3.142"},
+		{"100%% %{name}q", `100% "gopher"`},
+		{"%{nobody}s is missing", "%!s(MISSING=nobody) is missing"},
+		{"plain %s", "plain %!s(MISSING)"},
+		{"%{name}s %{amt}d %{name}s %d", "gopher 42 gopher %!d(MISSING)"},
+		{"%-5d|%{amt}d", "%!d(MISSING)|42"},
+		{"%{amt}d %é", "42 %!é(MISSING)"},
+		{"%{name", "%!{(MISSING)name"},
+		{"héllo %{name}v", "héllo gopher"},
+		{"", ""},
+	} {
+		if got := SprintfNamed(tt.format, args); got != tt.want {
+			t.Errorf("SprintfNamed(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import (
+	"reflect"
+	"std/internal/fmtsort"
+)
+
+// Pretty formats v like %+v, but spreads structs, maps, and slices and
+// arrays of such values over multiple lines, indenting each level with
+// a tab. Struct fields are printed with their names, and pointers to
+// composite values are followed instead of being printed as addresses;
+// a pointer back to a value that is already being printed is shown as
+// an address to break the cycle. Values implementing Formatter, error
+// or Stringer are printed on a single line using those methods.
+func Pretty(v interface{}) string {
+	p := newPrinter()
+	p.fmt.plusV = true
+	p.printPretty(reflect.ValueOf(v), 0, nil)
+	s := string(p.buf)
+	p.free()
+	return s
+}
+
+// printPretty prints value at the given indentation depth.
+// seen holds the addresses of the pointers being followed.
+func (p *pp) printPretty(value reflect.Value, depth int, seen []uintptr) {
+	if value.IsValid() {
+		if value.CanInterface() {
+			p.arg = value.Interface()
+			if p.handleMethods('v') {
+				return
+			}
+		} else if value.Type().Implements(redactorType) {
+			p.fmtRedacted(redactedString, 'v')
+			return
+		}
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.NumField() == 0 {
+			break
+		}
+		p.buf.writeByte('{')
+		for i := 0; i < value.NumField(); i++ {
+			p.writeIndent(depth + 1)
+			if name := value.Type().Field(i).Name; name != "" {
+				p.buf.writeString(name)
+				p.buf.writeString(": ")
+			}
+			if redactedField(value.Type(), i) {
+				p.fmtRedacted(redactedString, 'v')
+				continue
+			}
+			p.printPretty(getField(value, i), depth+1, seen)
+		}
+		p.writeIndent(depth)
+		p.buf.writeByte('}')
+		return
+	case reflect.Map:
+		if value.Len() == 0 {
+			break
+		}
+		p.buf.writeString(mapString)
+		sorted := fmtsort.Sort(value)
+		for i, key := range sorted.Key {
+			p.writeIndent(depth + 1)
+			p.printPretty(key, depth+1, seen)
+			p.buf.writeString(": ")
+			p.printPretty(sorted.Value[i], depth+1, seen)
+		}
+		p.writeIndent(depth)
+		p.buf.writeByte(']')
+		return
+	case reflect.Array, reflect.Slice:
+		if value.Len() == 0 || !prettyComposite(value.Type().Elem()) {
+			break
+		}
+		p.buf.writeByte('[')
+		for i := 0; i < value.Len(); i++ {
+			p.writeIndent(depth + 1)
+			p.printPretty(value.Index(i), depth+1, seen)
+		}
+		p.writeIndent(depth)
+		p.buf.writeByte(']')
+		return
+	case reflect.Ptr:
+		if value.IsNil() || !prettyComposite(value.Type().Elem()) {
+			break
+		}
+		ptr := value.Pointer()
+		for _, s := range seen {
+			if s == ptr {
+				p.fmtPointer(value, 'v')
+				return
+			}
+		}
+		p.buf.writeByte('&')
+		p.printPretty(value.Elem(), depth, append(seen, ptr))
+		return
+	case reflect.Interface:
+		if !value.IsNil() {
+			p.printPretty(value.Elem(), depth, seen)
+			return
+		}
+	}
+	// Everything else is printed on one line, as by %+v.
+	p.printValue(value, 'v', depth+1)
+}
+
+// prettyComposite reports whether Pretty spreads values of type t,
+// or the values pointed to by t, over multiple lines.
+func prettyComposite(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Interface:
+		return true
+	case reflect.Array, reflect.Slice:
+		return prettyComposite(t.Elem())
+	}
+	return false
+}
+
+// writeIndent starts a new line indented by depth tabs.
+func (p *pp) writeIndent(depth int) {
+	p.buf.writeByte('\n')
+	for i := 0; i < depth; i++ {
+		p.buf.writeByte('\t')
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	. "fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type prettyServer struct {
+	Name     string
+	Ports    []int
+	Timeout  time.Duration
+	TLS      *prettyTLS
+	Backends []prettyBackend
+	Labels   map[string]string
+	Extra    interface{}
+	Empty    struct{}
+	Secret   string `fmt:"redact"`
+	next     *prettyServer
+}
+
+type prettyTLS struct {
+	Cert, Key string
+}
+
+type prettyBackend struct {
+	Addr   string
+	Weight int
+}
+
+func TestPretty(t *testing.T) {
+	s := &prettyServer{
+		Name:     "api",
+		Ports:    []int{80, 443},
+		Timeout:  3 * time.Second,
+		TLS:      &prettyTLS{"cert.pem", "key.pem"},
+		Backends: []prettyBackend{{"10.0.0.1", 1}, {"10.0.0.2", 2}},
+		Labels:   map[string]string{"zone": "b", "env": "prod"},
+		Extra:    map[string]int{"a": 1},
+		Secret:   "hunter2",
+	}
+	want := `&{
+	Name: api
+	Ports: [80 443]
+	Timeout: 3s
+	TLS: &{
+		Cert: cert.pem
+		Key: key.pem
+	}
+	Backends: [
+		{
+			Addr: 10.0.0.1
+			Weight: 1
+		}
+		{
+			Addr: 10.0.0.2
+			Weight: 2
+		}
+	]
+	Labels: map[
+		env: prod
+		zone: b
+	]
+	Extra: map[
+		a: 1
+	]
+	Empty: {}
+	Secret: [REDACTED]
+	next: <nil>
+}`
+	if got := Pretty(s); got != want {
+		t.Errorf("Pretty:\n%s\nwant:\n%s", got, want)
+	}
+
+	for _, tt := range []struct {
+		v    interface{}
+		want string
+	}{
+		{nil, "<nil>"},
+		{1, "1"},
+		{"s", "s"},
+		{[]int{}, "[]"},
+		{map[string]int{}, "map[]"},
+		{[]string{"a", "b"}, "[a b]"},
+		{(*prettyTLS)(nil), "<nil>"},
+		{[]*prettyTLS{{"c", "k"}}, "[\n\t&{\n\t\tCert: c\n\t\tKey: k\n\t}\n]"},
+	} {
+		if got := Pretty(tt.v); got != tt.want {
+			t.Errorf("Pretty(%#v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestPrettyCycle(t *testing.T) {
+	s := &prettyServer{Name: "loop"}
+	s.next = s
+	got := Pretty(s)
+	if want := "\tnext: 0x"; !strings.Contains(got, want) {
+		t.Errorf("Pretty of a cyclic value = %q, want it to contain %q", got, want)
+	}
+}
@@ -104,7 +104,7 @@ type pp struct {
 	panicking bool // panicking通过设置catchPanic来避免无限的panic, recover, panic, ... recursion.
 	erroring bool // 在打印错误字符串时设置erroring，以防止调用handlememethods。
 	wrapErrs bool // 当格式字符串可能包含%w谓词时设置wrapErrs。
-	wrappedErr error // wrappedErr记录%w谓词的目标。
+	wrappedErrs []int // wrappedErrs按出现顺序记录各个%w谓词的参数下标。
 }
 
 var ppFree = sync.Pool{
@@ -131,7 +131,7 @@ func (p *pp) free() {
 	p.buf = p.buf[:0]
 	p.arg = nil
 	p.value = reflect.Value{}
-	p.wrappedErr = nil
+	p.wrappedErrs = p.wrappedErrs[:0]
 	ppFree.Put(p)
 }
 
@@ -193,6 +193,19 @@ func Sprintf(format string, a ...interface{}) string {
 	return s
 }
 
+// Appendf根据格式说明符格式化，将结果追加到字节切片b中并返回更新后的切片。
+// 格式化直接写入b，不经过中间字符串，因此参数不能与b[len(b):cap(b)]共享内存。
+func Appendf(b []byte, format string, a ...interface{}) []byte {
+	p := newPrinter()
+	buf := p.buf
+	p.buf = b
+	p.doPrintf(format, a)
+	b = p.buf
+	p.buf = buf
+	p.free()
+	return b
+}
+
 // 这些goroutine不接受格式字符串
 
 // Fprint格式使用其操作数的默认格式和写入到w。如果操作数和写入都不是字符串，则在操作数之间添加空格。它返回写入的字节数和遇到的任何写入错误。
@@ -221,6 +234,19 @@ func Sprint(a ...interface{}) string {
 	return s
 }
 
+// Append使用其操作数的默认格式进行格式化，将结果追加到字节切片b中并返回更新后的切片。
+// 参数不能与b[len(b):cap(b)]共享内存。
+func Append(b []byte, a ...interface{}) []byte {
+	p := newPrinter()
+	buf := p.buf
+	p.buf = b
+	p.doPrint(a)
+	b = p.buf
+	p.buf = buf
+	p.free()
+	return b
+}
+
 // These routines end in 'ln', do not take a format string,
 // always add spaces between operands, and add a newline
 // after the last operand.
@@ -253,6 +279,19 @@ func Sprintln(a ...interface{}) string {
 	return s
 }
 
+// Appendln使用其操作数的默认格式进行格式化，将结果追加到字节切片b中并返回更新后的切片。操作数之间总是添加空格，末尾追加换行符。
+// 参数不能与b[len(b):cap(b)]共享内存。
+func Appendln(b []byte, a ...interface{}) []byte {
+	p := newPrinter()
+	buf := p.buf
+	p.buf = b
+	p.doPrintln(a)
+	b = p.buf
+	p.buf = buf
+	p.free()
+	return b
+}
+
 // getField gets the i'th field of the struct value.
 // If the field is itself is an interface, return a value for
 // the thing inside the interface, not the interface itself.
@@ -544,17 +583,16 @@ func (p *pp) handleMethods(verb rune) (handled bool) {
 	if p.erroring {
 		return
 	}
+	if p.handleRedactor(verb) {
+		return true
+	}
 	if verb == 'w' {
-		// It is invalid to use %w other than with Errorf, more than once,
-		// or with a non-error arg.
-		err, ok := p.arg.(error)
-		if !ok || !p.wrapErrs || p.wrappedErr != nil {
-			p.wrappedErr = nil
-			p.wrapErrs = false
+		// It is invalid to use %w other than with Errorf or with a non-error arg.
+		_, ok := p.arg.(error)
+		if !ok || !p.wrapErrs {
 			p.badVerb(verb)
 			return true
 		}
-		p.wrappedErr = err
 		// If the arg is a Formatter, pass 'v' as the verb to it.
 		verb = 'v'
 	}
@@ -567,6 +605,14 @@ func (p *pp) handleMethods(verb rune) (handled bool) {
 		return
 	}
 
+	// Is it an Appender?
+	if appender, ok := p.arg.(Appender); ok && !p.fmt.sharpV {
+		handled = true
+		defer p.catchPanic(p.arg, verb, "AppendFormat")
+		p.appendFormat(appender, verb)
+		return
+	}
+
 	// If we're doing Go syntax and the argument knows how to supply it, take care of it now.
 	if p.fmt.sharpV {
 		if stringer, ok := p.arg.(GoStringer); ok {
@@ -608,6 +654,11 @@ func (p *pp) printArg(arg interface{}, verb rune) {
 	p.arg = arg
 	p.value = reflect.Value{}
 
+	if fn := customVerb(arg, verb); fn != nil {
+		p.printCustom(fn, arg, verb)
+		return
+	}
+
 	if arg == nil {
 		switch verb {
 		case 'T', 'v':
@@ -690,9 +741,18 @@ func (p *pp) printArg(arg interface{}, verb rune) {
 // printValue is similar to printArg but starts with a reflect value, not an interface{} value.
 // It does not handle 'p' and 'T' verbs because these should have been already handled by printArg.
 func (p *pp) printValue(value reflect.Value, verb rune, depth int) {
+	// Unexported fields cannot be asked for their placeholder.
+	if depth > 0 && value.IsValid() && !value.CanInterface() && value.Type().Implements(redactorType) {
+		p.fmtRedacted(redactedString, verb)
+		return
+	}
 	// Handle values with special methods if not already handled by printArg (depth == 0).
 	if depth > 0 && value.IsValid() && value.CanInterface() {
 		p.arg = value.Interface()
+		if fn := customVerb(p.arg, verb); fn != nil {
+			p.printCustom(fn, p.arg, verb)
+			return
+		}
 		if p.handleMethods(verb) {
 			return
 		}
@@ -776,6 +836,10 @@ func (p *pp) printValue(value reflect.Value, verb rune, depth int) {
 					p.buf.writeByte(':')
 				}
 			}
+			if redactedField(f.Type(), i) {
+				p.fmtRedacted(redactedString, verb)
+				continue
+			}
 			p.printValue(getField(f, i), verb, depth+1)
 		}
 		p.buf.writeByte('}')
@@ -945,6 +1009,11 @@ func (p *pp) missingArg(verb rune) {
 }
 
 func (p *pp) doPrintf(format string, a []interface{}) {
+	p.doPrintfTranslated(translate(format), a)
+}
+
+// doPrintfTranslated与doPrintf相同，但format已经在目录中查找过翻译。
+func (p *pp) doPrintfTranslated(format string, a []interface{}) {
 	end := len(format)
 	argNum := 0         // we process one argument per non-trivial format
 	afterIndex := false // previous item in format was an index like [3].
@@ -988,6 +1057,9 @@ formatLoop:
 				// Fast path for common case of ascii lower case simple verbs
 				// without precision or width or argument indices.
 				if 'a' <= c && c <= 'z' && argNum < len(a) {
+					if c == 'w' && p.wrapErrs {
+						p.wrappedErrs = append(p.wrappedErrs, argNum)
+					}
 					if c == 'v' {
 						// Go syntax
 						p.fmt.sharpV = p.fmt.sharp
@@ -1092,6 +1164,9 @@ formatLoop:
 			p.fmt.plus = false
 			fallthrough
 		default:
+			if verb == 'w' && p.wrapErrs {
+				p.wrappedErrs = append(p.wrappedErrs, argNum)
+			}
 			p.printArg(a[argNum], verb)
 			argNum++
 		}
@@ -0,0 +1,108 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import (
+	"io"
+	"reflect"
+)
+
+// A Printer holds the state used to format operands, letting callers
+// that print often manage its lifetime themselves, for instance by
+// keeping one per goroutine or in their own sync.Pool, instead of
+// going through the package's internal free list. The buffer a Printer
+// formats into is retained between calls, so steady-state use does not
+// allocate for the output.
+//
+// The zero value is ready to use. A Printer must not be copied after
+// first use and is not safe for concurrent use by multiple goroutines.
+type Printer struct {
+	p     pp
+	ascii bool
+}
+
+// start prepares the printer for a new call.
+func (pr *Printer) start() *pp {
+	p := &pr.p
+	p.buf = p.buf[:0]
+	p.panicking = false
+	p.erroring = false
+	p.wrapErrs = false
+	p.fmt.init(&p.buf)
+	p.fmt.ascii = pr.ascii
+	return p
+}
+
+// finish drops the references to the last operand formatted.
+func (pr *Printer) finish() {
+	pr.p.arg = nil
+	pr.p.value = reflect.Value{}
+	pr.p.wrappedErrs = pr.p.wrappedErrs[:0]
+}
+
+// Reset releases the buffer and any other state retained by pr,
+// including its options, returning it to its zero value.
+func (pr *Printer) Reset() {
+	*pr = Printer{}
+}
+
+// SetASCII sets whether pr keeps its output 7-bit clean. When on, the
+// strings and characters it formats, under any verb, have their
+// non-ASCII and control characters escaped as %+q would escape them,
+// without adding quotes under %s and %v; %q then behaves as %+q. Text
+// written directly by Formatter implementations and the literal text of
+// the format are not escaped.
+func (pr *Printer) SetASCII(on bool) {
+	pr.ascii = on
+}
+
+// Fprintf is like the package-level Fprintf but uses pr's buffer.
+func (pr *Printer) Fprintf(w io.Writer, format string, a ...interface{}) (n int, err error) {
+	p := pr.start()
+	p.doPrintf(format, a)
+	n, err = w.Write(p.buf)
+	pr.finish()
+	return
+}
+
+// Fprint is like the package-level Fprint but uses pr's buffer.
+func (pr *Printer) Fprint(w io.Writer, a ...interface{}) (n int, err error) {
+	p := pr.start()
+	p.doPrint(a)
+	n, err = w.Write(p.buf)
+	pr.finish()
+	return
+}
+
+// Fprintln is like the package-level Fprintln but uses pr's buffer.
+func (pr *Printer) Fprintln(w io.Writer, a ...interface{}) (n int, err error) {
+	p := pr.start()
+	p.doPrintln(a)
+	n, err = w.Write(p.buf)
+	pr.finish()
+	return
+}
+
+// Appendf is like the package-level Appendf: it formats directly into b
+// and returns the extended slice, leaving pr's own buffer untouched.
+func (pr *Printer) Appendf(b []byte, format string, a ...interface{}) []byte {
+	p := pr.start()
+	buf := p.buf
+	p.buf = b
+	p.doPrintf(format, a)
+	b = p.buf
+	p.buf = buf
+	pr.finish()
+	return b
+}
+
+// Sprintf is like the package-level Sprintf but uses pr's buffer.
+func (pr *Printer) Sprintf(format string, a ...interface{}) string {
+	p := pr.start()
+	p.doPrintf(format, a)
+	s := string(p.buf)
+	pr.finish()
+	return s
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	"bytes"
+	"errors"
+	. "fmt"
+	"io/ioutil"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	var pr Printer
+	var buf bytes.Buffer
+	for i := 0; i < 3; i++ {
+		buf.Reset()
+		pr.Fprintf(&buf, "%d:%s", i, "x")
+		pr.Fprint(&buf, " a", 1, 2)
+		n, err := pr.Fprintln(&buf, "", true)
+		if n != 6 || err != nil {
+			t.Errorf("Fprintln = %d, %v; want 6, nil", n, err)
+		}
+		want := Sprintf("%d:x a1 2 true\n", i)
+		if buf.String() != want {
+			t.Errorf("iteration %d: got %q, want %q", i, buf.String(), want)
+		}
+	}
+	if got := string(pr.Appendf([]byte("<"), "%03d>", 7)); got != "<007>" {
+		t.Errorf("Appendf = %q, want %q", got, "<007>")
+	}
+	if got := pr.Sprintf("%v %x", []int{1}, "hi"); got != "[1] 6869" {
+		t.Errorf("Sprintf = %q", got)
+	}
+	pr.Reset()
+	if got := pr.Sprintf("%5.1f", 2.25); got != "  2.2" {
+		t.Errorf("after Reset, Sprintf = %q", got)
+	}
+}
+
+func TestPrinterPanicState(t *testing.T) {
+	var pr Printer
+	var p *PanicS
+	if got, want := pr.Sprintf("%s", p), "<nil>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := pr.Sprintf("%s", PanicS{errors.New("boom")}), "%!s(PANIC=String method: boom)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := pr.Sprintf("%d", 1), "1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrinterAllocs(t *testing.T) {
+	var pr Printer
+	b := make([]byte, 0, 64)
+	pr.Fprintf(ioutil.Discard, "%s %d", "warm", 1)
+	n := testing.AllocsPerRun(100, func() {
+		pr.Fprintf(ioutil.Discard, "%s %x", "hello", 12345)
+		b = pr.Appendf(b[:0], "%d", 12345)
+	})
+	if n != 0 {
+		t.Errorf("got %v allocs per run, want 0", n)
+	}
+}
+
+type asciiStringer struct{}
+
+func (asciiStringer) String() string { return "ünïcode\n" }
+
+func TestPrinterASCII(t *testing.T) {
+	var pr Printer
+	pr.SetASCII(true)
+	for _, tt := range []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{"%s", "日本\x00", `\u65e5\u672c\x00`},
+		{"%s", "a\tb\xffc\"\\", `a\tb\xffc"\`},
+		{"%.2s", "日本語", `\u65e5\u672c`},
+		{"%-8.1s|", "日本語", `\u65e5  |`},
+		{"%s", []byte("é\n"), `\u00e9\n`},
+		{"%v", []string{"é", "x"}, `[\u00e9 x]`},
+		{"%v", map[string]int{"ö": 1}, `map[\u00f6:1]`},
+		{"%+v", struct{ Name string }{"Zoë"}, `{Name:Zo\u00eb}`},
+		{"%v", asciiStringer{}, `\u00fcn\u00efcode\n`},
+		{"%v", errors.New("bad\tbyte \xff"), `bad\tbyte \xff`},
+		{"%q", "é", `"\u00e9"`},
+		{"%#q", "é", `"\u00e9"`},
+		{"%#q", "e", "`e`"},
+		{"%c", 'é', `\u00e9`},
+		{"%c", 'e', "e"},
+		{"%q", 'é', `'\u00e9'`},
+		{"%#U", 'é', "U+00E9"},
+		{"%#U", 'e', "U+0065 'e'"},
+		{"%5s|", "é", `\u00e9|`},
+		{"%8s|", "é", `  \u00e9|`},
+	} {
+		if got := pr.Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("Sprintf(%q, %v) = %q, want %q", tt.format, tt.arg, got, tt.want)
+		}
+	}
+	pr.SetASCII(false)
+	if got := pr.Sprintf("%s", "é"); got != "é" {
+		t.Errorf("after SetASCII(false): got %q", got)
+	}
+}
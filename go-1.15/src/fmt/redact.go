@@ -0,0 +1,57 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import "reflect"
+
+// Redactor is implemented by values that hold secrets, such as passwords
+// or access tokens, and must not be printed. Whenever such a value is
+// printed, with any verb other than %T and %p and also as an element of
+// a composite value, the string returned by Redact is printed in its
+// place. Redactor takes precedence over Formatter, GoStringer, error,
+// Stringer and functions registered with RegisterVerb.
+//
+// If the value is an unexported struct field, Redact cannot be called
+// and the placeholder "[REDACTED]" is printed instead.
+//
+// Struct fields whose type does not implement Redactor can be marked
+// as secret with the struct tag `fmt:"redact"`, which prints the
+// placeholder "[REDACTED]" in place of the field value.
+type Redactor interface {
+	Redact() string
+}
+
+const redactedString = "[REDACTED]"
+
+var redactorType = reflect.TypeOf((*Redactor)(nil)).Elem()
+
+// handleRedactor prints the placeholder for p.arg if it is a Redactor.
+func (p *pp) handleRedactor(verb rune) (handled bool) {
+	r, ok := p.arg.(Redactor)
+	if !ok {
+		return false
+	}
+	defer p.catchPanic(p.arg, verb, "Redact")
+	p.fmtRedacted(r.Redact(), verb)
+	return true
+}
+
+// fmtRedacted prints the placeholder s for a secret value.
+// Verbs that do not apply to strings print it as %s would,
+// as reporting a bad verb would print the value.
+func (p *pp) fmtRedacted(s string, verb rune) {
+	if verb == 'q' {
+		p.fmt.fmtQ(s)
+		return
+	}
+	p.fmt.fmtS(s)
+}
+
+// redactedField reports whether the i'th field of struct type t
+// is marked with the tag `fmt:"redact"`.
+func redactedField(t reflect.Type, i int) bool {
+	tag := t.Field(i).Tag
+	return tag != "" && tag.Get("fmt") == "redact"
+}
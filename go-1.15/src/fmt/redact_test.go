@@ -0,0 +1,68 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	"errors"
+	. "fmt"
+	"strings"
+	"testing"
+)
+
+type password string
+
+func (password) Redact() string { return "***" }
+
+// String must never be called on a Redactor.
+func (p password) String() string { return "leaked:" + string(p) }
+
+type token struct{ secret string }
+
+func (*token) Redact() string { return "<token>" }
+
+type credentials struct {
+	User     string
+	Password password
+	Key      string `fmt:"redact"`
+	pw       password
+}
+
+func TestRedactor(t *testing.T) {
+	creds := credentials{"gopher", "hunter2", "k3y", "hunter3"}
+	for _, tt := range []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{"%v", password("hunter2"), "***"},
+		{"%s", password("hunter2"), "***"},
+		{"%q", password("hunter2"), `"***"`},
+		{"%6v|", password("hunter2"), "   ***|"},
+		{"%d", password("hunter2"), "***"},
+		{"%x", password("hunter2"), "***"},
+		{"%#v", password("hunter2"), "***"},
+		{"%T", password("hunter2"), "fmt_test.password"},
+		{"%v", creds, "{gopher *** [REDACTED] [REDACTED]}"},
+		{"%+v", creds, "{User:gopher Password:*** Key:[REDACTED] pw:[REDACTED]}"},
+		{"%v", []password{"a", "b"}, "[*** ***]"},
+		{"%v", map[string]password{"k": "v"}, "map[k:***]"},
+		{"%v", &token{"t"}, "<token>"},
+		{"%v", []interface{}{&token{"t"}}, "[<token>]"},
+		{"%v", (*token)(nil), "<token>"},
+	} {
+		if got := Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("Sprintf(%q, ...) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+	for _, s := range []string{
+		Sprintf("%#v", creds),
+		Sprint(creds, password("x")),
+		Errorf("login %v failed: %w", creds, errors.New("denied")).Error(),
+	} {
+		if strings.Contains(s, "hunter") || strings.Contains(s, "k3y") || strings.Contains(s, "leaked") {
+			t.Errorf("secret printed: %q", s)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strconv"
 	"sync"
+	"time"
 	"unicode/utf8"
 )
 
@@ -161,12 +162,13 @@ type ss struct {
 // ssave holds the parts of ss that need to be
 // saved and restored on recursive scans.
 type ssave struct {
-	validSave bool // is or was a part of an actual ss.
-	nlIsEnd   bool // whether newline terminates scan
-	nlIsSpace bool // whether newline counts as white space
-	argLimit  int  // max value of ss.count for this arg; argLimit <= limit
-	limit     int  // max value of ss.count.
-	maxWid    int  // width of this arg.
+	validSave bool   // is or was a part of an actual ss.
+	nlIsEnd   bool   // whether newline terminates scan
+	nlIsSpace bool   // whether newline counts as white space
+	argLimit  int    // max value of ss.count for this arg; argLimit <= limit
+	limit     int    // max value of ss.count.
+	maxWid    int    // width of this arg.
+	layout    string // time layout given as %{layout} for this arg.
 }
 
 // The Read method is only in ScanState so that ScanState
@@ -389,6 +391,7 @@ func newScanState(r io.Reader, nlIsSpace, nlIsEnd bool) (s *ss, old ssave) {
 	s.limit = hugeWid
 	s.argLimit = hugeWid
 	s.maxWid = hugeWid
+	s.layout = ""
 	s.validSave = true
 	s.count = 0
 	return
@@ -936,6 +939,70 @@ const (
 	uintptrBits = 32 << (^uintptr(0) >> 63)
 )
 
+// scanDuration scans a time.Duration. The %v and %s verbs accept the
+// syntax of time.ParseDuration, such as "1h30m", as well as a plain
+// integer number of nanoseconds; the integer verbs scan nanoseconds.
+func (s *ss) scanDuration(verb rune) time.Duration {
+	if verb != 'v' && verb != 's' {
+		return time.Duration(s.scanInt(verb, 64))
+	}
+	s.SkipSpace()
+	s.notEOF()
+	tok := string(s.token(true, notSpace))
+	d, err := time.ParseDuration(tok)
+	if err != nil {
+		i, ierr := strconv.ParseInt(tok, 10, 64)
+		if ierr != nil {
+			s.error(err)
+		}
+		d = time.Duration(i)
+	}
+	return d
+}
+
+// scanTime scans a time.Time using the layout given in the format as
+// %{layout}v, or time.RFC3339 if there is none. The input consumed is as
+// many space-separated words as there are in the layout.
+func (s *ss) scanTime(verb rune) time.Time {
+	if !s.okVerb(verb, "sv", "time.Time") {
+		return time.Time{}
+	}
+	layout := s.layout
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	words := 0
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != ' ' && (i == 0 || layout[i-1] == ' ') {
+			words++
+		}
+	}
+	s.SkipSpace()
+	s.notEOF()
+	for n := 0; n < words; n++ {
+		if n > 0 {
+			// Keep the spaces between words; time.Parse matches them.
+			for {
+				r := s.getRune()
+				if r == eof {
+					break
+				}
+				if r == '\n' || !isSpace(r) {
+					s.UnreadRune()
+					break
+				}
+				s.buf.writeRune(r)
+			}
+		}
+		s.token(false, notSpace)
+	}
+	t, err := time.Parse(layout, string(s.buf))
+	if err != nil {
+		s.error(err)
+	}
+	return t
+}
+
 // scanPercent scans a literal percent character.
 func (s *ss) scanPercent() {
 	s.SkipSpace()
@@ -1004,6 +1071,10 @@ func (s *ss) scanOne(verb rune, arg interface{}) {
 			s.notEOF()
 			*v = s.convertFloat(s.floatToken(), 64)
 		}
+	case *time.Duration:
+		*v = s.scanDuration(verb)
+	case *time.Time:
+		*v = s.scanTime(verb)
 	case *string:
 		*v = s.convertString(verb)
 	case *[]byte:
@@ -1202,6 +1273,20 @@ func (s *ss) doScanf(format string, a []interface{}) (numProcessed int, err erro
 			s.maxWid = hugeWid
 		}
 
+		// do we have {layout}?
+		s.layout = ""
+		if i <= end && format[i] == '{' {
+			j := i + 1
+			for j <= end && format[j] != '}' {
+				j++
+			}
+			if j > end {
+				s.errorString("missing } in layout")
+			}
+			s.layout = format[i+1 : j]
+			i = j + 1
+		}
+
 		c, w := utf8.DecodeRuneInString(format[i:])
 		i += w
 
@@ -1226,6 +1311,7 @@ func (s *ss) doScanf(format string, a []interface{}) (numProcessed int, err erro
 		s.scanOne(c, arg)
 		numProcessed++
 		s.argLimit = s.limit
+		s.layout = ""
 	}
 	if numProcessed < len(a) {
 		s.errorString("too many operands")
@@ -0,0 +1,64 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	. "fmt"
+	"testing"
+	"time"
+)
+
+func TestScanDuration(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{" -1.5s", -1500 * time.Millisecond},
+		{"0", 0},
+		{"1500", 1500},
+	} {
+		var d time.Duration
+		if _, err := Sscan(tt.input, &d); err != nil || d != tt.want {
+			t.Errorf("Sscan(%q) = %v, %v; want %v", tt.input, d, err, tt.want)
+		}
+	}
+
+	var d, e time.Duration
+	if _, err := Sscanf("5m 10", "%v %x", &d, &e); err != nil || d != 5*time.Minute || e != 16 {
+		t.Errorf("Sscanf = %v, %v, %v", d, e, err)
+	}
+	if _, err := Sscan("1x", &d); err == nil {
+		t.Errorf("Sscan(1x): expected error")
+	}
+}
+
+func TestScanTime(t *testing.T) {
+	var (
+		tm time.Time
+		d  time.Duration
+	)
+	n, err := Sscanf("2020-09-09 17:10 after 1h30m", "%{2006-01-02 15:04}v after %v", &tm, &d)
+	want := time.Date(2020, 9, 9, 17, 10, 0, 0, time.UTC)
+	if n != 2 || err != nil || !tm.Equal(want) || d != 90*time.Minute {
+		t.Errorf("Sscanf = %d, %v: %v, %v", n, err, tm, d)
+	}
+
+	var s string
+	n, err = Sscan("2020-09-09T17:10:00Z rest", &tm, &s)
+	if n != 2 || err != nil || !tm.Equal(want) || s != "rest" {
+		t.Errorf("Sscan = %d, %v: %v, %q", n, err, tm, s)
+	}
+
+	if _, err := Sscanf("9 Sep", "%{Jan 2}v", &tm); err == nil {
+		t.Errorf("Sscanf with mismatched layout: expected error")
+	}
+	if _, err := Sscanf("x", "%{2006v", &tm); err == nil {
+		t.Errorf("Sscanf with unterminated layout: expected error")
+	}
+	if _, err := Sscanf("2020", "%d", &tm); err == nil {
+		t.Errorf("Sscanf with %%d: expected error")
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+)
+
+// A Template is a compiled format string. Formatting with a Template
+// produces the same output as Printf with the format it was compiled
+// from, but the format is parsed only once, by Compile, instead of on
+// every call.
+//
+// A Template is safe for concurrent use by multiple goroutines.
+type Template struct {
+	format    string
+	directs   []directive
+	tail      string // literal text after the last directive
+	reordered bool   // whether the format has explicit argument indexes
+}
+
+// A directive is a verb with its flags, width, precision and argument
+// indexes, and the literal text before it.
+type directive struct {
+	lit   string
+	verb  rune
+	flags fmtFlags // widPresent and precPresent only for literal numbers
+	wid   int
+	prec  int
+
+	// The explicit argument indexes before the width, the
+	// precision and the verb, zero-based, or -1 if absent.
+	widIndex, precIndex, argIndex int
+
+	widStar, precStar bool
+	precDot           bool // precision present, whatever its value
+}
+
+// Compile parses a format string, as described in the package
+// documentation, into a Template. Compile uses the translation of format
+// in the catalog installed by SetCatalog, if any, at the time it is
+// called.
+//
+// Compile reports an error for a format that Printf would mark as bad
+// whatever its operands: one with a malformed or zero argument index,
+// an index before a literal width or precision, as in %[3]2d, or a
+// trailing % with no verb. Missing and extra operands, non-integer *
+// widths and indexes beyond the operands passed are not known until the
+// Template is executed, and are reported in its output as Printf would
+// report them.
+func Compile(format string) (*Template, error) {
+	t := &Template{format: format}
+	format = translate(format)
+	end := len(format)
+	lasti := 0
+	for i := 0; i < end; {
+		for i < end && format[i] != '%' {
+			i++
+		}
+		if i >= end {
+			break
+		}
+		start := i
+		i++
+		if i < end && format[i] == '%' {
+			// A plain %% is literal text.
+			t.tail += format[lasti:start] + "%"
+			i++
+			lasti = i
+			continue
+		}
+
+		d := directive{lit: t.tail + format[lasti:start], widIndex: -1, precIndex: -1, argIndex: -1}
+		t.tail = ""
+	flags:
+		for ; i < end; i++ {
+			switch format[i] {
+			case '#':
+				d.flags.sharp = true
+			case '0':
+				d.flags.zero = !d.flags.minus // Only allow zero padding to the left.
+			case '+':
+				d.flags.plus = true
+			case '-':
+				d.flags.minus = true
+				d.flags.zero = false // Do not pad with zeros to the right.
+			case ' ':
+				d.flags.space = true
+			default:
+				break flags
+			}
+		}
+
+		var err error
+		afterIndex := false
+		if d.widIndex, i, afterIndex, err = compileArgNumber(format, i, start); err != nil {
+			return nil, err
+		}
+		if i < end && format[i] == '*' {
+			i++
+			d.widStar = true
+			afterIndex = false
+		} else {
+			d.wid, d.flags.widPresent, i = parsenum(format, i, end)
+			if afterIndex && d.flags.widPresent {
+				return nil, errors.New("fmt: argument index before width in " + strconv.Quote(format[start:i]))
+			}
+		}
+
+		if i+1 < end && format[i] == '.' {
+			i++
+			d.precDot = true
+			if afterIndex {
+				return nil, errors.New("fmt: argument index before precision in " + strconv.Quote(format[start:i]))
+			}
+			if d.precIndex, i, afterIndex, err = compileArgNumber(format, i, start); err != nil {
+				return nil, err
+			}
+			if i < end && format[i] == '*' {
+				i++
+				d.precStar = true
+				afterIndex = false
+			} else {
+				d.prec, d.flags.precPresent, i = parsenum(format, i, end)
+				d.flags.precPresent = true
+			}
+		}
+
+		if !afterIndex {
+			if d.argIndex, i, _, err = compileArgNumber(format, i, start); err != nil {
+				return nil, err
+			}
+		}
+		t.reordered = t.reordered || d.widIndex >= 0 || d.precIndex >= 0 || d.argIndex >= 0
+
+		if i >= end {
+			return nil, errors.New("fmt: missing verb in " + strconv.Quote(format[start:]))
+		}
+		verb, size := rune(format[i]), 1
+		if verb >= utf8.RuneSelf {
+			verb, size = utf8.DecodeRuneInString(format[i:])
+		}
+		i += size
+		d.verb = verb
+		t.directs = append(t.directs, d)
+		lasti = i
+	}
+	t.tail += format[lasti:]
+	return t, nil
+}
+
+// MustCompile is like Compile but panics if the format cannot be
+// compiled. It simplifies the initialization of global variables
+// holding Templates.
+func MustCompile(format string) *Template {
+	t, err := Compile(format)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// compileArgNumber parses the explicit argument index, if any, at
+// format[i:], for the directive starting at format[start]. It returns
+// the zero-based index or -1, the index of the next byte of the format,
+// and whether an index was found.
+func compileArgNumber(format string, i, start int) (index, newi int, found bool, err error) {
+	if len(format) <= i || format[i] != '[' {
+		return -1, i, false, nil
+	}
+	index, wid, ok := parseArgNumber(format[i:])
+	if !ok || index < 0 {
+		return 0, 0, false, errors.New("fmt: bad argument index in " + strconv.Quote(format[start:i+wid]))
+	}
+	return index, i + wid, true, nil
+}
+
+// String returns the format string t was compiled from.
+func (t *Template) String() string {
+	return t.format
+}
+
+// Execute formats the operands according to t and writes to w.
+// It returns the number of bytes written and any write error encountered.
+func (t *Template) Execute(w io.Writer, a ...interface{}) (n int, err error) {
+	p := newPrinter()
+	p.doTemplate(t, a)
+	n, err = w.Write(p.buf)
+	p.free()
+	return
+}
+
+// Append formats the operands according to t, appends the result to b
+// and returns the extended buffer. As with Appendf, the output is written
+// directly into b, so the operands must not share memory with
+// b[len(b):cap(b)].
+func (t *Template) Append(b []byte, a ...interface{}) []byte {
+	p := newPrinter()
+	buf := p.buf
+	p.buf = b
+	p.doTemplate(t, a)
+	b = p.buf
+	p.buf = buf
+	p.free()
+	return b
+}
+
+// templateArgNum returns the operand selected by an explicit index, or
+// argNum if there is none, as argNumber does.
+func (p *pp) templateArgNum(argNum, index, numArgs int) int {
+	if index < 0 {
+		return argNum
+	}
+	if index < numArgs {
+		return index
+	}
+	p.goodArgNum = false
+	return argNum
+}
+
+// doTemplate is doPrintf for a format compiled into t.
+func (p *pp) doTemplate(t *Template, a []interface{}) {
+	argNum := 0
+	p.reordered = t.reordered
+	for i := range t.directs {
+		d := &t.directs[i]
+		p.buf.writeString(d.lit)
+		p.goodArgNum = true
+		p.fmt.fmtFlags = d.flags
+		p.fmt.wid = d.wid
+		p.fmt.prec = d.prec
+
+		argNum = p.templateArgNum(argNum, d.widIndex, len(a))
+		if d.widStar {
+			p.fmt.wid, p.fmt.widPresent, argNum = intFromArg(a, argNum)
+			if !p.fmt.widPresent {
+				p.buf.writeString(badWidthString)
+			}
+			if p.fmt.wid < 0 {
+				p.fmt.wid = -p.fmt.wid
+				p.fmt.minus = true
+				p.fmt.zero = false // Do not pad with zeros to the right.
+			}
+		}
+
+		if d.precDot {
+			argNum = p.templateArgNum(argNum, d.precIndex, len(a))
+			if d.precStar {
+				p.fmt.prec, p.fmt.precPresent, argNum = intFromArg(a, argNum)
+				if p.fmt.prec < 0 {
+					p.fmt.prec = 0
+					p.fmt.precPresent = false
+				}
+				if !p.fmt.precPresent {
+					p.buf.writeString(badPrecString)
+				}
+			}
+		}
+
+		argNum = p.templateArgNum(argNum, d.argIndex, len(a))
+
+		switch verb := d.verb; {
+		case verb == '%': // Percent does not absorb operands and ignores f.wid and f.prec.
+			p.buf.writeByte('%')
+		case !p.goodArgNum:
+			p.badArgNum(verb)
+		case argNum >= len(a): // No argument left over to print for the current verb.
+			p.missingArg(verb)
+		case verb == 'v':
+			// Go syntax
+			p.fmt.sharpV = p.fmt.sharp
+			p.fmt.sharp = false
+			// Struct-field syntax
+			p.fmt.plusV = p.fmt.plus
+			p.fmt.plus = false
+			fallthrough
+		default:
+			p.printArg(a[argNum], verb)
+			argNum++
+		}
+	}
+	p.buf.writeString(t.tail)
+
+	if !p.reordered && argNum < len(a) {
+		p.fmt.clearflags()
+		p.buf.writeString(extraString)
+		for i, arg := range a[argNum:] {
+			if i > 0 {
+				p.buf.writeString(commaSpaceString)
+			}
+			if arg == nil {
+				p.buf.writeString(nilAngleString)
+			} else {
+				p.buf.writeString(reflect.TypeOf(arg).String())
+				p.buf.writeByte('=')
+				p.printArg(arg, 'v')
+			}
+		}
+		p.buf.writeByte(')')
+	}
+}
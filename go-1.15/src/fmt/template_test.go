@@ -0,0 +1,115 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	"bytes"
+	. "fmt"
+	"strings"
+	"testing"
+)
+
+// checkTemplate checks that a Template compiled from format prints the
+// operands as Sprintf does, or that Sprintf marks format as bad if it
+// does not compile.
+func checkTemplate(t *testing.T, format string, a ...interface{}) {
+	t.Helper()
+	want := Sprintf(format, a...)
+	tmpl, err := Compile(format)
+	if err != nil {
+		if !strings.Contains(want, "%!") {
+			t.Errorf("Compile(%q): %v, but Sprintf prints %q", format, err, want)
+		}
+		return
+	}
+	if got := string(tmpl.Append(nil, a...)); got != want {
+		t.Errorf("Compile(%q).Append(%v) = %q, want %q", format, a, got, want)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	for _, tt := range fmtTests {
+		checkTemplate(t, tt.fmt, tt.val)
+	}
+	for _, tt := range reorderTests {
+		checkTemplate(t, tt.fmt, tt.val...)
+	}
+	for _, tt := range startests {
+		checkTemplate(t, tt.fmt, tt.in...)
+	}
+	for _, format := range []string{
+		"", "plain", "100%%", "%%%d%%", "%d %d", "%[2]d %d", "%[3]*.[2]*[1]f %% %v",
+		"%-08.3f|%+ #x|% d", "%d%%%[1]d", "%[1]%%d", "%.", "%5.", "%x%X",
+	} {
+		checkTemplate(t, format)
+		checkTemplate(t, format, 1, 2.5, "x", -3)
+	}
+}
+
+func TestTemplateCompileErrors(t *testing.T) {
+	for _, format := range []string{
+		"%", "abc%", "%-5", "%[1]", "%[0]d", "%[x]d", "%[1d", "%[1]2d", "%[1].2d", "%.[]d", "%2147483648d",
+	} {
+		if _, err := Compile(format); err == nil {
+			t.Errorf("Compile(%q) succeeded", format)
+		}
+	}
+}
+
+func TestTemplateExecute(t *testing.T) {
+	tmpl := MustCompile("%s=%d\n")
+	var buf bytes.Buffer
+	n, err := tmpl.Execute(&buf, "x", 1)
+	if err != nil || n != 4 || buf.String() != "x=1\n" {
+		t.Errorf("Execute = %d, %v, wrote %q; want 4, nil, %q", n, err, buf.String(), "x=1\n")
+	}
+	if got, want := string(tmpl.Append([]byte("> "), "y", 2)), "> y=2\n"; got != want {
+		t.Errorf("Append = %q, want %q", got, want)
+	}
+	if got := tmpl.String(); got != "%s=%d\n" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestMustCompilePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustCompile of bad format did not panic")
+		}
+	}()
+	MustCompile("%[0]d")
+}
+
+func TestTemplateMallocs(t *testing.T) {
+	tmpl := MustCompile("%s %d %x")
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = tmpl.Append(buf[:0], "xyz", 7, 255)
+	})
+	// Converting the operands to interfaces may allocate,
+	// but formatting them must not.
+	if allocs > 1 {
+		t.Errorf("Append allocates %v times, want at most 1", allocs)
+	}
+}
+
+func BenchmarkTemplateAppend(b *testing.B) {
+	tmpl := MustCompile("%s: request %d from %s took %dms (%x)\n")
+	b.RunParallel(func(pb *testing.PB) {
+		var buf []byte
+		for pb.Next() {
+			buf = tmpl.Append(buf[:0], "GET", 42, "10.0.0.1", 17, 0xbeef)
+		}
+	})
+}
+
+func BenchmarkSprintfLogLine(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		var buf []byte
+		for pb.Next() {
+			buf = Appendf(buf[:0], "%s: request %d from %s took %dms (%x)\n", "GET", 42, "10.0.0.1", 17, 0xbeef)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// A VerbFunc formats arg for a verb registered with RegisterVerb or
+// RegisterTypeVerb. It writes its output to f and may consult f for the
+// width, precision and flags of the directive. A VerbFunc may call
+// Fprintf(f, ...) to format parts of arg with other verbs.
+type VerbFunc func(f State, verb rune, arg interface{})
+
+// RegisterVerb registers fn as the formatting function for verb.
+// It is used for operands of any type, except those that implement
+// Formatter or have a function registered by RegisterTypeVerb for verb.
+// Registering a nil fn removes the registration.
+//
+// The verbs defined by this package, and the characters that may start
+// a flag, width, precision or argument index, cannot be registered;
+// RegisterVerb panics if verb is one of them. Registrations are global,
+// so they are best made from an init function of the package that
+// defines the verb.
+func RegisterVerb(verb rune, fn VerbFunc) {
+	registerVerb(nil, verb, fn)
+}
+
+// RegisterTypeVerb is like RegisterVerb, but fn is only used for operands
+// whose dynamic type is typ. It takes precedence over functions registered
+// with RegisterVerb and over a Format method of typ.
+func RegisterTypeVerb(typ reflect.Type, verb rune, fn VerbFunc) {
+	if typ == nil {
+		panic("fmt: RegisterTypeVerb with nil type")
+	}
+	registerVerb(typ, verb, fn)
+}
+
+// A verbKey identifies a registered verb. typ is nil for verbs
+// registered for all types.
+type verbKey struct {
+	typ  reflect.Type
+	verb rune
+}
+
+var customVerbs struct {
+	mu sync.Mutex   // serializes changes
+	m  atomic.Value // map[verbKey]VerbFunc, replaced on every change
+}
+
+func registerVerb(typ reflect.Type, verb rune, fn VerbFunc) {
+	if reservedVerb(verb) {
+		panic("fmt: cannot register verb " + strconv.QuoteRune(verb))
+	}
+	customVerbs.mu.Lock()
+	defer customVerbs.mu.Unlock()
+	old, _ := customVerbs.m.Load().(map[verbKey]VerbFunc)
+	m := make(map[verbKey]VerbFunc, len(old)+1)
+	for k, f := range old {
+		m[k] = f
+	}
+	k := verbKey{typ, verb}
+	if fn == nil {
+		delete(m, k)
+	} else {
+		m[k] = fn
+	}
+	customVerbs.m.Store(m)
+}
+
+// reservedVerb reports whether verb is defined by this package
+// or cannot be used as a verb in a format string.
+func reservedVerb(verb rune) bool {
+	switch verb {
+	case 'v', 'T', 'p', 't', 'b', 'c', 'd', 'o', 'O', 'q', 'x', 'X', 'U',
+		'e', 'E', 'f', 'F', 'g', 'G', 's', 'w',
+		'%', '+', '-', '#', ' ', '[', ']', '.', '*':
+		return true
+	}
+	return '0' <= verb && verb <= '9' || verb < ' ' || verb == 0x7f || verb > utf8.MaxRune
+}
+
+// customVerb returns the function registered for formatting arg with verb,
+// or nil if there is none. Redactors are never formatted by registered
+// functions.
+func customVerb(arg interface{}, verb rune) VerbFunc {
+	m, _ := customVerbs.m.Load().(map[verbKey]VerbFunc)
+	if len(m) == 0 {
+		return nil
+	}
+	if _, ok := arg.(Redactor); ok {
+		return nil
+	}
+	if arg != nil {
+		if fn := m[verbKey{reflect.TypeOf(arg), verb}]; fn != nil {
+			return fn
+		}
+		if _, ok := arg.(Formatter); ok {
+			return nil
+		}
+	}
+	return m[verbKey{nil, verb}]
+}
+
+// printCustom formats arg with the registered function fn.
+func (p *pp) printCustom(fn VerbFunc, arg interface{}, verb rune) {
+	defer p.catchPanic(arg, verb, "VerbFunc")
+	fn(p, verb, arg)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fmt_test
+
+import (
+	. "fmt"
+	"reflect"
+	"testing"
+)
+
+type cents int64
+
+type formattedCents int64
+
+func (c formattedCents) Format(f State, verb rune) {
+	Fprintf(f, "formatted(%d)", int64(c))
+}
+
+func TestRegisterVerb(t *testing.T) {
+	defer RegisterVerb('y', nil)
+	defer RegisterTypeVerb(reflect.TypeOf(cents(0)), 'y', nil)
+	defer RegisterTypeVerb(reflect.TypeOf(cents(0)), 'z', nil)
+	defer RegisterTypeVerb(reflect.TypeOf(formattedCents(0)), 'm', nil)
+	// A variable format keeps vet from rejecting the unknown verb.
+	y := "%y"
+
+	if got, want := Sprintf(y, 1), "%!y(int=1)"; got != want {
+		t.Errorf("before registration: got %q, want %q", got, want)
+	}
+	RegisterVerb('y', func(f State, verb rune, arg interface{}) {
+		wid, ok := f.Width()
+		if !ok {
+			wid = -1
+		}
+		Fprintf(f, "y[%v,%d,%t]", arg, wid, f.Flag('+'))
+	})
+	dollars := func(f State, verb rune, arg interface{}) {
+		c := int64(arg.(cents))
+		Fprintf(f, "$%d.%02d", c/100, c%100)
+	}
+	RegisterTypeVerb(reflect.TypeOf(cents(0)), 'y', dollars)
+	RegisterTypeVerb(reflect.TypeOf(cents(0)), 'z', dollars)
+	RegisterTypeVerb(reflect.TypeOf(formattedCents(0)), 'm', func(f State, verb rune, arg interface{}) {
+		Fprintf(f, "registered(%d)", int64(arg.(formattedCents)))
+	})
+
+	for _, tt := range []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{"%y", 1, "y[1,-1,false]"},
+		{"%+5y", "s", "y[s,5,true]"},
+		{"%y", nil, "y[<nil>,-1,false]"},
+		{"%y", cents(1234), "$12.34"},
+		{"%y", []cents{5, 250}, "y[[5 250],-1,false]"},
+		{"%z", []cents{5, 250}, "[$0.05 $2.50]"},
+		{"%z", struct{ C cents }{7}, "{$0.07}"},
+		{"%z", 1, "%!z(int=1)"},
+		{"%v", cents(1234), "1234"},
+		{"%y", formattedCents(3), "formatted(3)"},
+		{"%m", formattedCents(3), "registered(3)"},
+		{"%m", cents(3), "%!m(fmt_test.cents=3)"},
+	} {
+		if got := Sprintf(tt.format, tt.arg); got != tt.want {
+			t.Errorf("Sprintf(%q, %#v) = %q, want %q", tt.format, tt.arg, got, tt.want)
+		}
+	}
+
+	RegisterVerb('y', func(f State, verb rune, arg interface{}) {
+		panic("oops")
+	})
+	if got, want := Sprintf(y, 1), "%!y(PANIC=VerbFunc method: oops)"; got != want {
+		t.Errorf("panicking VerbFunc: got %q, want %q", got, want)
+	}
+}
+
+func TestRegisterReservedVerb(t *testing.T) {
+	for _, verb := range []rune{'v', 'd', 'w', '%', '3', '[', ' ', '\n'} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterVerb(%q) did not panic", verb)
+				}
+			}()
+			RegisterVerb(verb, func(State, rune, interface{}) {})
+		}()
+	}
+}
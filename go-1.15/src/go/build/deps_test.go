@@ -70,7 +70,7 @@ import (
 var depsRules = `
 	# No dependencies allowed for any of these packages.
 	NONE
-	< container/list, container/ring,
+	< container/btree, container/list, container/radix, container/ring,
 	  internal/cfg, internal/cpu,
 	  internal/goversion, internal/nettrace,
 	  unicode/utf8, unicode/utf16, unicode,
@@ -96,6 +96,18 @@ var depsRules = `
 	< sort
 	< container/heap;
 
+	RUNTIME
+	< container/skiplist;
+
+	container/list
+	< container/orderedmap;
+
+	container/list
+	< container/lfu;
+
+	container/btree
+	< container/set;
+
 	RUNTIME
 	< io;
 
@@ -116,6 +128,9 @@ var depsRules = `
 	< context
 	< TIME;
 
+	TIME
+	< time/wheel;
+
 	# MATH is RUNTIME plus the basic math packages.
 	RUNTIME
 	< math
@@ -129,6 +144,12 @@ var depsRules = `
 	MATH
 	< math/rand;
 
+	MATH
+	< container/bloom;
+
+	RUNTIME
+	< math/fastrand;
+
 	MATH, unicode/utf8
 	< strconv;
 
@@ -142,6 +163,9 @@ var depsRules = `
 	bufio, path, strconv
 	< STR;
 
+	STR, TIME
+	< bufio/autoflush;
+
 	# OS is basic OS access, including helpers (path/filepath, os/exec, etc).
 	# OS includes string routines, but those must be layered above package os.
 	# OS does not include reflection.
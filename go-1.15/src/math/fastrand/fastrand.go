@@ -0,0 +1,72 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fastrand provides pseudo-random numbers from the generator that
+// the runtime keeps for each thread, as used by sync.Pool and map
+// iteration. Unlike the top-level functions of math/rand, which share a
+// single source guarded by a mutex, the functions of this package take no
+// lock and do not allocate, so they suit jitter and sampling on hot paths
+// of concurrent programs.
+//
+// The numbers cannot be seeded, so their sequence is not reproducible.
+// This package is not suitable for security-sensitive work; see the
+// crypto/rand package for that.
+package fastrand
+
+import (
+	"math/bits"
+	_ "unsafe" // for go:linkname
+)
+
+//go:linkname runtime_fastrand runtime.fastrand
+func runtime_fastrand() uint32
+
+// Uint32 returns a pseudo-random 32-bit value as a uint32.
+func Uint32() uint32 {
+	return runtime_fastrand()
+}
+
+// Uint64 returns a pseudo-random 64-bit value as a uint64.
+func Uint64() uint64 {
+	return uint64(runtime_fastrand())<<32 | uint64(runtime_fastrand())
+}
+
+// Intn returns, as an int, a non-negative pseudo-random number in [0,n).
+// It panics if n <= 0.
+func Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	if uint64(n) <= 1<<32-1 {
+		return int(uint32n(uint32(n)))
+	}
+	return int(uint64n(uint64(n)))
+}
+
+// uint32n returns a pseudo-random number in [0,n), using Lemire's
+// multiply-and-shift method with rejection of the biased samples.
+// See https://arxiv.org/abs/1805.10941.
+func uint32n(n uint32) uint32 {
+	m := uint64(Uint32()) * uint64(n)
+	if low := uint32(m); low < n {
+		thresh := -n % n
+		for low < thresh {
+			m = uint64(Uint32()) * uint64(n)
+			low = uint32(m)
+		}
+	}
+	return uint32(m >> 32)
+}
+
+// uint64n is like uint32n for 64-bit values.
+func uint64n(n uint64) uint64 {
+	hi, lo := bits.Mul64(Uint64(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(Uint64(), n)
+		}
+	}
+	return hi
+}
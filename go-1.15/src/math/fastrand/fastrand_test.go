@@ -0,0 +1,114 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fastrand
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIntn(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 10, 1000, 1<<31 - 1} {
+		for i := 0; i < 1000; i++ {
+			if v := Intn(n); v < 0 || v >= n {
+				t.Fatalf("Intn(%d) = %d, out of range", n, v)
+			}
+		}
+	}
+	if ^uint(0)>>63 != 0 {
+		n := int(^uint(0)>>2 + 12345)
+		for i := 0; i < 1000; i++ {
+			if v := Intn(n); v < 0 || v >= n {
+				t.Fatalf("Intn(%d) = %d, out of range", n, v)
+			}
+		}
+	}
+}
+
+func TestIntnPanics(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Intn(%d) did not panic", n)
+				}
+			}()
+			Intn(n)
+		}()
+	}
+}
+
+func TestIntnDistribution(t *testing.T) {
+	// A chi-squared test with 9 degrees of freedom;
+	// the critical value for p = 0.0001 is 33.7.
+	const n, samples = 10, 100000
+	var counts [n]int
+	for i := 0; i < samples; i++ {
+		counts[Intn(n)]++
+	}
+	chi2 := 0.0
+	for _, c := range counts {
+		d := float64(c) - samples/n
+		chi2 += d * d / (samples / n)
+	}
+	if chi2 > 33.7 {
+		t.Errorf("Intn(%d) counts %v are not uniform (chi2 = %.1f)", n, counts, chi2)
+	}
+}
+
+func TestUint64Bits(t *testing.T) {
+	// Every bit must vary.
+	var or, and uint64 = 0, ^uint64(0)
+	for i := 0; i < 1000; i++ {
+		v := Uint64()
+		or |= v
+		and &= v
+	}
+	if or != ^uint64(0) || and != 0 {
+		t.Errorf("stuck bits: or = %#x, and = %#x", or, and)
+	}
+}
+
+func TestAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		Uint32()
+		Uint64()
+		Intn(100)
+	})
+	if allocs != 0 {
+		t.Errorf("got %v allocations per run, want 0", allocs)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				if v := Intn(7); v < 0 || v >= 7 {
+					t.Errorf("Intn(7) = %d", v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkUint32(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Uint32()
+	}
+}
+
+func BenchmarkIntnParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Intn(1000)
+		}
+	})
+}
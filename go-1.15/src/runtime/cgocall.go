@@ -112,6 +112,9 @@ func cgocall(fn, arg unsafe.Pointer) int32 {
 	mp := getg().m
 	mp.ncgocall++
 	mp.ncgo++
+	if mp.ncgo == 1 && atomic.Load(&cgoTimed) != 0 {
+		atomic.Store64((*uint64)(unsafe.Pointer(&mp.cgoStart)), uint64(nanotime()))
+	}
 
 	// Reset traceback.
 	mp.cgoCallers[0] = 0
@@ -146,6 +149,9 @@ func cgocall(fn, arg unsafe.Pointer) int32 {
 	// reschedule us on to a different M.
 	mp.incgo = false
 	mp.ncgo--
+	if mp.ncgo == 0 {
+		cgoCallEnd(mp)
+	}
 
 	osPreemptExtExit(mp)
 
@@ -174,6 +180,25 @@ func cgocall(fn, arg unsafe.Pointer) int32 {
 	return errno
 }
 
+// cgoTimed is set by the first call to ReadCgoStats. Until then cgo
+// calls are not timed, so that programs that do not read the statistics
+// do not call nanotime twice per call (atomic).
+var cgoTimed uint32
+
+// cgoCallEnd records the time spent in the outermost cgo call on mp,
+// which has just returned.
+//go:nosplit
+func cgoCallEnd(mp *m) {
+	if mp.cgoStart == 0 {
+		// The call was not timed.
+		return
+	}
+	// ReadCgoStats reads these concurrently.
+	t := mp.cgoTime + nanotime() - mp.cgoStart
+	atomic.Store64((*uint64)(unsafe.Pointer(&mp.cgoTime)), uint64(t))
+	atomic.Store64((*uint64)(unsafe.Pointer(&mp.cgoStart)), 0)
+}
+
 // Call from C back to Go.
 //go:nosplit
 func cgocallbackg(ctxt uintptr) {
@@ -370,6 +395,9 @@ func unwindm(restore *bool) {
 		if mp.ncgo > 0 {
 			mp.incgo = false
 			mp.ncgo--
+			if mp.ncgo == 0 {
+				cgoCallEnd(mp)
+			}
 			osPreemptExtExit(mp)
 		}
 
@@ -0,0 +1,255 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "unsafe"
+
+// A cgroup CPU quota limits the CPU time the processes of the cgroup may
+// use in each period, independently of the number of CPUs they may run
+// on. A process with more running threads than its quota allows is
+// throttled for the rest of each period, so the scheduler is better off
+// with GOMAXPROCS sized to the quota.
+//
+// The cgroup of the process is read from /proc/self/cgroup, and the
+// hierarchies are assumed to be mounted at their usual places under
+// /sys/fs/cgroup. The quota of a cgroup is bounded by those of its
+// ancestors, so the smallest quota on the path to the root is used.
+// Within a cgroup namespace the path given by /proc/self/cgroup may not
+// exist below the mount point; the missing levels are then skipped.
+
+var (
+	procSelfCgroup   = []byte("/proc/self/cgroup\x00")
+	cgroupV2Root     = "/sys/fs/cgroup"
+	cgroupV1CPURoot  = "/sys/fs/cgroup/cpu"
+	cgroupV2CPUMax   = "/cpu.max"
+	cgroupV1CPUQuota = "/cpu.cfs_quota_us"
+	cgroupV1CPUPer   = "/cpu.cfs_period_us"
+)
+
+// cgroupBufSize bounds the length of the cgroup files and paths read.
+const cgroupBufSize = 4096
+
+// cgroupCPUQuota returns the CPU quota of the process, in CPUs, and
+// whether it has one. It does not allocate.
+func cgroupCPUQuota() (float64, bool) {
+	var buf [cgroupBufSize]byte
+	n := readCgroupFile(procSelfCgroup, buf[:])
+	if n <= 0 {
+		return 0, false
+	}
+	v1, v2 := parseProcCgroup(cgroupString(buf[:n]))
+	if v2 != "" {
+		if q, ok := cgroupWalk(cgroupV2Root, v2, true); ok {
+			return q, true
+		}
+	}
+	if v1 != "" {
+		return cgroupWalk(cgroupV1CPURoot, v1, false)
+	}
+	return 0, false
+}
+
+// cgroupMaxProcs returns the GOMAXPROCS value suited to the CPU quota of
+// the process: the quota rounded up, and at least 1. It returns 0 if the
+// process has no quota.
+func cgroupMaxProcs() int32 {
+	q, ok := cgroupCPUQuota()
+	if !ok {
+		return 0
+	}
+	n := int32(q)
+	if float64(n) < q {
+		n++
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// parseProcCgroup returns the cgroup v1 path of the cpu controller and
+// the cgroup v2 path from the contents of /proc/self/cgroup, whose lines
+// have the form "hierarchy-ID:controller-list:cgroup-path".
+func parseProcCgroup(s string) (v1, v2 string) {
+	for s != "" {
+		line := s
+		if i := index(s, "\n"); i >= 0 {
+			line, s = s[:i], s[i+1:]
+		} else {
+			s = ""
+		}
+		i := index(line, ":")
+		if i < 0 {
+			continue
+		}
+		id, rest := line[:i], line[i+1:]
+		i = index(rest, ":")
+		if i < 0 {
+			continue
+		}
+		controllers, path := rest[:i], rest[i+1:]
+		if id == "0" && controllers == "" {
+			v2 = path
+			continue
+		}
+		for controllers != "" {
+			c := controllers
+			if i := index(controllers, ","); i >= 0 {
+				c, controllers = controllers[:i], controllers[i+1:]
+			} else {
+				controllers = ""
+			}
+			if c == "cpu" {
+				v1 = path
+			}
+		}
+	}
+	return v1, v2
+}
+
+// cgroupWalk reads the quota of the cgroup path below root and of each of
+// its ancestors, in the format of cgroup v2 or v1, and returns the
+// smallest quota found.
+func cgroupWalk(root, path string, v2 bool) (float64, bool) {
+	var dir [cgroupBufSize]byte
+	min, found := 0.0, false
+	for {
+		if len(root)+len(path) <= len(dir) {
+			n := copy(dir[:], root)
+			if path != "/" {
+				n += copy(dir[n:], path)
+			}
+			var q float64
+			var ok bool
+			if v2 {
+				q, ok = cgroupV2Quota(dir[:n])
+			} else {
+				q, ok = cgroupV1Quota(dir[:n])
+			}
+			if ok && (!found || q < min) {
+				min, found = q, true
+			}
+		}
+		if path == "/" || path == "" {
+			break
+		}
+		i := len(path) - 1
+		for i > 0 && path[i] != '/' {
+			i--
+		}
+		if i == 0 {
+			path = "/"
+		} else {
+			path = path[:i]
+		}
+	}
+	return min, found
+}
+
+// cgroupV2Quota reads the quota of the cgroup v2 directory dir.
+func cgroupV2Quota(dir []byte) (float64, bool) {
+	var buf [64]byte
+	n := readCgroupAt(dir, cgroupV2CPUMax, buf[:])
+	if n <= 0 {
+		return 0, false
+	}
+	return parseCPUMax(cgroupString(buf[:n]))
+}
+
+// cgroupV1Quota reads the quota of the cgroup v1 directory dir.
+func cgroupV1Quota(dir []byte) (float64, bool) {
+	var qbuf, pbuf [32]byte
+	qn := readCgroupAt(dir, cgroupV1CPUQuota, qbuf[:])
+	if qn <= 0 {
+		return 0, false
+	}
+	pn := readCgroupAt(dir, cgroupV1CPUPer, pbuf[:])
+	if pn <= 0 {
+		return 0, false
+	}
+	return parseCFSQuota(cgroupString(qbuf[:qn]), cgroupString(pbuf[:pn]))
+}
+
+// parseCPUMax parses the contents of a cgroup v2 cpu.max file,
+// "$MAX $PERIOD", where $MAX is "max" when there is no quota.
+func parseCPUMax(s string) (float64, bool) {
+	s = trimNewline(s)
+	i := index(s, " ")
+	if i < 0 {
+		return 0, false
+	}
+	return cpuQuota(s[:i], s[i+1:])
+}
+
+// parseCFSQuota parses the contents of the cgroup v1 files
+// cpu.cfs_quota_us, which holds -1 when there is no quota, and
+// cpu.cfs_period_us.
+func parseCFSQuota(quota, period string) (float64, bool) {
+	return cpuQuota(trimNewline(quota), trimNewline(period))
+}
+
+func cpuQuota(quota, period string) (float64, bool) {
+	q, ok := atoi(quota)
+	if !ok || q <= 0 {
+		return 0, false
+	}
+	p, ok := atoi(period)
+	if !ok || p <= 0 {
+		return 0, false
+	}
+	return float64(q) / float64(p), true
+}
+
+// cgroupString returns the non-empty b as a string without copying it.
+// The string must not outlive b.
+func cgroupString(b []byte) string {
+	return slicebytetostringtmp((*byte)(noescape(unsafe.Pointer(&b[0]))), len(b))
+}
+
+func trimNewline(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// readCgroupAt reads the file name in directory dir into buf.
+func readCgroupAt(dir []byte, name string, buf []byte) int {
+	var path [cgroupBufSize]byte
+	if len(dir)+len(name) >= len(path) {
+		return -1
+	}
+	n := copy(path[:], dir)
+	n += copy(path[n:], name)
+	path[n] = 0
+	return readCgroupFile(path[:n+1], buf)
+}
+
+// readCgroupFile reads the file with the NUL-terminated name path into
+// buf and returns the number of bytes read, or -1 on failure. A file
+// that does not fit in buf is a failure.
+func readCgroupFile(path, buf []byte) int {
+	fd := open(&path[0], 0 /* O_RDONLY */, 0)
+	if fd < 0 {
+		return -1
+	}
+	n := 0
+	for n < len(buf) {
+		r := read(fd, noescape(unsafe.Pointer(&buf[n])), int32(len(buf)-n))
+		if r < 0 {
+			closefd(fd)
+			return -1
+		}
+		if r == 0 {
+			break
+		}
+		n += int(r)
+	}
+	closefd(fd)
+	if n == len(buf) {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime_test
+
+import (
+	. "runtime"
+	"testing"
+)
+
+func TestParseProcCgroup(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		v1, v2 string
+	}{
+		{"0::/user.slice/session-1.scope\n", "", "/user.slice/session-1.scope"},
+		{"12:memory:/a\n4:cpu,cpuacct:/docker/abc\n1:name=systemd:/b\n", "/docker/abc", ""},
+		{"3:cpuacct,cpu:/x\n0::/y", "/x", "/y"},
+		{"5:cpuset:/z\n", "", ""},
+		{"garbage\n", "", ""},
+		{"", "", ""},
+	} {
+		v1, v2 := ParseProcCgroup(test.in)
+		if v1 != test.v1 || v2 != test.v2 {
+			t.Errorf("ParseProcCgroup(%q) = %q, %q; want %q, %q", test.in, v1, v2, test.v1, test.v2)
+		}
+	}
+}
+
+func TestParseCPUQuota(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"max 100000\n", 0, false},
+		{"200000 100000\n", 2, true},
+		{"250000 100000", 2.5, true},
+		{"50000 100000\n", 0.5, true},
+		{"100000 0\n", 0, false},
+		{"100000\n", 0, false},
+	} {
+		got, ok := ParseCPUMax(test.in)
+		if got != test.want || ok != test.ok {
+			t.Errorf("ParseCPUMax(%q) = %v, %v; want %v, %v", test.in, got, ok, test.want, test.ok)
+		}
+	}
+
+	for _, test := range []struct {
+		quota, period string
+		want          float64
+		ok            bool
+	}{
+		{"-1\n", "100000\n", 0, false},
+		{"150000\n", "100000\n", 1.5, true},
+		{"400000\n", "100000\n", 4, true},
+		{"x\n", "100000\n", 0, false},
+	} {
+		got, ok := ParseCFSQuota(test.quota, test.period)
+		if got != test.want || ok != test.ok {
+			t.Errorf("ParseCFSQuota(%q, %q) = %v, %v; want %v, %v", test.quota, test.period, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestCPUQuota(t *testing.T) {
+	q, ok := CPUQuota()
+	if ok && q <= 0 {
+		t.Errorf("CPUQuota = %v, true; want a positive quota", q)
+	}
+	t.Logf("CPUQuota = %v, %v", q, ok)
+}
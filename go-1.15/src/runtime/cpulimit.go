@@ -0,0 +1,205 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "runtime/internal/atomic"
+
+// A CPU limit of L CPUs is enforced by letting only the n = ceil(L) Ps
+// with the lowest ids run Go code for a fraction L-(n-1) of each period,
+// and one P fewer for the rest of the period. sysmon, which does not need
+// a P, switches between the two. A P that may not run Go code is
+// throttled by its M the next time it enters the scheduler: the M moves
+// the P's goroutines to the global run queue, parks the P on
+// cpuLimit.throttled and stops. Throttled Ps are idle, so they are stopped
+// by stopTheWorld and run safe point functions like idle Ps, and
+// GOMAXPROCS is left alone. sysmon parks as usual once all Ps are idle,
+// and is woken when a P leaves the idle list or is throttled.
+
+// cpuLimitPeriod is the period over which the CPU limit is enforced.
+const cpuLimitPeriod = 50e6 // 50ms
+
+var cpuLimit struct {
+	bits    uint64 // float64bits of the limit in CPUs, or 0 if there is no limit (atomic)
+	limited uint32 // Ps with an id of procs or more may not run Go code (atomic)
+	procs   uint32 // (atomic)
+
+	// Protected by sched.lock.
+	throttled  puintptr // Ps throttled to enforce the limit, linked by p.link
+	nthrottled int32
+}
+
+// SetCPULimit sets a soft limit on the CPU time used to run Go code, in
+// CPUs, and returns the previous limit. A limit of 0 or less removes the
+// limit. Unlike GOMAXPROCS, the limit may be fractional: for example,
+// SetCPULimit(2.5) lets Go code use at most two and a half CPUs on average.
+//
+// The scheduler enforces the limit by keeping Ps idle, even if there are
+// goroutines ready to run, for the fraction of the time exceeding the
+// limit; with a limit below 1, no Go code runs for part of the time.
+// The limit is an average over periods of tens of milliseconds; it does
+// not apply to system calls and cgo calls. SetCPULimit does not change
+// GOMAXPROCS, which still bounds the number of Ps; a limit of GOMAXPROCS
+// or more has no effect.
+//
+// On js/wasm, SetCPULimit has no effect and returns 0.
+func SetCPULimit(cpus float64) float64 {
+	if GOARCH == "wasm" {
+		// There is no sysmon to switch the number of Ps that may
+		// run Go code, or to release throttled Ps.
+		return 0
+	}
+	if cpus < 0 || cpus != cpus {
+		cpus = 0
+	}
+	old := float64frombits(atomic.Xchg64(&cpuLimit.bits, float64bits(cpus)))
+	if cpus != old {
+		cpuLimitAdjust(nanotime())
+	}
+	return old
+}
+
+// cpuLimitAdjust sets the number of Ps that may run Go code at time now.
+// It is called by sysmon while there is a limit, and by SetCPULimit.
+func cpuLimitAdjust(now int64) {
+	cpus := float64frombits(atomic.Load64(&cpuLimit.bits))
+	procs := gomaxprocs
+	limited := cpus > 0 && cpus < float64(procs)
+	if limited {
+		n := int32(cpus)
+		if float64(n) < cpus {
+			n++
+		}
+		on := cpus - float64(n-1)
+		if float64(now%cpuLimitPeriod) >= on*cpuLimitPeriod {
+			n--
+		}
+		procs = n
+	}
+
+	lock(&sched.lock)
+	if limited {
+		atomic.Store(&cpuLimit.procs, uint32(procs))
+		atomic.Store(&cpuLimit.limited, 1)
+	} else {
+		atomic.Store(&cpuLimit.limited, 0)
+	}
+	// Return the throttled Ps that may run again to the idle list.
+	released := false
+	for pp := &cpuLimit.throttled; *pp != 0; {
+		p := (*pp).ptr()
+		if p.id < procs {
+			*pp = p.link
+			cpuLimit.nthrottled--
+			pidleput(p)
+			released = true
+		} else {
+			pp = &p.link
+		}
+	}
+	unlock(&sched.lock)
+	if released {
+		// A spinning M takes the goroutines queued meanwhile,
+		// and wakes more Ms as needed.
+		wakep()
+	}
+
+	// Ask the Ps running Go code that may not to stop.
+	if limited {
+		lock(&allpLock)
+		for _, p := range allp {
+			if p.id >= procs && p.status == _Prunning {
+				preemptone(p)
+			}
+		}
+		unlock(&allpLock)
+	}
+}
+
+// cpuLimited reports whether there is a CPU limit.
+//go:nosplit
+func cpuLimited() bool {
+	return atomic.Load64(&cpuLimit.bits) != 0
+}
+
+// cpuLimitThrottle is called by schedule on the M owning pp, the current
+// P. If the CPU limit does not let pp run Go code, it throttles pp, stops
+// the M until it is given another P and returns true.
+func cpuLimitThrottle(pp *p) bool {
+	if atomic.Load(&cpuLimit.limited) == 0 || uint32(pp.id) < atomic.Load(&cpuLimit.procs) {
+		return false
+	}
+	_g_ := getg()
+	lock(&sched.lock)
+	if sched.gcwaiting != 0 || atomic.Load(&cpuLimit.limited) == 0 || uint32(pp.id) < atomic.Load(&cpuLimit.procs) {
+		unlock(&sched.lock)
+		return false
+	}
+	spinning := _g_.m.spinning
+	if spinning {
+		_g_.m.spinning = false
+		if int32(atomic.Xadd(&sched.nmspinning, -1)) < 0 {
+			throw("cpuLimitThrottle: negative nmspinning")
+		}
+	}
+	moved := false
+	for {
+		gp, _ := runqget(pp)
+		if gp == nil {
+			break
+		}
+		globrunqput(gp)
+		moved = true
+	}
+	// As in handoffp, forEachP may be waiting for pp.
+	if pp.runSafePointFn != 0 && atomic.Cas(&pp.runSafePointFn, 1, 0) {
+		sched.safePointFn(pp)
+		sched.safePointWait--
+		if sched.safePointWait == 0 {
+			notewakeup(&sched.safePointNote)
+		}
+	}
+	if releasep() != pp {
+		throw("cpuLimitThrottle: wrong p")
+	}
+	pp.link = cpuLimit.throttled
+	cpuLimit.throttled.set(pp)
+	cpuLimit.nthrottled++
+	cpuLimitWakeSysmon()
+	unlock(&sched.lock)
+
+	// Pass on the search for work, or the goroutines, to an idle P
+	// that may still run Go code, if there is one. Each P that may
+	// not is throttled in turn, so this ends.
+	if spinning || moved {
+		wakep()
+	}
+	stopm()
+	return true
+}
+
+// cpuLimitWakeSysmon wakes sysmon if there is a CPU limit and sysmon is
+// parked. sysmon parks only while all Ps are idle; it must be awake while
+// a P runs Go code, to throttle it, and while a P is throttled, to
+// release it. sched.lock must be held.
+//go:nowritebarrierrec
+func cpuLimitWakeSysmon() {
+	if cpuLimited() && atomic.Load(&sched.sysmonwait) != 0 {
+		atomic.Store(&sched.sysmonwait, 0)
+		notewakeup(&sched.sysmonnote)
+	}
+}
+
+// cpuLimitStopTheWorld is called by stopTheWorldWithSema, with sched.lock
+// held, to stop the throttled Ps. It returns the number stopped.
+// procresize returns them to the idle list when the world starts.
+func cpuLimitStopTheWorld() int32 {
+	for p := cpuLimit.throttled.ptr(); p != nil; p = p.link.ptr() {
+		p.status = _Pgcstop
+	}
+	n := cpuLimit.nthrottled
+	cpuLimit.throttled = 0
+	cpuLimit.nthrottled = 0
+	return n
+}
@@ -418,6 +418,19 @@ func TestCgoNumGoroutine(t *testing.T) {
 	}
 }
 
+func TestCgoStats(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "plan9":
+		t.Skipf("skipping cgo stats test on %s", runtime.GOOS)
+	}
+	t.Parallel()
+	got := runTestProg(t, "testprogcgo", "CgoStats")
+	want := "OK\n"
+	if got != want {
+		t.Errorf("expected %q got %v", want, got)
+	}
+}
+
 func TestCatchPanic(t *testing.T) {
 	t.Parallel()
 	switch runtime.GOOS {
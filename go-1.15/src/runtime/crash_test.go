@@ -204,6 +204,10 @@ func TestLockedDeadlock2(t *testing.T) {
 	testDeadlock(t, "LockedDeadlock2")
 }
 
+func TestCPULimitDeadlock(t *testing.T) {
+	testDeadlock(t, "CPULimitDeadlock")
+}
+
 func TestGoexitDeadlock(t *testing.T) {
 	output := runTestProg(t, "testprog", "GoexitDeadlock")
 	want := "no goroutines (main called runtime.Goexit) - deadlock!"
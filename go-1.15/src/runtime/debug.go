@@ -11,9 +11,8 @@ func GOMAXPROCS(n int) int {
 		n = 1 // WebAssembly还没有线程，所以只能使用一个CPU。.
 	}
 
-	lock(&sched.lock)
-	ret := int(gomaxprocs)
-	unlock(&sched.lock)
+	// 查询当前设置不需要加锁：procresize以原子方式写入gomaxprocs，因此GOMAXPROCS(0)可以在热路径上调用。
+	ret := int(atomic.Load((*uint32)(unsafe.Pointer(&gomaxprocs))))
 	if n <= 0 || n == ret {
 		return ret
 	}
@@ -27,13 +26,85 @@ func GOMAXPROCS(n int) int {
 	return ret
 }
 
+// maxProcsSema serializes the changes of GOMAXPROCS made by WithMaxProcs
+// and protects maxProcsOverride.
+var maxProcsSema uint32 = 1
+
+var maxProcsOverride struct {
+	base   int   // GOMAXPROCS before the first active override
+	limits []int // limits of the active overrides
+}
+
+// WithMaxProcs calls f with GOMAXPROCS limited to at most n, and restores
+// the previous setting when f returns, even if f panics. It is meant for
+// phases of a program that benefit from less parallelism, such as a
+// memory-bound rebuild. If n < 1, f is called without changing GOMAXPROCS.
+//
+// Calls of WithMaxProcs may overlap, from different goroutines or nested:
+// while any are in progress, GOMAXPROCS is the smallest of their limits
+// and of the setting before the first of them started, and that setting is
+// restored when the last of them returns. Calls to GOMAXPROCS made in the
+// meantime are overridden.
+func WithMaxProcs(n int, f func()) {
+	if n < 1 {
+		f()
+		return
+	}
+	o := &maxProcsOverride
+	semacquire(&maxProcsSema)
+	if len(o.limits) == 0 {
+		o.base = GOMAXPROCS(0)
+	}
+	o.limits = append(o.limits, n)
+	applyMaxProcsOverrides()
+	semrelease(&maxProcsSema)
+
+	defer func() {
+		semacquire(&maxProcsSema)
+		for i, l := range o.limits {
+			if l == n {
+				o.limits = append(o.limits[:i], o.limits[i+1:]...)
+				break
+			}
+		}
+		applyMaxProcsOverrides()
+		semrelease(&maxProcsSema)
+	}()
+	f()
+}
+
+// applyMaxProcsOverrides sets GOMAXPROCS for the active overrides.
+// maxProcsSema must be held.
+func applyMaxProcsOverrides() {
+	o := &maxProcsOverride
+	procs := o.base
+	for _, l := range o.limits {
+		if l < procs {
+			procs = l
+		}
+	}
+	GOMAXPROCS(procs)
+}
+
 // NumCPU returns the number of logical CPUs usable by the current process.
 //
 // The set of available CPUs is checked by querying the operating system
 // at process startup. Changes to operating system CPU allocation after
-// process startup are not reflected.
+// process startup are reflected only after a call to UpdateNumCPU, or
+// once NotifyNumCPU has been called.
 func NumCPU() int {
-	return int(ncpu)
+	return int(getNumCPU())
+}
+
+// CPUQuota reports the CPU quota of the process, in CPUs, and whether it
+// has one. The quota, such as the cgroup CPU quota of a container on
+// Linux, limits the CPU time the process may use rather than the CPUs it
+// may run on, so it can be fractional and lower than NumCPU.
+//
+// CPUQuota reads the quota from the operating system at each call.
+// It reports no quota on systems other than Linux.
+func CPUQuota() (cpus float64, ok bool) {
+	return cgroupCPUQuota()
 }
 
 // NumCgoCall returns the number of cgo calls made by the current process.
@@ -45,6 +116,88 @@ func NumCgoCall() int64 {
 	return n
 }
 
+// CgoStats describes the cgo calls made by the current process, as
+// reported by ReadCgoStats. Times are in nanoseconds, and include the time
+// spent in calls from C back to Go made during the cgo calls. Only the cgo
+// calls started after the first call to ReadCgoStats are timed, so that
+// programs that do not read the statistics do not pay for timing.
+type CgoStats struct {
+	// Calls is the cumulative number of cgo calls, as reported
+	// by NumCgoCall.
+	Calls int64
+
+	// InFlight is the number of cgo calls in progress, including
+	// cgo calls made by Go code called back from C.
+	InFlight int
+
+	// Time is the cumulative time spent in cgo calls, including
+	// the calls in progress.
+	Time int64
+
+	// PerThread holds the statistics of each operating system
+	// thread, or M, that has made cgo calls.
+	PerThread []CgoThreadStats
+}
+
+// CgoThreadStats describes the cgo calls made by one operating system
+// thread.
+type CgoThreadStats struct {
+	// ID identifies the thread within the runtime, as shown by
+	// the schedtrace output; it is not the operating system's ID.
+	ID int64
+
+	// Calls is the cumulative number of cgo calls made by the thread.
+	Calls int64
+
+	// Time is the cumulative time the thread spent in cgo calls,
+	// including the call in progress.
+	Time int64
+
+	// Current is the time spent so far in the cgo call in progress,
+	// or 0 if the thread is not in a cgo call. A large Current
+	// indicates a thread stalled in C code.
+	Current int64
+}
+
+// ReadCgoStats populates s with statistics about cgo calls.
+// It reuses the PerThread slice of s if it has enough capacity.
+//
+// ReadCgoStats does not stop the world: the statistics of each thread are
+// read while the thread may be entering or leaving a cgo call, so they
+// are not an exactly consistent snapshot. Threads that have exited are not
+// reported, and their calls are no longer counted, as for NumCgoCall.
+func ReadCgoStats(s *CgoStats) {
+	s.Calls = 0
+	s.InFlight = 0
+	s.Time = 0
+	s.PerThread = s.PerThread[:0]
+	if atomic.Load(&cgoTimed) == 0 {
+		atomic.Store(&cgoTimed, 1)
+	}
+	now := nanotime()
+	for mp := (*m)(atomic.Loadp(unsafe.Pointer(&allm))); mp != nil; mp = mp.alllink {
+		calls := int64(mp.ncgocall)
+		if calls == 0 {
+			continue
+		}
+		t := CgoThreadStats{
+			ID:    mp.id,
+			Calls: calls,
+			Time:  atomic.Loadint64(&mp.cgoTime),
+		}
+		if start := atomic.Loadint64(&mp.cgoStart); start != 0 && now > start {
+			t.Current = now - start
+			t.Time += t.Current
+		}
+		if n := mp.ncgo; n > 0 {
+			s.InFlight += int(n)
+		}
+		s.Calls += t.Calls
+		s.Time += t.Time
+		s.PerThread = append(s.PerThread, t)
+	}
+}
+
 // NumGoroutine returns the number of goroutines that currently exist.
 func NumGoroutine() int {
 	return int(gcount())
@@ -132,6 +132,29 @@ func SetMaxThreads(threads int) int {
 	return setMaxThreads(threads)
 }
 
+// SetMaxGoroutines sets the maximum number of goroutines that the Go
+// program can have, not counting the runtime's own goroutines.
+// A go statement that would exceed the limit panics, with a message naming
+// the function being started and with the stack of the goroutine executing
+// the statement, or blocks until other goroutines exit, depending on
+// SetBlockOnMaxGoroutines. A limit of 0 or less removes the limit, which
+// is the initial setting. SetMaxGoroutines returns the previous setting.
+//
+// The limit turns bugs that create unbounded numbers of goroutines into
+// immediate failures, rather than a slow exhaustion of memory. Goroutines
+// started by the functions of timers, as by time.AfterFunc, are not
+// limited, but are counted.
+func SetMaxGoroutines(n int) int {
+	return setMaxGoroutines(n)
+}
+
+// SetBlockOnMaxGoroutines sets whether a go statement that would exceed
+// the limit set by SetMaxGoroutines blocks until enough goroutines exit,
+// rather than panicking. It returns the previous setting.
+func SetBlockOnMaxGoroutines(block bool) bool {
+	return setBlockOnMaxGoroutines(block)
+}
+
 // SetPanicOnFault controls the runtime's behavior when a program faults
 // at an unexpected (non-nil) address. Such faults are typically caused by
 // bugs such as runtime memory corruption, so the default response is to crash
@@ -5,9 +5,12 @@
 package debug_test
 
 import (
+	"fmt"
 	"internal/testenv"
 	"runtime"
 	. "runtime/debug"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -191,3 +194,74 @@ func TestSetMaxThreadsOvf(t *testing.T) {
 	nt := SetMaxThreads(1 << (30 + ^uint(0)>>63))
 	SetMaxThreads(nt) // restore previous value
 }
+
+func TestSetMaxGoroutines(t *testing.T) {
+	base := runtime.NumGoroutine()
+	defer SetMaxGoroutines(SetMaxGoroutines(base + 2))
+
+	stop := make(chan bool)
+	release := make(chan bool)
+	go func() { <-stop }()
+	go func() { <-release }()
+	func() {
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("go statement exceeding the limit did not panic")
+			}
+			msg := fmt.Sprint(r)
+			if !strings.Contains(msg, "goroutine limit") || !strings.Contains(msg, "TestSetMaxGoroutines") {
+				t.Errorf("unexpected panic message %q", msg)
+			}
+		}()
+		go func() { <-stop }()
+	}()
+
+	// In blocking mode, the go statement waits for a goroutine to exit.
+	// Timer functions are not limited. The arguments of the go statement
+	// must survive a garbage collection during the wait.
+	defer SetBlockOnMaxGoroutines(SetBlockOnMaxGoroutines(true))
+	time.AfterFunc(50*time.Millisecond, func() {
+		runtime.GC()
+		close(release)
+	})
+	done := make(chan bool)
+	p := new(int)
+	*p = 42
+	go func(p *int, s string) { done <- *p == 42 && s == "gogo" }(p, strings.Repeat("go", 2))
+	if !<-done {
+		t.Error("arguments of a blocked go statement were corrupted")
+	}
+	close(stop)
+}
+
+func TestSetMaxGoroutinesConcurrent(t *testing.T) {
+	// Many go statements wait at the limit while goroutines exit
+	// concurrently, so that waiters are admitted out of turn.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	const spawners, n = 8, 1000
+	start := make(chan bool)
+	var wg sync.WaitGroup
+	wg.Add(spawners * n)
+	for i := 0; i < spawners; i++ {
+		go func() {
+			<-start
+			for j := 0; j < n; j++ {
+				go wg.Done()
+			}
+		}()
+	}
+	defer SetMaxGoroutines(SetMaxGoroutines(runtime.NumGoroutine() + 2))
+	defer SetBlockOnMaxGoroutines(SetBlockOnMaxGoroutines(true))
+	done := make(chan bool)
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	close(start)
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		t.Fatal("go statements blocked at the goroutine limit did not all start")
+	}
+}
@@ -5,6 +5,9 @@
 package debug
 
 import (
+	"fmt"
+	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -14,16 +17,41 @@ func modinfo() string
 // ReadBuildInfo returns the build information embedded
 // in the running binary. The information is available only
 // in binaries built with module support.
+//
+// GoVersion and the GOOS, GOARCH and -compiler settings are filled in
+// from package runtime when the embedded information lacks them.
 func ReadBuildInfo() (info *BuildInfo, ok bool) {
-	return readBuildInfo(modinfo())
+	data := modinfo()
+	if len(data) < 32 {
+		return nil, false
+	}
+	info, err := ParseBuildInfo(data[16 : len(data)-16])
+	if err != nil {
+		return nil, false
+	}
+	if info.GoVersion == "" {
+		info.GoVersion = runtime.Version()
+	}
+	for _, s := range []BuildSetting{
+		{"-compiler", runtime.Compiler},
+		{"GOARCH", runtime.GOARCH},
+		{"GOOS", runtime.GOOS},
+	} {
+		if _, ok := info.Setting(s.Key); !ok {
+			info.Settings = append(info.Settings, s)
+		}
+	}
+	return info, true
 }
 
 // BuildInfo represents the build information read from
 // the running binary.
 type BuildInfo struct {
-	Path string    // The main package path
-	Main Module    // The module containing the main package
-	Deps []*Module // Module dependencies
+	GoVersion string         // Version of Go that produced this binary
+	Path      string         // The main package path
+	Main      Module         // The module containing the main package
+	Deps      []*Module      // Module dependencies
+	Settings  []BuildSetting // Other information about the build
 }
 
 // Module represents a module.
@@ -34,22 +62,108 @@ type Module struct {
 	Replace *Module // replaced by this module
 }
 
-func readBuildInfo(data string) (*BuildInfo, bool) {
-	if len(data) < 32 {
-		return nil, false
+// A BuildSetting is a key-value pair describing one setting that
+// influenced a build, such as GOOS or a build flag.
+type BuildSetting struct {
+	// Key and Value describe the build setting.
+	// Key must not contain an equals sign, space, tab, or newline.
+	// Value must not contain newlines ('\n').
+	Key, Value string
+}
+
+// Setting returns the value of the build setting with the given key,
+// and whether it is present.
+func (bi *BuildInfo) Setting(key string) (value string, ok bool) {
+	for _, s := range bi.Settings {
+		if s.Key == key {
+			return s.Value, true
+		}
+	}
+	return "", false
+}
+
+// String returns the build information in the line-oriented format
+// parsed by ParseBuildInfo, which is also the format printed by
+// "go version -m".
+func (bi *BuildInfo) String() string {
+	var b strings.Builder
+	if bi.GoVersion != "" {
+		fmt.Fprintf(&b, "go\t%s\n", bi.GoVersion)
+	}
+	if bi.Path != "" {
+		fmt.Fprintf(&b, "path\t%s\n", bi.Path)
+	}
+	var formatMod func(string, Module)
+	formatMod = func(word string, m Module) {
+		b.WriteString(word)
+		b.WriteByte('\t')
+		b.WriteString(m.Path)
+		b.WriteByte('\t')
+		b.WriteString(m.Version)
+		if m.Replace == nil {
+			b.WriteByte('\t')
+			b.WriteString(m.Sum)
+		} else {
+			b.WriteByte('\n')
+			formatMod("=>", *m.Replace)
+			return
+		}
+		b.WriteByte('\n')
+	}
+	if bi.Main != (Module{}) {
+		formatMod("mod", bi.Main)
+	}
+	for _, dep := range bi.Deps {
+		formatMod("dep", *dep)
+	}
+	for _, s := range bi.Settings {
+		key := s.Key
+		if quoteKey(key) {
+			key = strconv.Quote(key)
+		}
+		value := s.Value
+		if quoteValue(value) {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&b, "build\t%s=%s\n", key, value)
 	}
-	data = data[16 : len(data)-16]
+	return b.String()
+}
+
+// quoteKey reports whether key must be quoted in a build line.
+func quoteKey(key string) bool {
+	return len(key) == 0 || strings.ContainsAny(key, "= \t\r\n\"`")
+}
+
+// quoteValue reports whether value must be quoted in a build line.
+func quoteValue(value string) bool {
+	return strings.ContainsAny(value, " \t\r\n\"`")
+}
+
+// ParseBuildInfo parses build information in the format produced by
+// BuildInfo.String.
+func ParseBuildInfo(data string) (bi *BuildInfo, err error) {
+	lineNum := 1
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("could not parse Go build info: line %d: %w", lineNum, err)
+		}
+	}()
 
 	const (
-		pathLine = "path\t"
-		modLine  = "mod\t"
-		depLine  = "dep\t"
-		repLine  = "=>\t"
+		pathLine  = "path\t"
+		modLine   = "mod\t"
+		depLine   = "dep\t"
+		repLine   = "=>\t"
+		buildLine = "build\t"
+		goLine    = "go\t"
+		newline   = "\n"
+		tab       = "\t"
 	)
 
-	readEntryFirstLine := func(elem []string) (Module, bool) {
+	readModuleLine := func(elem []string) (Module, error) {
 		if len(elem) != 2 && len(elem) != 3 {
-			return Module{}, false
+			return Module{}, fmt.Errorf("expected 2 or 3 columns; got %d", len(elem))
 		}
 		sum := ""
 		if len(elem) == 3 {
@@ -59,48 +173,49 @@ func readBuildInfo(data string) (*BuildInfo, bool) {
 			Path:    elem[0],
 			Version: elem[1],
 			Sum:     sum,
-		}, true
+		}, nil
 	}
 
+	bi = new(BuildInfo)
 	var (
-		info = &BuildInfo{}
 		last *Module
 		line string
 		ok   bool
 	)
-	// Reverse of cmd/go/internal/modload.PackageBuildInfo
+	// Reverse of BuildInfo.String(), and of
+	// cmd/go/internal/modload.PackageBuildInfo
 	for len(data) > 0 {
-		i := strings.IndexByte(data, '\n')
-		if i < 0 {
+		line, data, ok = cut(data, newline)
+		if !ok {
 			break
 		}
-		line, data = data[:i], data[i+1:]
 		switch {
+		case strings.HasPrefix(line, goLine):
+			bi.GoVersion = line[len(goLine):]
 		case strings.HasPrefix(line, pathLine):
-			elem := line[len(pathLine):]
-			info.Path = elem
+			bi.Path = line[len(pathLine):]
 		case strings.HasPrefix(line, modLine):
-			elem := strings.Split(line[len(modLine):], "\t")
-			last = &info.Main
-			*last, ok = readEntryFirstLine(elem)
-			if !ok {
-				return nil, false
+			elem := strings.Split(line[len(modLine):], tab)
+			last = &bi.Main
+			*last, err = readModuleLine(elem)
+			if err != nil {
+				return nil, err
 			}
 		case strings.HasPrefix(line, depLine):
-			elem := strings.Split(line[len(depLine):], "\t")
+			elem := strings.Split(line[len(depLine):], tab)
 			last = new(Module)
-			info.Deps = append(info.Deps, last)
-			*last, ok = readEntryFirstLine(elem)
-			if !ok {
-				return nil, false
+			bi.Deps = append(bi.Deps, last)
+			*last, err = readModuleLine(elem)
+			if err != nil {
+				return nil, err
 			}
 		case strings.HasPrefix(line, repLine):
-			elem := strings.Split(line[len(repLine):], "\t")
+			elem := strings.Split(line[len(repLine):], tab)
 			if len(elem) != 3 {
-				return nil, false
+				return nil, fmt.Errorf("expected 3 columns for replacement; got %d", len(elem))
 			}
 			if last == nil {
-				return nil, false
+				return nil, fmt.Errorf("replacement with no module on previous line")
 			}
 			last.Replace = &Module{
 				Path:    elem[0],
@@ -108,7 +223,72 @@ func readBuildInfo(data string) (*BuildInfo, bool) {
 				Sum:     elem[2],
 			}
 			last = nil
+		case strings.HasPrefix(line, buildLine):
+			s, err := parseBuildSetting(line[len(buildLine):])
+			if err != nil {
+				return nil, err
+			}
+			bi.Settings = append(bi.Settings, s)
 		}
+		lineNum++
 	}
-	return info, true
+	return bi, nil
+}
+
+// parseBuildSetting parses the key=value text of a build line, in which
+// the key and the value may be quoted.
+func parseBuildSetting(kv string) (BuildSetting, error) {
+	var key, rawValue string
+	if strings.HasPrefix(kv, `"`) {
+		q := quotedPrefix(kv)
+		var err error
+		key, err = strconv.Unquote(q)
+		if err != nil {
+			return BuildSetting{}, err
+		}
+		kv = kv[len(q):]
+		if !strings.HasPrefix(kv, "=") {
+			return BuildSetting{}, fmt.Errorf("expected '=' after quoted key")
+		}
+		rawValue = kv[1:]
+	} else {
+		var ok bool
+		key, rawValue, ok = cut(kv, "=")
+		if !ok {
+			return BuildSetting{}, fmt.Errorf("invalid build line")
+		}
+	}
+	value := rawValue
+	if strings.HasPrefix(rawValue, `"`) {
+		var err error
+		value, err = strconv.Unquote(rawValue)
+		if err != nil {
+			return BuildSetting{}, err
+		}
+	}
+	if key == "" {
+		return BuildSetting{}, fmt.Errorf("empty key")
+	}
+	return BuildSetting{Key: key, Value: value}, nil
+}
+
+// quotedPrefix returns the prefix of s up to the end of the
+// double-quoted string it starts with, or s if the string is not closed.
+func quotedPrefix(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return s[:i+1]
+		}
+	}
+	return s
+}
+
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
 }
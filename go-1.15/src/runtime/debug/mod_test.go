@@ -0,0 +1,101 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug_test
+
+import (
+	"reflect"
+	. "runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestParseBuildInfoRoundTrip(t *testing.T) {
+	bi := &BuildInfo{
+		GoVersion: "go1.15.2",
+		Path:      "example.com/cmd/tool",
+		Main:      Module{Path: "example.com", Version: "(devel)"},
+		Deps: []*Module{
+			{Path: "golang.org/x/text", Version: "v0.3.3", Sum: "h1:abc="},
+			{
+				Path:    "example.com/old",
+				Version: "v1.0.0",
+				Replace: &Module{Path: "example.com/new", Version: "v1.1.0", Sum: "h1:def="},
+			},
+		},
+		Settings: []BuildSetting{
+			{Key: "GOOS", Value: "linux"},
+			{Key: "-ldflags", Value: "-s -w"},
+			{Key: "odd key=", Value: "tab\tand \"quote\""},
+		},
+	}
+	s := bi.String()
+	want := "go\tgo1.15.2\n" +
+		"path\texample.com/cmd/tool\n" +
+		"mod\texample.com\t(devel)\t\n" +
+		"dep\tgolang.org/x/text\tv0.3.3\th1:abc=\n" +
+		"dep\texample.com/old\tv1.0.0\n" +
+		"=>\texample.com/new\tv1.1.0\th1:def=\n" +
+		"build\tGOOS=linux\n" +
+		"build\t-ldflags=\"-s -w\"\n" +
+		"build\t\"odd key=\"=\"tab\\tand \\\"quote\\\"\"\n"
+	if s != want {
+		t.Errorf("String:\n%s\nwant:\n%s", s, want)
+	}
+
+	got, err := ParseBuildInfo(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, bi) {
+		t.Errorf("ParseBuildInfo(String()) = %+v, want %+v", got, bi)
+	}
+	if v, ok := got.Setting("-ldflags"); !ok || v != "-s -w" {
+		t.Errorf("Setting(-ldflags) = %q, %v", v, ok)
+	}
+	if _, ok := got.Setting("CGO_ENABLED"); ok {
+		t.Errorf("Setting(CGO_ENABLED) found")
+	}
+}
+
+func TestParseBuildInfoReplaceNoSum(t *testing.T) {
+	// Local directory replacements have no checksum.
+	bi := &BuildInfo{
+		Path: "example.com/cmd/tool",
+		Main: Module{Path: "example.com", Version: "(devel)"},
+		Deps: []*Module{{
+			Path:    "example.com/old",
+			Version: "v1.0.0",
+			Replace: &Module{Path: "../new", Version: "(devel)"},
+		}},
+	}
+	s := bi.String()
+	if !strings.Contains(s, "=>\t../new\t(devel)\t\n") {
+		t.Errorf("String:\n%s\nwant an empty checksum column for the replacement", s)
+	}
+	got, err := ParseBuildInfo(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, bi) {
+		t.Errorf("ParseBuildInfo(String()) = %+v, want %+v", got, bi)
+	}
+}
+
+func TestParseBuildInfoErrors(t *testing.T) {
+	for _, data := range []string{
+		"mod\tonlypath\n",
+		"=>\ta\tb\tc\n",
+		"dep\ta\tv1\n=>\tb\tv2\n",
+		"build\tnoequals\n",
+		"build\t=value\n",
+		"build\t\"key\"value\n",
+	} {
+		if _, err := ParseBuildInfo(data); err == nil {
+			t.Errorf("ParseBuildInfo(%q) succeeded", data)
+		} else if !strings.Contains(err.Error(), "line") {
+			t.Errorf("ParseBuildInfo(%q): error %q lacks line number", data, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debug
+
+import (
+	"sync"
+	"time"
+)
+
+// Implemented in package runtime.
+func setStallWatchdog(limit int64, dump bool)
+func stallWait() int64
+func stallWaitStop()
+
+var stallWatchdog struct {
+	mu      sync.Mutex
+	f       func(time.Duration)
+	enabled bool // f should be called on stalls
+	running bool // stallWatchdogLoop is running
+}
+
+// SetStallWatchdog enables a watchdog that detects when the scheduler is
+// wedged: goroutines are ready to run, but none has been scheduled for at
+// least d, for example because of a lock-up in C code or a stop-the-world
+// that cannot complete. A d of 0 or less disables the watchdog.
+//
+// The watchdog reports each stall once. If f is nil, it prints the state
+// of the scheduler, and the status and wait reason of each goroutine, to
+// standard error, as GODEBUG=schedtrace=X,scheddetail=1 does; this works
+// even if no goroutine can run. It does not print goroutine stacks: they
+// can only be read safely with the world stopped, which a stalled
+// scheduler may be unable to do. Otherwise, it calls f with the duration
+// of the stall so far, on a goroutine of its own; f therefore runs only
+// once the scheduler can run that goroutine, which may be after the stall
+// ends. To collect the goroutine stacks, f can call runtime.Stack with
+// all set to true.
+//
+// The watchdog checks for progress from the runtime's monitoring thread,
+// so stalls are detected with a delay of up to about 10ms.
+func SetStallWatchdog(d time.Duration, f func(stall time.Duration)) {
+	w := &stallWatchdog
+	w.mu.Lock()
+	w.f = f
+	w.enabled = f != nil && d > 0
+	if w.enabled && !w.running {
+		w.running = true
+		go stallWatchdogLoop()
+	} else if !w.enabled && w.running {
+		stallWaitStop()
+	}
+	w.mu.Unlock()
+	setStallWatchdog(int64(d), f == nil)
+}
+
+// stallWatchdogLoop calls the stall function for each stall reported by
+// the runtime. It exits once the watchdog is disabled or no longer has
+// a stall function.
+func stallWatchdogLoop() {
+	w := &stallWatchdog
+	for {
+		stall := stallWait()
+		w.mu.Lock()
+		if !w.enabled {
+			w.running = false
+			w.mu.Unlock()
+			return
+		}
+		f := w.f
+		w.mu.Unlock()
+		if stall >= 0 {
+			f(time.Duration(stall))
+		}
+	}
+}
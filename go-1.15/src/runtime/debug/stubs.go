@@ -15,3 +15,5 @@ func setMaxStack(int) int
 func setGCPercent(int32) int32
 func setPanicOnFault(bool) bool
 func setMaxThreads(int) int
+func setMaxGoroutines(int) int
+func setBlockOnMaxGoroutines(bool) bool
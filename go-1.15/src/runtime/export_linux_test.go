@@ -17,3 +17,9 @@ type EpollEvent epollevent
 func Epollctl(epfd, op, fd int32, ev unsafe.Pointer) int32 {
 	return epollctl(epfd, op, fd, (*epollevent)(ev))
 }
+
+var (
+	ParseProcCgroup = parseProcCgroup
+	ParseCPUMax     = parseCPUMax
+	ParseCFSQuota   = parseCFSQuota
+)
@@ -991,3 +991,17 @@ func MSpanCountAlloc(bits []byte) int {
 	s.gcmarkBits = (*gcBits)(unsafe.Pointer(&bits[0]))
 	return s.countAlloc()
 }
+
+// ForEachP runs a safe point function on every P, as the garbage
+// collector does, and returns the number of Ps it ran on.
+func ForEachP() int32 {
+	var n uint32
+	semacquire(&worldsema)
+	systemstack(func() {
+		forEachP(func(*p) {
+			atomic.Xadd(&n, 1)
+		})
+	})
+	semrelease(&worldsema)
+	return int32(n)
+}
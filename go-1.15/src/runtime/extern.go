@@ -133,6 +133,11 @@ It is a comma-separated list of name=val pairs setting these named variables:
 	because it also disables the conservative stack scanning used
 	for asynchronously preempted goroutines.
 
+	cgroupmaxprocs: setting cgroupmaxprocs=1 makes the default GOMAXPROCS
+	value the CPU quota of the process's cgroup, rounded up, if that is lower
+	than the number of CPUs. This is supported on Linux, for cgroup v1 and v2.
+	An explicit GOMAXPROCS setting takes precedence. See CPUQuota.
+
 The net, net/http, and crypto/tls packages also refer to debugging variables in GODEBUG.
 See the documentation for those packages for details.
 
@@ -140,7 +145,8 @@ The GOMAXPROCS variable limits the number of operating system threads that
 can execute user-level Go code simultaneously. There is no limit to the number of threads
 that can be blocked in system calls on behalf of Go code; those do not count against
 the GOMAXPROCS limit. This package's GOMAXPROCS function queries and changes
-the limit.
+the limit. By default the limit is the number of CPUs, or, with
+GODEBUG=cgroupmaxprocs=1, the CPU quota of the process if it is lower.
 
 The GORACE variable configures the race detector, for programs built using -race.
 See https://golang.org/doc/articles/race_detector.html for details.
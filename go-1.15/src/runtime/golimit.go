@@ -0,0 +1,168 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"runtime/internal/atomic"
+	"unsafe"
+)
+
+// goroutineLimit holds the limit on the number of goroutines set by
+// runtime/debug.SetMaxGoroutines.
+//
+// count is maintained by newproc1 and goexit0 whether or not there is
+// a limit, so that checking the limit costs a few atomic operations.
+// It includes the goroutines held by goroutines in waitq, which have
+// been created but not started, so count-nwait goroutines are running.
+var goroutineLimit struct {
+	count uint32 // goroutines that are not system goroutines (atomic)
+	max   uint32 // 0 if there is no limit (atomic)
+	block uint32 // go statements block rather than panic (atomic)
+	nwait uint32 // len(waitq) (atomic)
+
+	lock  mutex
+	waitq gQueue // goroutines blocked in goroutineLimitWait
+}
+
+//go:linkname setMaxGoroutines runtime/debug.setMaxGoroutines
+func setMaxGoroutines(n int) int {
+	if n < 0 {
+		n = 0
+	} else if n > 0x7fffffff { // MaxInt32
+		n = 0x7fffffff
+	}
+	old := atomic.Xchg(&goroutineLimit.max, uint32(n))
+	goroutineLimitAdmit()
+	return int(old)
+}
+
+//go:linkname setBlockOnMaxGoroutines runtime/debug.setBlockOnMaxGoroutines
+func setBlockOnMaxGoroutines(block bool) bool {
+	b := uint32(0)
+	if block {
+		b = 1
+	}
+	old := atomic.Xchg(&goroutineLimit.block, b)
+	goroutineLimitAdmit()
+	return old != 0
+}
+
+// goroutineLimitAdd is called by newproc1 to count a new goroutine
+// that is not a system goroutine, created by callergp. It reports
+// false if the limit is reached and go statements panic rather than
+// block; then the goroutine is not counted and must not be started.
+// Go statements executed on the system stack, such as by the
+// functions of timers, are not limited.
+func goroutineLimitAdd(callergp *g) bool {
+	n := atomic.Xadd(&goroutineLimit.count, 1)
+	max := atomic.Load(&goroutineLimit.max)
+	if max == 0 || n-atomic.Load(&goroutineLimit.nwait) <= max ||
+		atomic.Load(&goroutineLimit.block) != 0 || callergp != callergp.m.curg {
+		return true
+	}
+	goroutineLimitDone()
+	return false
+}
+
+// goroutineLimitDone uncounts a goroutine that is not a system
+// goroutine, and starts goroutines held in goroutineLimitWait that
+// are now within the limit. It is called by goexit0 on the system
+// stack, so it must not block.
+func goroutineLimitDone() {
+	atomic.Xadd(&goroutineLimit.count, -1)
+	// Pairs with the increment of nwait in goroutineLimitWait: either
+	// the waiter sees the new count, or we see the waiter.
+	if atomic.Load(&goroutineLimit.nwait) == 0 {
+		return
+	}
+	lock(&goroutineLimit.lock)
+	admitted := goroutineLimitAdmitLocked()
+	unlock(&goroutineLimit.lock)
+	for !admitted.empty() {
+		ready(admitted.pop(), 0, true)
+	}
+}
+
+// goroutineLimitHold reports whether newproc must not start newg,
+// created by callergp, until goroutines exit. newg has been counted.
+// If so, newg waits, rather than being runnable, until it is started
+// by goroutineLimitWait.
+func goroutineLimitHold(newg, callergp *g) bool {
+	max := atomic.Load(&goroutineLimit.max)
+	if max == 0 || atomic.Load(&goroutineLimit.block) == 0 ||
+		atomic.Load(&goroutineLimit.count)-atomic.Load(&goroutineLimit.nwait) <= max ||
+		callergp != callergp.m.curg || isSystemGoroutine(newg, false) {
+		return false
+	}
+	newg.waitreason = waitReasonGoroutineLimit
+	casgstatus(newg, _Grunnable, _Gwaiting)
+	return true
+}
+
+// goroutineLimitWait is called by newproc, once the arguments of the go
+// statement starting fn have been copied to the stack of newg, when
+// newproc must not start newg. newproc itself cannot block or grow the
+// stack. If newg is nil, the limit is reached and goroutineLimitWait
+// panics. Otherwise it waits for goroutines to exit before starting
+// newg.
+func goroutineLimitWait(fn *funcval, newg *g) {
+	if newg == nil {
+		var buf [20]byte
+		panic(plainError("runtime: goroutine limit of " + string(itoa(buf[:], uint64(atomic.Load(&goroutineLimit.max)))) +
+			" reached starting " + funcname(findfunc(fn.fn))))
+	}
+	gp := getg()
+	lock(&goroutineLimit.lock)
+	gp.param = nil
+	goroutineLimit.waitq.pushBack(gp)
+	atomic.Xadd(&goroutineLimit.nwait, 1)
+	// Goroutines may have exited, or the limit may have changed,
+	// since newproc checked it.
+	// Waiters ahead of gp may be admitted while gp is not; they must
+	// be readied before gp parks, as nothing else readies them.
+	admitted := goroutineLimitAdmitLocked()
+	for !admitted.empty() {
+		if wg := admitted.pop(); wg != gp {
+			goready(wg, 1)
+		}
+	}
+	if gp.param == nil {
+		goparkunlock(&goroutineLimit.lock, waitReasonGoroutineLimit, traceEvGoBlock, 1)
+	} else {
+		unlock(&goroutineLimit.lock)
+	}
+	gp.param = nil
+	goready(newg, 1)
+}
+
+// goroutineLimitAdmit starts the goroutines held in goroutineLimitWait
+// that are within a new limit.
+func goroutineLimitAdmit() {
+	lock(&goroutineLimit.lock)
+	admitted := goroutineLimitAdmitLocked()
+	unlock(&goroutineLimit.lock)
+	for !admitted.empty() {
+		goready(admitted.pop(), 1)
+	}
+}
+
+// goroutineLimitAdmitLocked removes from waitq, in order, the goroutines
+// whose held goroutines may start, marks them by setting their param,
+// and returns them. goroutineLimit.lock must be held.
+func goroutineLimitAdmitLocked() gList {
+	var admitted gList
+	for !goroutineLimit.waitq.empty() {
+		max := atomic.Load(&goroutineLimit.max)
+		if max != 0 && atomic.Load(&goroutineLimit.block) != 0 &&
+			atomic.Load(&goroutineLimit.count)-atomic.Load(&goroutineLimit.nwait) >= max {
+			break
+		}
+		gp := goroutineLimit.waitq.pop()
+		atomic.Xadd(&goroutineLimit.nwait, -1)
+		gp.param = unsafe.Pointer(gp)
+		admitted.push(gp)
+	}
+	return admitted
+}
@@ -515,7 +515,7 @@ func dumpparams() {
 	dumpint(uint64(arenaEnd))
 	dumpstr(sys.GOARCH)
 	dumpstr(sys.Goexperiment)
-	dumpint(uint64(ncpu))
+	dumpint(uint64(getNumCPU()))
 }
 
 func itab_callback(tab *itab) {
@@ -7,9 +7,124 @@ const MaxUintptr = ^uintptr(0)
 // MulUintptr returns a * b and whether the multiplication overflowed.
 // On supported platforms this is an intrinsic lowered by the compiler.
 func MulUintptr(a, b uintptr) (uintptr, bool) {
+	if sys.PtrSize == 4 {
+		// A 64-bit product is cheaper than the division below
+		// on 32-bit platforms without the intrinsic.
+		p := uint64(a) * uint64(b)
+		return uintptr(p), p>>32 != 0
+	}
 	if a|b < 1<<(4*sys.PtrSize) || a == 0 {
 		return a * b, false
 	}
 	overflow := b > MaxUintptr/a
 	return a * b, overflow
 }
+
+// Mul64 returns the 128-bit product of x and y: (hi, lo) = x * y
+// with the product bits' upper half returned in hi and the lower
+// half returned in lo. It is a copy of math/bits.Mul64 for use in the
+// runtime. It does not use the stack, so it may be called from nosplit
+// functions, and on supported platforms it is an intrinsic.
+//go:nosplit
+func Mul64(x, y uint64) (hi, lo uint64) {
+	const mask32 = 1<<32 - 1
+	x0 := x & mask32
+	x1 := x >> 32
+	y0 := y & mask32
+	y1 := y >> 32
+	w0 := x0 * y0
+	t := x1*y0 + w0>>32
+	w1 := t & mask32
+	w2 := t >> 32
+	w1 += x0 * y1
+	hi = x1*y1 + w2 + w1>>32
+	lo = x * y
+	return
+}
+
+// IsPow2 reports whether x is a power of 2.
+func IsPow2(x uintptr) bool {
+	return x != 0 && x&(x-1) == 0
+}
+
+// AlignUp returns x rounded up to a multiple of align and whether the
+// result overflowed. align must be a power of 2.
+func AlignUp(x, align uintptr) (uintptr, bool) {
+	mask := align - 1
+	return (x + mask) &^ mask, x+mask < x
+}
+
+// RoundUpPow2 returns the smallest power of 2 greater than or equal to x
+// and whether the result overflowed. RoundUpPow2(0) is 1.
+func RoundUpPow2(x uintptr) (uintptr, bool) {
+	if x <= 1 {
+		return 1, false
+	}
+	n := sys.Len64(uint64(x - 1))
+	if n >= 8*sys.PtrSize {
+		return 0, true
+	}
+	return 1 << n, false
+}
+
+// DivRoundUp returns ceil(n / d) without overflow, unlike the common
+// (n + d - 1) / d. d must not be 0.
+func DivRoundUp(n, d uintptr) uintptr {
+	q := n / d
+	if n%d != 0 {
+		q++
+	}
+	return q
+}
+
+// DivRoundUpChecked is like DivRoundUp, but it reports false
+// instead of panicking if d is 0.
+func DivRoundUpChecked(n, d uintptr) (uintptr, bool) {
+	if d == 0 {
+		return 0, false
+	}
+	return DivRoundUp(n, d), true
+}
+
+// TrailingZeros returns the number of trailing zero bits in x;
+// the result is 8*sys.PtrSize for x == 0.
+func TrailingZeros(x uintptr) int {
+	if sys.PtrSize == 8 {
+		return sys.Ctz64(uint64(x))
+	}
+	return sys.Ctz32(uint32(x))
+}
+
+// LeadingZeros returns the number of leading zero bits in x;
+// the result is 8*sys.PtrSize for x == 0.
+func LeadingZeros(x uintptr) int {
+	return 8*sys.PtrSize - bitLen(x)
+}
+
+// Log2 returns the base 2 logarithm of x rounded down;
+// the result is -1 for x == 0.
+func Log2(x uintptr) int {
+	return bitLen(x) - 1
+}
+
+// bitLen returns the minimum number of bits required to represent x;
+// the result is 0 for x == 0.
+func bitLen(x uintptr) int {
+	if sys.PtrSize == 8 {
+		return sys.Len64(uint64(x))
+	}
+	return sys.Len32(uint32(x))
+}
+
+// AddUintptr returns a + b and whether the addition overflowed.
+// It is inlined to an add and a compare.
+func AddUintptr(a, b uintptr) (uintptr, bool) {
+	sum := a + b
+	return sum, sum < a
+}
+
+// SubUintptr returns a - b and whether the subtraction underflowed.
+// It is inlined to a subtract and a compare.
+func SubUintptr(a, b uintptr) (uintptr, bool) {
+	return a - b, b > a
+}
@@ -50,6 +50,85 @@ func TestMulUintptr(t *testing.T) {
 	}
 }
 
+var mul64Tests = []struct {
+	x, y   uint64
+	hi, lo uint64
+}{
+	{0, 0, 0, 0},
+	{1, 1, 0, 1},
+	{1 << 32, 1 << 32, 1, 0},
+	{1<<64 - 1, 2, 1, 1<<64 - 2},
+	{1<<64 - 1, 1<<64 - 1, 1<<64 - 2, 1},
+	{0x123456789abcdef0, 0xfedcba9876543210, 0x121fa00ad77d7422, 0x236d88fe5618cf00},
+}
+
+func TestMul64(t *testing.T) {
+	for _, test := range mul64Tests {
+		x, y := test.x, test.y
+		for i := 0; i < 2; i++ {
+			hi, lo := Mul64(x, y)
+			if hi != test.hi || lo != test.lo {
+				t.Errorf("Mul64(%#x, %#x) = %#x, %#x want %#x, %#x",
+					x, y, hi, lo, test.hi, test.lo)
+			}
+			x, y = y, x
+		}
+	}
+}
+
+type addUintptrTest struct {
+	a        uintptr
+	b        uintptr
+	overflow bool
+}
+
+var addUintptrTests = []addUintptrTest{
+	{0, 0, false},
+	{1000, 1000, false},
+	{MaxUintptr, 0, false},
+	{MaxUintptr, 1, true},
+	{MaxUintptr / 2, MaxUintptr / 2, false},
+	{MaxUintptr / 2, MaxUintptr/2 + 1, false},
+	{MaxUintptr / 2, MaxUintptr/2 + 2, true},
+	{MaxUintptr, MaxUintptr, true},
+}
+
+func TestAddUintptr(t *testing.T) {
+	for _, test := range addUintptrTests {
+		a, b := test.a, test.b
+		for i := 0; i < 2; i++ {
+			sum, overflow := AddUintptr(a, b)
+			if sum != a+b || overflow != test.overflow {
+				t.Errorf("AddUintptr(%v, %v) = %v, %v want %v, %v",
+					a, b, sum, overflow, a+b, test.overflow)
+			}
+			a, b = b, a
+		}
+	}
+}
+
+var subUintptrTests = []addUintptrTest{
+	{0, 0, false},
+	{1000, 1000, false},
+	{1000, 1001, true},
+	{0, 1, true},
+	{MaxUintptr, MaxUintptr, false},
+	{MaxUintptr - 1, MaxUintptr, true},
+	{MaxUintptr, 0, false},
+	{0, MaxUintptr, true},
+}
+
+func TestSubUintptr(t *testing.T) {
+	for _, test := range subUintptrTests {
+		a, b := test.a, test.b
+		diff, overflow := SubUintptr(a, b)
+		if diff != a-b || overflow != test.overflow {
+			t.Errorf("SubUintptr(%v, %v) = %v, %v want %v, %v",
+				a, b, diff, overflow, a-b, test.overflow)
+		}
+	}
+}
+
 var SinkUintptr uintptr
 var SinkBool bool
 
@@ -66,6 +145,16 @@ func BenchmarkMulUintptr(b *testing.B) {
 			}
 		}
 	})
+	x, y = 1<<(UintptrSize/2), 1000
+	b.Run("medium", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var overflow bool
+			SinkUintptr, overflow = MulUintptr(x, y)
+			if overflow {
+				SinkUintptr = 0
+			}
+		}
+	})
 	x, y = MaxUintptr, MaxUintptr-1
 	b.Run("large", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
@@ -77,3 +166,135 @@ func BenchmarkMulUintptr(b *testing.B) {
 		}
 	})
 }
+
+func TestIsPow2(t *testing.T) {
+	for _, x := range []uintptr{1, 2, 4, 1 << 20, 1 << (UintptrSize - 1)} {
+		if !IsPow2(x) {
+			t.Errorf("IsPow2(%#x) = false, want true", x)
+		}
+	}
+	for _, x := range []uintptr{0, 3, 6, 1<<20 + 1, MaxUintptr} {
+		if IsPow2(x) {
+			t.Errorf("IsPow2(%#x) = true, want false", x)
+		}
+	}
+}
+
+var alignUpTests = []struct {
+	x, align uintptr
+	want     uintptr
+	overflow bool
+}{
+	{0, 8, 0, false},
+	{1, 8, 8, false},
+	{8, 8, 8, false},
+	{9, 8, 16, false},
+	{4095, 4096, 4096, false},
+	{5, 1, 5, false},
+	{MaxUintptr &^ 7, 8, MaxUintptr &^ 7, false},
+	{MaxUintptr&^7 + 1, 8, 0, true},
+	{MaxUintptr, 2, 0, true},
+}
+
+func TestAlignUp(t *testing.T) {
+	for _, test := range alignUpTests {
+		got, overflow := AlignUp(test.x, test.align)
+		if got != test.want || overflow != test.overflow {
+			t.Errorf("AlignUp(%#x, %#x) = %#x, %v want %#x, %v",
+				test.x, test.align, got, overflow, test.want, test.overflow)
+		}
+	}
+}
+
+var roundUpPow2Tests = []struct {
+	x        uintptr
+	want     uintptr
+	overflow bool
+}{
+	{0, 1, false},
+	{1, 1, false},
+	{2, 2, false},
+	{3, 4, false},
+	{1000, 1024, false},
+	{1 << 20, 1 << 20, false},
+	{1<<20 + 1, 1 << 21, false},
+	{1 << (UintptrSize - 1), 1 << (UintptrSize - 1), false},
+	{1<<(UintptrSize-1) + 1, 0, true},
+	{MaxUintptr, 0, true},
+}
+
+func TestRoundUpPow2(t *testing.T) {
+	for _, test := range roundUpPow2Tests {
+		got, overflow := RoundUpPow2(test.x)
+		if got != test.want || overflow != test.overflow {
+			t.Errorf("RoundUpPow2(%#x) = %#x, %v want %#x, %v",
+				test.x, got, overflow, test.want, test.overflow)
+		}
+	}
+}
+
+var divRoundUpTests = []struct {
+	n, d uintptr
+	want uintptr
+}{
+	{0, 1, 0},
+	{0, 8, 0},
+	{1, 8, 1},
+	{8, 8, 1},
+	{9, 8, 2},
+	{1000, 3, 334},
+	{MaxUintptr, 1, MaxUintptr},
+	{MaxUintptr, 2, MaxUintptr/2 + 1},
+	{MaxUintptr, 3, MaxUintptr / 3},
+	{MaxUintptr, 8, MaxUintptr/8 + 1},
+	{MaxUintptr, MaxUintptr, 1},
+	{MaxUintptr - 1, MaxUintptr, 1},
+}
+
+func TestDivRoundUp(t *testing.T) {
+	for _, test := range divRoundUpTests {
+		if got := DivRoundUp(test.n, test.d); got != test.want {
+			t.Errorf("DivRoundUp(%#x, %#x) = %#x, want %#x", test.n, test.d, got, test.want)
+		}
+		got, ok := DivRoundUpChecked(test.n, test.d)
+		if got != test.want || !ok {
+			t.Errorf("DivRoundUpChecked(%#x, %#x) = %#x, %v want %#x, true", test.n, test.d, got, ok, test.want)
+		}
+	}
+	if got, ok := DivRoundUpChecked(1, 0); got != 0 || ok {
+		t.Errorf("DivRoundUpChecked(1, 0) = %#x, %v want 0, false", got, ok)
+	}
+}
+
+func TestZeros(t *testing.T) {
+	if got := TrailingZeros(0); got != UintptrSize {
+		t.Errorf("TrailingZeros(0) = %d, want %d", got, UintptrSize)
+	}
+	if got := LeadingZeros(0); got != UintptrSize {
+		t.Errorf("LeadingZeros(0) = %d, want %d", got, UintptrSize)
+	}
+	if got := Log2(0); got != -1 {
+		t.Errorf("Log2(0) = %d, want -1", got)
+	}
+	for i := 0; i < UintptrSize; i++ {
+		x := uintptr(1) << i
+		if got := TrailingZeros(x); got != i {
+			t.Errorf("TrailingZeros(%#x) = %d, want %d", x, got, i)
+		}
+		if got := TrailingZeros(MaxUintptr << i); got != i {
+			t.Errorf("TrailingZeros(%#x) = %d, want %d", MaxUintptr<<i, got, i)
+		}
+		if got := LeadingZeros(x); got != UintptrSize-1-i {
+			t.Errorf("LeadingZeros(%#x) = %d, want %d", x, got, UintptrSize-1-i)
+		}
+		if got := LeadingZeros(MaxUintptr >> i); got != i {
+			t.Errorf("LeadingZeros(%#x) = %d, want %d", MaxUintptr>>i, got, i)
+		}
+		if got := Log2(x); got != i {
+			t.Errorf("Log2(%#x) = %d, want %d", x, got, i)
+		}
+		if got := Log2(x<<1 - 1); got != i {
+			t.Errorf("Log2(%#x) = %d, want %d", x<<1-1, got, i)
+		}
+	}
+}
@@ -44,6 +44,19 @@ var ntz8tab = [256]uint8{
 	0x04, 0x00, 0x01, 0x00, 0x02, 0x00, 0x01, 0x00, 0x03, 0x00, 0x01, 0x00, 0x02, 0x00, 0x01, 0x00,
 }
 
+// Len32 returns the minimum number of bits required to represent x; the result is 0 for x == 0.
+func Len32(x uint32) (n int) {
+	if x >= 1<<16 {
+		x >>= 16
+		n = 16
+	}
+	if x >= 1<<8 {
+		x >>= 8
+		n += 8
+	}
+	return n + int(len8tab[x])
+}
+
 // len64 returns the minimum number of bits required to represent x; the result is 0 for x == 0.
 func Len64(x uint64) (n int) {
 	if x >= 1<<32 {
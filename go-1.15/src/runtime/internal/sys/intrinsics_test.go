@@ -36,3 +36,18 @@ func TestBswap32(t *testing.T) {
 		t.Errorf("Bswap(%x)=%x, want 0x44332211", x, y)
 	}
 }
+
+func TestLen32(t *testing.T) {
+	if got := sys.Len32(0); got != 0 {
+		t.Errorf("Len32(0)=%d, want 0", got)
+	}
+	for i := 0; i < 32; i++ {
+		x := uint32(1) << uint(i)
+		if got := sys.Len32(x); got != i+1 {
+			t.Errorf("Len32(%d)=%d, want %d", x, got, i+1)
+		}
+		if got := sys.Len32(x | x>>1); got != i+1 {
+			t.Errorf("Len32(%d)=%d, want %d", x|x>>1, got, i+1)
+		}
+	}
+}
@@ -417,7 +417,7 @@ func mallocinit() {
 
 	testdefersizes()
 
-	if heapArenaBitmapBytes&(heapArenaBitmapBytes-1) != 0 {
+	if !math.IsPow2(heapArenaBitmapBytes) {
 		// heapBits expects modular arithmetic on bitmap
 		// addresses to work.
 		throw("heapArenaBitmapBytes not a power of 2")
@@ -441,7 +441,7 @@ func mallocinit() {
 		print("system page size (", physPageSize, ") is smaller than minimum page size (", minPhysPageSize, ")\n")
 		throw("bad system page size")
 	}
-	if physPageSize&(physPageSize-1) != 0 {
+	if !math.IsPow2(physPageSize) {
 		print("system page size (", physPageSize, ") must be a power of 2\n")
 		throw("bad system page size")
 	}
@@ -639,7 +639,7 @@ func (h *mheap) sysAlloc(n uintptr) (v unsafe.Pointer, size uintptr) {
 		if hint.down {
 			p -= n
 		}
-		if p+n < p {
+		if _, overflow := math.AddUintptr(p, n); overflow {
 			// We can't use this, so don't ask.
 			v = nil
 		} else if arenaIndex(p+n-1) >= 1<<arenaBits {
@@ -700,7 +700,7 @@ func (h *mheap) sysAlloc(n uintptr) (v unsafe.Pointer, size uintptr) {
 	{
 		var bad string
 		p := uintptr(v)
-		if p+size < p {
+		if _, overflow := math.AddUintptr(p, size); overflow {
 			bad = "region exceeds uintptr range"
 		} else if arenaIndex(p) >= 1<<arenaBits {
 			bad = "base outside usable address space"
@@ -1156,7 +1156,7 @@ func mallocgc(size uintptr, typ *_type, needzero bool) unsafe.Pointer {
 func largeAlloc(size uintptr, needzero bool, noscan bool) *mspan {
 	// print("largeAlloc size=", size, "\n")
 
-	if size+_PageSize < size {
+	if _, overflow := math.AddUintptr(size, _PageSize); overflow {
 		throw("out of memory")
 	}
 	npages := size >> _PageShift
@@ -1340,7 +1340,7 @@ func persistentalloc1(size, align uintptr, sysStat *uint64) *notInHeap {
 		throw("persistentalloc: size == 0")
 	}
 	if align != 0 {
-		if align&(align-1) != 0 {
+		if !math.IsPow2(align) {
 			throw("persistentalloc: align is not a power of 2")
 		}
 		if align > _PageSize {
@@ -1421,7 +1421,7 @@ type linearAlloc struct {
 }
 
 func (l *linearAlloc) init(base, size uintptr) {
-	if base+size < base {
+	if _, overflow := math.AddUintptr(base, size); overflow {
 		// Chop off the last byte. The runtime isn't prepared
 		// to deal with situations where the bounds could overflow.
 		// Leave that memory reserved, though, so we don't map it
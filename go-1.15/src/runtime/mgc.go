@@ -1298,10 +1298,10 @@ func gcStart(trigger gcTrigger) {
 	systemstack(gcResetMarkState)
 
 	work.stwprocs, work.maxprocs = gomaxprocs, gomaxprocs
-	if work.stwprocs > ncpu {
+	if n := getNumCPU(); work.stwprocs > n {
 		// This is used to compute CPU time of the STW phases,
 		// so it can't be more than ncpu, even if GOMAXPROCS is.
-		work.stwprocs = ncpu
+		work.stwprocs = n
 	}
 	work.heap0 = atomic.Load64(&memstats.heap_live)
 	work.pauseNS = 0
@@ -716,6 +716,33 @@ func runtime_goroutineProfileWithLabels(p []StackRecord, labels []unsafe.Pointer
 	return goroutineProfileWithLabels(p, labels)
 }
 
+// runtime_goroutineLabels is goroutineLabels for runtime/pprof.
+//go:linkname runtime_goroutineLabels runtime/pprof.runtime_goroutineLabels
+func runtime_goroutineLabels(labels []unsafe.Pointer) (n int) {
+	return goroutineLabels(labels)
+}
+
+// goroutineLabels stores the profiler labels of the live user goroutines
+// in labels, for a census of goroutines by label, and returns their
+// number. If labels is too short, only the first len(labels) are stored.
+// Unlike goroutineProfileWithLabels, it does not stop the world or collect
+// stacks, so goroutines starting or exiting meanwhile may or may not be
+// counted.
+func goroutineLabels(labels []unsafe.Pointer) (n int) {
+	lock(&allglock)
+	for _, gp1 := range allgs {
+		if readgstatus(gp1) == _Gdead || isSystemGoroutine(gp1, false) {
+			continue
+		}
+		if n < len(labels) {
+			labels[n] = gp1.labels
+		}
+		n++
+	}
+	unlock(&allglock)
+	return n
+}
+
 // labels may be nil. If labels is non-nil, it must have the same length as p.
 func goroutineProfileWithLabels(p []StackRecord, labels []unsafe.Pointer) (n int, ok bool) {
 	if labels != nil && len(labels) != len(p) {
@@ -0,0 +1,145 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"runtime/internal/atomic"
+	"unsafe"
+)
+
+// numCPUWatchPeriod is how often the CPU count is queried once
+// NotifyNumCPU has been called.
+const numCPUWatchPeriod = 1e9 // 1s
+
+var numCPUNotify struct {
+	lock     mutex
+	chans    []chan<- int // replaced, never modified, once registered
+	watching bool
+	gen      uint32 // incremented when the watcher is stopped
+}
+
+// numCPUSendSema serializes the sends of CPU count changes. The sends
+// are not made under numCPUNotify.lock, as a send on a closed channel
+// panics, which must not happen while holding a runtime lock.
+var numCPUSendSema uint32 = 1
+
+// getNumCPU returns ncpu. Once the program is running, UpdateNumCPU
+// may change ncpu concurrently, so ncpu must be read with getNumCPU.
+//
+//go:nosplit
+func getNumCPU() int32 {
+	return int32(atomic.Load((*uint32)(unsafe.Pointer(&ncpu))))
+}
+
+// UpdateNumCPU queries the operating system for the number of logical
+// CPUs usable by the current process, as at process startup, and updates
+// the value returned by NumCPU. If the count changed, for example because
+// the CPU affinity mask of the process was changed or CPUs were brought
+// online or offline, the new count is sent on the channels registered
+// with NotifyNumCPU. UpdateNumCPU returns the new count.
+//
+// GOMAXPROCS is not changed. Only Linux supports updating the count;
+// on other systems UpdateNumCPU returns the count queried at startup.
+func UpdateNumCPU() int {
+	var n int32
+	systemstack(func() {
+		n = osNumCPU()
+	})
+	old := int32(atomic.Xchg((*uint32)(unsafe.Pointer(&ncpu)), uint32(n)))
+	if n != old {
+		sendNumCPU()
+	}
+	return int(n)
+}
+
+// sendNumCPU sends the CPU count on the channels registered with
+// NotifyNumCPU.
+func sendNumCPU() {
+	semacquire(&numCPUSendSema)
+	defer semrelease(&numCPUSendSema)
+	lock(&numCPUNotify.lock)
+	chans := numCPUNotify.chans
+	unlock(&numCPUNotify.lock)
+	// Send the latest count, in case of concurrent updates.
+	n := int(getNumCPU())
+	for _, c := range chans {
+		select {
+		case c <- n:
+		default:
+		}
+	}
+}
+
+// NotifyNumCPU causes the new number of usable CPUs to be sent on c
+// whenever it changes, so that pools and other structures sized by
+// NumCPU can be resized. The runtime then checks the count about once
+// a second, as UpdateNumCPU does.
+//
+// The runtime does not block sending to c: the caller must ensure that
+// c has sufficient buffer space to keep up. A buffer of 1 suffices to
+// keep the latest change, if the receiver calls NumCPU after each
+// receive. Registering the same channel more than once has no effect.
+// c must not be closed before StopNumCPU(c) returns, as sending on it
+// would then panic.
+func NotifyNumCPU(c chan<- int) {
+	if c == nil {
+		panic("runtime: NotifyNumCPU using nil channel")
+	}
+	lock(&numCPUNotify.lock)
+	for _, x := range numCPUNotify.chans {
+		if x == c {
+			unlock(&numCPUNotify.lock)
+			return
+		}
+	}
+	numCPUNotify.chans = append(numCPUNotify.chans, c)
+	start := !numCPUNotify.watching
+	numCPUNotify.watching = true
+	gen := numCPUNotify.gen
+	unlock(&numCPUNotify.lock)
+	if start {
+		go numCPUWatcher(gen)
+	}
+}
+
+// StopNumCPU causes the runtime to stop sending CPU count changes on c.
+// When StopNumCPU returns, no more values will be sent on c. Once no
+// channels are registered, the runtime stops checking the count.
+func StopNumCPU(c chan<- int) {
+	lock(&numCPUNotify.lock)
+	chans := numCPUNotify.chans
+	for i, x := range chans {
+		if x == c {
+			// A concurrent sendNumCPU may be reading chans.
+			rest := make([]chan<- int, 0, len(chans)-1)
+			rest = append(rest, chans[:i]...)
+			numCPUNotify.chans = append(rest, chans[i+1:]...)
+			break
+		}
+	}
+	if len(numCPUNotify.chans) == 0 && numCPUNotify.watching {
+		numCPUNotify.watching = false
+		numCPUNotify.gen++
+	}
+	unlock(&numCPUNotify.lock)
+	// Wait for a send in progress, which may still include c.
+	semacquire(&numCPUSendSema)
+	semrelease(&numCPUSendSema)
+}
+
+// numCPUWatcher polls the CPU count for NotifyNumCPU until StopNumCPU
+// stops the watcher of generation gen.
+func numCPUWatcher(gen uint32) {
+	for {
+		timeSleep(numCPUWatchPeriod)
+		lock(&numCPUNotify.lock)
+		stopped := numCPUNotify.gen != gen
+		unlock(&numCPUNotify.lock)
+		if stopped {
+			return
+		}
+		UpdateNumCPU()
+	}
+}
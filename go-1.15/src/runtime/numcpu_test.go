@@ -0,0 +1,45 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime_test
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestUpdateNumCPU(t *testing.T) {
+	n := runtime.NumCPU()
+	if got := runtime.UpdateNumCPU(); got != n {
+		// Only expected if the CPUs of the process changed.
+		t.Logf("UpdateNumCPU = %d, NumCPU at start = %d", got, n)
+	}
+	if got, want := runtime.NumCPU(), runtime.UpdateNumCPU(); got != want {
+		t.Errorf("NumCPU = %d after UpdateNumCPU returned %d", got, want)
+	}
+}
+
+func TestNotifyNumCPU(t *testing.T) {
+	c := make(chan int, 1)
+	runtime.NotifyNumCPU(c)
+	runtime.NotifyNumCPU(c)
+	runtime.UpdateNumCPU()
+	runtime.StopNumCPU(c)
+	runtime.StopNumCPU(c)
+	// Without a change in the CPUs of the process, nothing is sent.
+	select {
+	case n := <-c:
+		if n != runtime.NumCPU() {
+			t.Errorf("received %d, NumCPU = %d", n, runtime.NumCPU())
+		}
+	default:
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NotifyNumCPU(nil) did not panic")
+		}
+	}()
+	runtime.NotifyNumCPU(nil)
+}
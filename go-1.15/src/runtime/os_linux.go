@@ -96,6 +96,11 @@ func getproccount() int32 {
 	return n
 }
 
+// osNumCPU returns the current number of CPUs for UpdateNumCPU.
+func osNumCPU() int32 {
+	return getproccount()
+}
+
 // Clone, the Linux rfork.
 const (
 	_CLONE_VM             = 0x100
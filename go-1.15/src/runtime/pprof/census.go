@@ -0,0 +1,109 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pprof
+
+import (
+	"sort"
+	"unsafe"
+)
+
+// A GoroutineCount is the number of live goroutines having a set of
+// profiler labels, as reported by GoroutineCensus.
+type GoroutineCount struct {
+	Labels map[string]string // nil for goroutines without labels
+	Count  int
+}
+
+// runtime_goroutineLabels is defined in runtime/mprof.go
+func runtime_goroutineLabels(labels []unsafe.Pointer) (n int)
+
+// goroutineLabels returns the label maps of the live goroutines.
+func goroutineLabels() []unsafe.Pointer {
+	// Leave room for goroutines started between the calls.
+	n := runtime_goroutineLabels(nil)
+	for {
+		labels := make([]unsafe.Pointer, n+10)
+		n = runtime_goroutineLabels(labels)
+		if n <= len(labels) {
+			return labels[:n]
+		}
+	}
+}
+
+// GoroutineCensus returns the number of live goroutines for each set of
+// profiler labels, as set by Do or SetGoroutineLabels, in decreasing order
+// of count. Goroutines without labels are counted together, with nil
+// Labels.
+//
+// Unlike the goroutine profile, GoroutineCensus neither stops the world
+// nor collects stacks, so it is cheap enough to be called regularly, for
+// example to export the number of goroutines per request handler as a
+// metric. The counts are not a consistent snapshot: goroutines starting
+// or exiting during the call may or may not be counted.
+//
+// The Labels maps are shared with the goroutines and must not be modified.
+func GoroutineCensus() []GoroutineCount {
+	// Goroutines usually share the label map of the goroutine that
+	// started them, so count by map first.
+	byMap := make(map[unsafe.Pointer]int)
+	for _, l := range goroutineLabels() {
+		byMap[l]++
+	}
+	index := make(map[string]int) // label string -> index in counts
+	var counts []GoroutineCount
+	var keys []string
+	for l, n := range byMap {
+		var m map[string]string
+		key := ""
+		if lm := (*labelMap)(l); lm != nil && len(*lm) > 0 {
+			m = *lm
+			key = lm.String()
+		}
+		if i, ok := index[key]; ok {
+			counts[i].Count += n
+			continue
+		}
+		index[key] = len(counts)
+		counts = append(counts, GoroutineCount{Labels: m, Count: n})
+		keys = append(keys, key)
+	}
+	sort.Sort(&censusSorter{counts, keys})
+	return counts
+}
+
+// GoroutinesByLabel returns the number of live goroutines for each value
+// of the profiler label key. Goroutines without the label are counted
+// under the empty value. Like GoroutineCensus, it is cheap enough to be
+// called regularly.
+func GoroutinesByLabel(key string) map[string]int {
+	counts := make(map[string]int)
+	for _, l := range goroutineLabels() {
+		var v string
+		if lm := (*labelMap)(l); lm != nil {
+			v = (*lm)[key]
+		}
+		counts[v]++
+	}
+	return counts
+}
+
+type censusSorter struct {
+	counts []GoroutineCount
+	keys   []string
+}
+
+func (s *censusSorter) Len() int { return len(s.counts) }
+
+func (s *censusSorter) Less(i, j int) bool {
+	if s.counts[i].Count != s.counts[j].Count {
+		return s.counts[i].Count > s.counts[j].Count
+	}
+	return s.keys[i] < s.keys[j]
+}
+
+func (s *censusSorter) Swap(i, j int) {
+	s.counts[i], s.counts[j] = s.counts[j], s.counts[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
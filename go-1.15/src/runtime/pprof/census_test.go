@@ -0,0 +1,68 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pprof
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestGoroutineCensus(t *testing.T) {
+	base := GoroutinesByLabel("handler")
+
+	var started, done sync.WaitGroup
+	stop := make(chan struct{})
+	spawn := func(handler string, n int) {
+		Do(context.Background(), Labels("handler", handler, "test", "census"), func(ctx context.Context) {
+			for i := 0; i < n; i++ {
+				started.Add(1)
+				done.Add(1)
+				go func() {
+					started.Done()
+					<-stop
+					done.Done()
+				}()
+			}
+		})
+	}
+	spawn("census-a", 5)
+	spawn("census-b", 3)
+	started.Wait()
+
+	byHandler := GoroutinesByLabel("handler")
+	if got := byHandler["census-a"] - base["census-a"]; got != 5 {
+		t.Errorf("GoroutinesByLabel: %d goroutines for census-a, want 5", got)
+	}
+	if got := byHandler["census-b"] - base["census-b"]; got != 3 {
+		t.Errorf("GoroutinesByLabel: %d goroutines for census-b, want 3", got)
+	}
+	if byHandler[""] < 1 {
+		t.Errorf("GoroutinesByLabel: no unlabeled goroutines counted")
+	}
+
+	found := 0
+	census := GoroutineCensus()
+	for i, c := range census {
+		if i > 0 && c.Count > census[i-1].Count {
+			t.Errorf("census not sorted by count: %v", census)
+		}
+		if c.Labels["test"] != "census" {
+			continue
+		}
+		found++
+		switch h := c.Labels["handler"]; {
+		case h == "census-a" && c.Count == 5, h == "census-b" && c.Count == 3:
+		default:
+			t.Errorf("census entry %v with count %d", c.Labels, c.Count)
+		}
+	}
+	if found != 2 {
+		t.Errorf("found %d census entries for the test goroutines, want 2: %v", found, census)
+	}
+
+	close(stop)
+	done.Wait()
+}
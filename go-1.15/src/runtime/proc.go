@@ -575,6 +575,11 @@ func schedinit() {
 
 	sched.lastpoll = uint64(nanotime())
 	procs := ncpu
+	if debug.cgroupmaxprocs > 0 {
+		if n := cgroupMaxProcs(); n > 0 && n < procs {
+			procs = n
+		}
+	}
 	if n, ok := atoi32(gogetenv("GOMAXPROCS")); ok && n > 0 {
 		procs = n
 	}
@@ -1004,6 +1009,7 @@ func stopTheWorldWithSema() {
 		p.status = _Pgcstop
 		sched.stopwait--
 	}
+	sched.stopwait -= cpuLimitStopTheWorld()
 	wait := sched.stopwait > 0
 	unlock(&sched.lock)
 
@@ -1330,6 +1336,13 @@ func forEachP(fn func(*p)) {
 			sched.safePointWait--
 		}
 	}
+	// Likewise for the Ps throttled by the CPU limit.
+	for p := cpuLimit.throttled.ptr(); p != nil; p = p.link.ptr() {
+		if atomic.Cas(&p.runSafePointFn, 1, 0) {
+			fn(p)
+			sched.safePointWait--
+		}
+	}
 
 	wait := sched.safePointWait > 0
 	unlock(&sched.lock)
@@ -2615,6 +2628,9 @@ top:
 	if pp.runSafePointFn != 0 {
 		runSafePointFn()
 	}
+	if cpuLimitThrottle(pp) {
+		goto top
+	}
 
 	// Sanity check: if we are spinning, the run queue should be empty.
 	// Check this before calling checkTimers, as that might call
@@ -2934,7 +2950,8 @@ func goexit0(gp *g) {
 	_g_ := getg()
 
 	casgstatus(gp, _Grunning, _Gdead)
-	if isSystemGoroutine(gp, false) {
+	system := isSystemGoroutine(gp, false)
+	if system {
 		atomic.Xadd(&sched.ngsys, -1)
 	}
 	gp.m = nil
@@ -2964,6 +2981,9 @@ func goexit0(gp *g) {
 
 	if GOARCH == "wasm" { // no threads yet on wasm
 		gfput(_g_.m.p.ptr(), gp)
+		if !system {
+			goroutineLimitDone()
+		}
 		schedule() // never returns
 	}
 
@@ -2972,6 +2992,9 @@ func goexit0(gp *g) {
 		throw("internal lockOSThread error")
 	}
 	gfput(_g_.m.p.ptr(), gp)
+	if !system {
+		goroutineLimitDone()
+	}
 	if locked {
 		// The goroutine may have locked this thread because
 		// it put it in an unusual kernel state. Kill it
@@ -3518,8 +3541,14 @@ func newproc(siz int32, fn *funcval) {
 	argp := add(unsafe.Pointer(&fn), sys.PtrSize)
 	gp := getg()
 	pc := getcallerpc()
+	var newg *g
+	hold := false
 	systemstack(func() {
-		newg := newproc1(fn, argp, siz, gp, pc)
+		newg = newproc1(fn, argp, siz, gp, pc)
+		if newg == nil || goroutineLimitHold(newg, gp) {
+			hold = true
+			return
+		}
 
 		_p_ := getg().m.p.ptr()
 		runqput(_p_, newg, true)
@@ -3528,12 +3557,20 @@ func newproc(siz int32, fn *funcval) {
 			wakep()
 		}
 	})
+	if hold {
+		// The arguments are on newg's stack, if any, so the stack
+		// of this frame may move now.
+		goroutineLimitWait(fn, newg)
+	}
 }
 
 // Create a new g in state _Grunnable, starting at fn, with narg bytes
 // of arguments starting at argp. callerpc is the address of the go
 // statement that created this. The caller is responsible for adding
 // the new g to the scheduler.
+// newproc1 returns nil, creating no g, if the limit on the number of
+// goroutines set by runtime/debug.SetMaxGoroutines is reached and
+// the go statement must panic.
 //
 // This must run on the system stack because it's the continuation of
 // newproc, which cannot split the stack.
@@ -3616,6 +3653,11 @@ func newproc1(fn *funcval, argp unsafe.Pointer, narg int32, callergp *g, callerp
 	}
 	if isSystemGoroutine(newg, false) {
 		atomic.Xadd(&sched.ngsys, +1)
+	} else if !goroutineLimitAdd(callergp) {
+		newg.labels = nil
+		gfput(_p_, newg)
+		releasem(_g_.m)
+		return nil
 	}
 	casgstatus(newg, _Gdead, _Grunnable)
 
@@ -4239,6 +4281,11 @@ func (pp *p) destroy() {
 		unlock(&pp.timersLock)
 		unlock(&plocal.timersLock)
 	}
+	// Keep the timer counts of destroyed Ps in ReadTimerStats totals.
+	atomic.Xadd64(&deadPTimerStats.started, int64(pp.timersStarted))
+	atomic.Xadd64(&deadPTimerStats.fired, int64(pp.timersFired))
+	atomic.Store64(&pp.timersStarted, 0)
+	atomic.Store64(&pp.timersFired, 0)
 	// If there's a background worker, make it runnable and put
 	// it on the global queue so it can clean itself up.
 	if gp := pp.gcBgMarkWorker.ptr(); gp != nil {
@@ -4337,6 +4384,7 @@ func procresize(nprocs int32) *p {
 		}
 		unlock(&allpLock)
 	}
+	pstatsResize(nprocs)
 
 	// initialize new P's
 	for i := old; i < nprocs; i++ {
@@ -4547,8 +4595,15 @@ func checkdead() {
 		case _Gwaiting,
 			_Gpreempted:
 			grunning++
-		case _Grunnable,
-			_Grunning,
+		case _Grunnable:
+			// The goroutine may wait for a P throttled by the
+			// CPU limit, which sysmon will release.
+			if cpuLimit.nthrottled > 0 {
+				unlock(&allglock)
+				return
+			}
+			fallthrough
+		case _Grunning,
 			_Gsyscall:
 			unlock(&allglock)
 			print("runtime: checkdead: find g ", gp.goid, " in status ", s, "\n")
@@ -4572,6 +4627,15 @@ func checkdead() {
 					break
 				}
 			}
+			// Time does not pass while nothing runs, so a P throttled
+			// by the CPU limit would never be released. Treat it as idle.
+			for pp := &cpuLimit.throttled; *pp != 0; pp = &(*pp).ptr().link {
+				if (*pp).ptr() == _p_ {
+					*pp = _p_.link
+					cpuLimit.nthrottled--
+					break
+				}
+			}
 			mp := mget()
 			if mp == nil {
 				// There should always be a free M since
@@ -4624,10 +4688,20 @@ func sysmon() {
 		if delay > 10*1000 { // up to 10ms
 			delay = 10 * 1000
 		}
+		if delay > 1000 && cpuLimited() && atomic.Load(&sched.npidle) != uint32(gomaxprocs) {
+			// Switch the number of Ps running Go code on time.
+			delay = 1000
+		}
 		usleep(delay)
 		now := nanotime()
 		next, _ := timeSleepUntil()
 		if debug.schedtrace <= 0 && (sched.gcwaiting != 0 || atomic.Load(&sched.npidle) == uint32(gomaxprocs)) {
+			// While the world is stopped, the stall watchdog is the
+			// only work left, to notice a stop that cannot complete.
+			watch := atomic.Load(&sched.gcwaiting) != 0 && stallWatchEnabled()
+			if watch {
+				stallCheck(now)
+			}
 			lock(&sched.lock)
 			if atomic.Load(&sched.gcwaiting) != 0 || atomic.Load(&sched.npidle) == uint32(gomaxprocs) {
 				if next > now {
@@ -4639,6 +4713,10 @@ func sysmon() {
 					if next-now < sleep {
 						sleep = next - now
 					}
+					if limit := atomic.Loadint64(&stallWatch.limit); watch && limit > 0 && limit < sleep {
+						// Check for a stall again in time.
+						sleep = limit
+					}
 					shouldRelax := sleep >= osRelaxMinNS
 					if shouldRelax {
 						osRelax(true)
@@ -4657,6 +4735,9 @@ func sysmon() {
 				delay = 20
 			}
 			unlock(&sched.lock)
+			if watch && atomic.Load(&sched.gcwaiting) != 0 {
+				continue
+			}
 		}
 		lock(&sched.sysmonlock)
 		{
@@ -4722,6 +4803,13 @@ func sysmon() {
 			lasttrace = now
 			schedtrace(debug.scheddetail > 0)
 		}
+		stallCheck(now)
+		if atomic.Load(&pstats.enabled) != 0 {
+			pstatsSample(now)
+		}
+		if cpuLimited() {
+			cpuLimitAdjust(now)
+		}
 		unlock(&sched.sysmonlock)
 	}
 }
@@ -5080,6 +5168,7 @@ func pidleget() *p {
 	if _p_ != nil {
 		sched.pidle = _p_.link
 		atomic.Xadd(&sched.npidle, -1) // TODO: fast atomic
+		cpuLimitWakeSysmon()
 	}
 	return _p_
 }
@@ -5518,7 +5607,7 @@ func sync_runtime_canSpin(i int) bool {
 	// GOMAXPROCS>1 and there is at least one other running P and local runq is empty.
 	// As opposed to runtime mutex we don't do passive spinning here,
 	// because there can be work on global runq or on other Ps.
-	if i >= active_spin || ncpu <= 1 || gomaxprocs <= int32(sched.npidle+sched.nmspinning)+1 {
+	if i >= active_spin || getNumCPU() <= 1 || gomaxprocs <= int32(sched.npidle+sched.nmspinning)+1 {
 		return false
 	}
 	if p := getg().m.p.ptr(); !runqempty(p) {
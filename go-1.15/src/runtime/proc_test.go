@@ -538,6 +538,18 @@ func stackGrowthRecursive(i int) {
 	}
 }
 
+func TestStallWatchdog(t *testing.T) {
+	output := runTestProg(t, "testprog", "StallWatchdog", "GODEBUG=asyncpreemptoff=1")
+	if output != "OK\n" {
+		t.Errorf("want OK, got %q", output)
+	}
+
+	output = runTestProg(t, "testprog", "StallWatchdogDump", "GODEBUG=asyncpreemptoff=1")
+	if !strings.Contains(output, "runtime: scheduler stalled") || !strings.HasSuffix(output, "OK\n") {
+		t.Errorf("want a scheduler dump and OK, got:\n%s", output)
+	}
+}
+
 func TestPreemptSplitBig(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping in -short mode")
@@ -1070,6 +1082,152 @@ loop:
 	}
 }
 
+func TestWithMaxProcs(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(procs)
+	runtime.GOMAXPROCS(4)
+
+	runtime.WithMaxProcs(2, func() {
+		if n := runtime.GOMAXPROCS(0); n != 2 {
+			t.Errorf("in WithMaxProcs(2), GOMAXPROCS = %d", n)
+		}
+		// A nested call with a higher limit does not raise it.
+		runtime.WithMaxProcs(3, func() {
+			if n := runtime.GOMAXPROCS(0); n != 2 {
+				t.Errorf("in WithMaxProcs(3) within WithMaxProcs(2), GOMAXPROCS = %d", n)
+			}
+		})
+		runtime.WithMaxProcs(1, func() {
+			if n := runtime.GOMAXPROCS(0); n != 1 {
+				t.Errorf("in WithMaxProcs(1) within WithMaxProcs(2), GOMAXPROCS = %d", n)
+			}
+		})
+		if n := runtime.GOMAXPROCS(0); n != 2 {
+			t.Errorf("after nested calls, GOMAXPROCS = %d, want 2", n)
+		}
+	})
+	if n := runtime.GOMAXPROCS(0); n != 4 {
+		t.Errorf("after WithMaxProcs, GOMAXPROCS = %d, want 4", n)
+	}
+
+	// The setting is restored when f panics.
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic not propagated")
+			}
+		}()
+		runtime.WithMaxProcs(1, func() { panic("phase failed") })
+	}()
+	if n := runtime.GOMAXPROCS(0); n != 4 {
+		t.Errorf("after panic in WithMaxProcs, GOMAXPROCS = %d, want 4", n)
+	}
+}
+
+func BenchmarkGOMAXPROCS0(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			runtime.GOMAXPROCS(0)
+		}
+	})
+}
+
+func TestSetCPULimit(t *testing.T) {
+	if runtime.GOARCH == "wasm" {
+		runtime.SetCPULimit(0.5)
+		if old := runtime.SetCPULimit(0); old != 0 {
+			t.Errorf("SetCPULimit(0) = %v, want 0 on wasm", old)
+		}
+		return
+	}
+	procs := runtime.GOMAXPROCS(0)
+	if procs < 2 {
+		t.Skip("skipping with GOMAXPROCS < 2")
+	}
+	if old := runtime.SetCPULimit(1); old != 0 {
+		t.Fatalf("SetCPULimit(1) = %v, want 0", old)
+	}
+	defer runtime.SetCPULimit(0)
+	if n := runtime.GOMAXPROCS(0); n != procs {
+		t.Errorf("with a limit of 1, GOMAXPROCS = %d, want %d", n, procs)
+	}
+
+	// Keep every P busy; with the limit, only one at a time runs.
+	busy := func(d time.Duration) time.Duration {
+		before := runtime.ReadPStats(nil)
+		start := time.Now()
+		done := make(chan bool)
+		for i := 0; i < procs; i++ {
+			go func() {
+				for time.Since(start) < d {
+				}
+				done <- true
+			}()
+		}
+		for i := 0; i < procs; i++ {
+			<-done
+		}
+		elapsed := time.Since(start)
+		var user int64
+		for i, s := range runtime.ReadPStats(nil) {
+			user += s.User - before[i].User
+		}
+		return time.Duration(user) - elapsed
+	}
+	if extra := busy(300 * time.Millisecond); extra > 150*time.Millisecond {
+		t.Errorf("with a limit of 1, Go code used %v more than one CPU", extra)
+	}
+
+	// Go code keeps running with a limit below 1 CPU.
+	if old := runtime.SetCPULimit(0.5); old != 1 {
+		t.Errorf("SetCPULimit(0.5) = %v, want 1", old)
+	}
+	busy(100 * time.Millisecond)
+
+	if old := runtime.SetCPULimit(0); old != 0.5 {
+		t.Errorf("SetCPULimit(0) = %v, want 0.5", old)
+	}
+	if n := runtime.GOMAXPROCS(0); n != procs {
+		t.Errorf("after removing the limit, GOMAXPROCS = %d, want %d", n, procs)
+	}
+}
+
+func TestCPULimitStopTheWorld(t *testing.T) {
+	if runtime.GOARCH == "wasm" {
+		t.Skip("no CPU limit on wasm")
+	}
+	// With a limit below 1, Ps are throttled most of the time; stopping
+	// the world and running safe point functions must not wait for them.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	defer runtime.SetCPULimit(runtime.SetCPULimit(0.5))
+	stop := make(chan bool)
+	done := make(chan bool)
+	for i := 0; i < 4; i++ {
+		go func() {
+			for {
+				select {
+				case <-stop:
+					done <- true
+					return
+				default:
+				}
+			}
+		}()
+	}
+	for start := time.Now(); time.Since(start) < 300*time.Millisecond; {
+		runtime.GC()
+		if n := runtime.ForEachP(); n != 4 {
+			t.Errorf("ForEachP ran on %d Ps, want 4", n)
+		}
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+	}
+	close(stop)
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+}
+
 // TestBigGOMAXPROCS tests that setting GOMAXPROCS to a large value
 // doesn't cause a crash at startup. See issue 38474.
 func TestBigGOMAXPROCS(t *testing.T) {
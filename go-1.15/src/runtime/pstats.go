@@ -0,0 +1,117 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import "runtime/internal/atomic"
+
+// PStats describes how a P, a processor running Go code, has spent its
+// time since the first call to ReadPStats, in nanoseconds.
+//
+// The times are estimated by sampling the state of each P from sysmon,
+// the runtime's monitoring thread, which wakes every 20µs to 10ms while
+// there is work. They are accurate over intervals much longer than that.
+type PStats struct {
+	// User is the time spent running goroutines, including the
+	// garbage collection work they are made to do as they allocate
+	// (GC assists) and time spent in the scheduler.
+	User int64
+
+	// GC is the time spent running the garbage collector's
+	// background workers, or stopped for a stop-the-world.
+	GC int64
+
+	// Syscall is the time spent by a goroutine in a system call or
+	// cgo call while holding the P.
+	Syscall int64
+
+	// Idle is the time the P had nothing to run.
+	Idle int64
+}
+
+var pstats struct {
+	enabled uint32 // sysmon samples the Ps; set by ReadPStats (atomic)
+	lock    mutex
+	last    int64    // time of the last sample
+	stats   []PStats // indexed by P id
+}
+
+// pstatsResize makes room for the statistics of nprocs Ps.
+// It is called by procresize, which is the only writer of pstats.stats
+// other than sysmon, so the length cannot change while allocating.
+func pstatsResize(nprocs int32) {
+	if int(nprocs) <= len(pstats.stats) {
+		return
+	}
+	stats := make([]PStats, nprocs)
+	lock(&pstats.lock)
+	copy(stats, pstats.stats)
+	pstats.stats = stats
+	unlock(&pstats.lock)
+}
+
+// pstatsSample is called by sysmon, once ReadPStats has been called, to
+// sample the state of each P. The time since the last sample is
+// attributed to the state each P is in now. sysmon sleeps for more than
+// 10ms only while every P is idle or the world is stopped, and those
+// states are then observed by the sample ending the sleep.
+func pstatsSample(now int64) {
+	lock(&allpLock)
+	lock(&pstats.lock)
+	dt := now - pstats.last
+	pstats.last = now
+	for i, pp := range allp {
+		if pp == nil || i >= len(pstats.stats) || dt <= 0 {
+			continue
+		}
+		s := &pstats.stats[i]
+		switch pp.status {
+		case _Pidle:
+			s.Idle += dt
+		case _Psyscall:
+			s.Syscall += dt
+		case _Pgcstop:
+			s.GC += dt
+		case _Prunning:
+			if mp := pp.m.ptr(); mp != nil && mp.curg != nil && mp.curg == pp.gcBgMarkWorker.ptr() {
+				s.GC += dt
+			} else {
+				s.User += dt
+			}
+		}
+	}
+	unlock(&pstats.lock)
+	unlock(&allpLock)
+}
+
+// ReadPStats appends the statistics of each P, indexed by P, to stats
+// and returns the extended slice. It reports GOMAXPROCS Ps. The runtime
+// samples the Ps only once ReadPStats has been called, so the first call
+// returns zero statistics.
+//
+// Sampling the statistics at intervals and comparing them shows the recent
+// utilization of each P, and so skew between Ps, such as a P kept busy by
+// a loop that cannot be preempted, without the cost of an execution trace.
+// A P that is removed by lowering GOMAXPROCS and added back later keeps
+// its earlier statistics.
+func ReadPStats(stats []PStats) []PStats {
+	// Allocate before taking the lock.
+	n := int(gomaxprocs)
+	if cap(stats)-len(stats) < n {
+		s := make([]PStats, len(stats), len(stats)+n)
+		copy(s, stats)
+		stats = s
+	}
+	lock(&pstats.lock)
+	if atomic.Load(&pstats.enabled) == 0 {
+		pstats.last = nanotime()
+		atomic.Store(&pstats.enabled, 1)
+	}
+	if n > len(pstats.stats) {
+		n = len(pstats.stats)
+	}
+	stats = append(stats, pstats.stats[:n]...)
+	unlock(&pstats.lock)
+	return stats
+}
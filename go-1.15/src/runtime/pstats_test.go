@@ -0,0 +1,41 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime_test
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReadPStats(t *testing.T) {
+	before := runtime.ReadPStats(nil)
+	if len(before) != runtime.GOMAXPROCS(0) {
+		t.Fatalf("ReadPStats returned %d Ps, want GOMAXPROCS = %d", len(before), runtime.GOMAXPROCS(0))
+	}
+
+	// Keep a P busy running user code.
+	start := time.Now()
+	for time.Since(start) < 200*time.Millisecond {
+	}
+	elapsed := time.Since(start)
+
+	after := runtime.ReadPStats(make([]runtime.PStats, 0, 1))
+	var user, total int64
+	for i := range after {
+		b, a := before[i], after[i]
+		if a.User < b.User || a.GC < b.GC || a.Syscall < b.Syscall || a.Idle < b.Idle {
+			t.Errorf("P %d: statistics decreased from %+v to %+v", i, b, a)
+		}
+		user += a.User - b.User
+		total += a.User - b.User + a.GC - b.GC + a.Syscall - b.Syscall + a.Idle - b.Idle
+	}
+	if user < int64(elapsed)/2 {
+		t.Errorf("user time %v while busy for %v", time.Duration(user), elapsed)
+	}
+	if max := int64(len(after)) * int64(elapsed+50*time.Millisecond); total > max {
+		t.Errorf("total time %v, more than %d Ps for %v", time.Duration(total), len(after), elapsed)
+	}
+}
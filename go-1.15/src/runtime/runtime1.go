@@ -280,6 +280,11 @@ func check() {
 
 	testAtomic64()
 
+	if offset := unsafe.Offsetof(m0.cgoStart); offset%8 != 0 {
+		println("runtime: m.cgoStart offset", offset)
+		throw("m.cgoStart not aligned to 8 bytes")
+	}
+
 	if _FixedStack != round2(_FixedStack) {
 		throw("FixedStack is not power-of-2")
 	}
@@ -317,6 +322,7 @@ var debug struct {
 	schedtrace         int32
 	tracebackancestors int32
 	asyncpreemptoff    int32
+	cgroupmaxprocs     int32
 }
 
 var dbgvars = []dbgVar{
@@ -338,6 +344,7 @@ var dbgvars = []dbgVar{
 	{"schedtrace", &debug.schedtrace},
 	{"tracebackancestors", &debug.tracebackancestors},
 	{"asyncpreemptoff", &debug.asyncpreemptoff},
+	{"cgroupmaxprocs", &debug.cgroupmaxprocs},
 }
 
 func parsedebugvars() {
@@ -474,6 +474,9 @@ type m struct {
 	divmod  uint32 // div/mod denominator for arm - known to liblink
 
 	// Fields not known to debuggers.
+	_             uint32       // align cgoStart and cgoTime for atomic access on 32-bit systems
+	cgoStart      int64        // nanotime at the start of the outermost cgo call in progress (atomic)
+	cgoTime       int64        // nanoseconds spent in completed cgo calls (atomic)
 	procid        uint64       // for debuggers, but offset not hard-coded
 	gsignal       *g           // signal-handling g
 	goSigStack    gsignalStack // Go-allocated signal handling stack
@@ -623,6 +626,12 @@ type p struct {
 	// This is 0 if the timer heap is empty.
 	timer0When uint64
 
+	// Number of timers started on this P and of timer functions
+	// run by it, reported by ReadTimerStats.
+	// Updated using atomic functions.
+	timersStarted uint64
+	timersFired   uint64
+
 	// Per-P GC state
 	gcAssistTime         int64    // Nanoseconds in assistAlloc
 	gcFractionalMarkTime int64    // Nanoseconds in fractional mark worker (atomic)
@@ -974,6 +983,7 @@ const (
 	waitReasonGCWorkerIdle                            // "GC worker (idle)"
 	waitReasonPreempted                               // "preempted"
 	waitReasonDebugCall                               // "debug call"
+	waitReasonGoroutineLimit                          // "goroutine limit"
 )
 
 var waitReasonStrings = [...]string{
@@ -1004,6 +1014,7 @@ var waitReasonStrings = [...]string{
 	waitReasonGCWorkerIdle:          "GC worker (idle)",
 	waitReasonPreempted:             "preempted",
 	waitReasonDebugCall:             "debug call",
+	waitReasonGoroutineLimit:        "goroutine limit",
 }
 
 func (w waitReason) String() string {
@@ -178,13 +178,8 @@ func growslice(et *_type, old slice, cap int) slice {
 		overflow = uintptr(newcap) > maxAlloc/sys.PtrSize
 		newcap = int(capmem / sys.PtrSize)
 	case isPowerOfTwo(et.size):
-		var shift uintptr
-		if sys.PtrSize == 8 {
-			// Mask shift for better code generation.
-			shift = uintptr(sys.Ctz64(uint64(et.size))) & 63
-		} else {
-			shift = uintptr(sys.Ctz32(uint32(et.size))) & 31
-		}
+		// Mask shift for better code generation.
+		shift := uintptr(math.TrailingZeros(et.size)) & (8*sys.PtrSize - 1)
 		lenmem = uintptr(old.len) << shift
 		newlenmem = uintptr(cap) << shift
 		capmem = roundupsize(uintptr(newcap) << shift)
@@ -0,0 +1,125 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runtime
+
+import (
+	"runtime/internal/atomic"
+	"unsafe"
+)
+
+// The stall watchdog detects that the scheduler is wedged: goroutines
+// are waiting to run, but none has been scheduled for a while, for
+// example because a P is stuck in C code or in a runaway GC assist, or
+// because a stop-the-world cannot complete. sysmon checks for progress,
+// as it does not need a P, and reports a stall once per occurrence.
+
+var stallWatch struct {
+	// The 64-bit atomic fields come first for alignment.
+	limit int64 // stall duration to report, or 0 if disabled (atomic)
+	stall int64 // duration of the last reported stall (atomic)
+
+	dump    uint32 // print the scheduler state on a stall (atomic)
+	pending uint32 // a stall has not been returned by stallWait (atomic)
+	stop    uint32 // stallWait should return without a stall (atomic)
+	waiting uint32 // stallWait is sleeping on note (atomic)
+	note    note
+
+	// Owned by sysmon.
+	ticks    uint64 // sum of the schedticks of all Ps at the last progress
+	progress int64  // time of the last progress
+	reported bool   // the current stall has been reported
+}
+
+//go:linkname setStallWatchdog runtime/debug.setStallWatchdog
+func setStallWatchdog(limit int64, dump bool) {
+	d := uint32(0)
+	if dump {
+		d = 1
+	}
+	atomic.Store(&stallWatch.dump, d)
+	if limit < 0 {
+		limit = 0
+	}
+	atomic.Store64((*uint64)(unsafe.Pointer(&stallWatch.limit)), uint64(limit))
+}
+
+// stallWait blocks until sysmon reports a stall and returns its
+// duration so far, or until stallWaitStop is called and returns -1.
+//go:linkname stallWait runtime/debug.stallWait
+func stallWait() int64 {
+	for {
+		if atomic.Xchg(&stallWatch.stop, 0) != 0 {
+			return -1
+		}
+		if atomic.Xchg(&stallWatch.pending, 0) != 0 {
+			return atomic.Loadint64(&stallWatch.stall)
+		}
+		noteclear(&stallWatch.note)
+		atomic.Store(&stallWatch.waiting, 1)
+		if (atomic.Load(&stallWatch.pending) != 0 || atomic.Load(&stallWatch.stop) != 0) &&
+			atomic.Cas(&stallWatch.waiting, 1, 0) {
+			// The stall or stop was reported before we were
+			// waiting, and no wakeup is coming.
+			continue
+		}
+		notetsleepg(&stallWatch.note, -1)
+	}
+}
+
+// stallWaitStop makes a blocked or future call to stallWait return -1,
+// so the goroutine calling it can exit.
+//go:linkname stallWaitStop runtime/debug.stallWaitStop
+func stallWaitStop() {
+	atomic.Store(&stallWatch.stop, 1)
+	if atomic.Cas(&stallWatch.waiting, 1, 0) {
+		notewakeup(&stallWatch.note)
+	}
+}
+
+func stallWatchEnabled() bool {
+	return atomic.Loadint64(&stallWatch.limit) != 0
+}
+
+// stallCheck is called by sysmon to check for a stall.
+func stallCheck(now int64) {
+	limit := atomic.Loadint64(&stallWatch.limit)
+	if limit == 0 {
+		stallWatch.reported = false
+		stallWatch.progress = now
+		return
+	}
+
+	ticks := uint64(0)
+	pending := sched.runqsize > 0
+	lock(&allpLock)
+	for _, pp := range allp {
+		ticks += uint64(pp.schedtick)
+		if !runqempty(pp) {
+			pending = true
+		}
+	}
+	unlock(&allpLock)
+	if ticks != stallWatch.ticks || !pending {
+		stallWatch.ticks = ticks
+		stallWatch.progress = now
+		stallWatch.reported = false
+		return
+	}
+	stall := now - stallWatch.progress
+	if stallWatch.reported || stall < limit {
+		return
+	}
+
+	stallWatch.reported = true
+	atomic.Store64((*uint64)(unsafe.Pointer(&stallWatch.stall)), uint64(stall))
+	atomic.Store(&stallWatch.pending, 1)
+	if atomic.Load(&stallWatch.dump) != 0 {
+		print("runtime: scheduler stalled: no goroutine scheduled for ", stall/1000000, "ms\n")
+		schedtrace(true)
+	}
+	if atomic.Cas(&stallWatch.waiting, 1, 0) {
+		notewakeup(&stallWatch.note)
+	}
+}
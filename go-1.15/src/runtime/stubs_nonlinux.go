@@ -10,3 +10,19 @@ package runtime
 func sbrk0() uintptr {
 	return 0
 }
+
+// cgroupCPUQuota returns the cgroup CPU quota of the process, which only
+// Linux has.
+func cgroupCPUQuota() (float64, bool) {
+	return 0, false
+}
+
+func cgroupMaxProcs() int32 {
+	return 0
+}
+
+// osNumCPU returns the current number of CPUs for UpdateNumCPU, which
+// is only supported on Linux.
+func osNumCPU() int32 {
+	return getNumCPU()
+}
@@ -19,6 +19,7 @@ func init() {
 	register("LockedDeadlock", LockedDeadlock)
 	register("LockedDeadlock2", LockedDeadlock2)
 	register("GoexitDeadlock", GoexitDeadlock)
+	register("CPULimitDeadlock", CPULimitDeadlock)
 	register("StackOverflow", StackOverflow)
 	register("ThreadExhaustion", ThreadExhaustion)
 	register("RecursivePanic", RecursivePanic)
@@ -50,6 +51,24 @@ func InitDeadlock() {
 	panic("not reached")
 }
 
+// CPULimitDeadlock deadlocks while Ps are throttled by the CPU limit.
+func CPULimitDeadlock() {
+	runtime.GOMAXPROCS(4)
+	runtime.SetCPULimit(0.5)
+	done := make(chan bool)
+	for i := 0; i < 4; i++ {
+		go func() {
+			for start := time.Now(); time.Since(start) < 100*time.Millisecond; {
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-done
+	}
+	select {}
+}
+
 func LockedDeadlock() {
 	runtime.LockOSThread()
 	select {}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+func init() {
+	register("StallWatchdog", StallWatchdog)
+	register("StallWatchdogDump", StallWatchdogDump)
+}
+
+// stall keeps the only P busy, without preemption points, while
+// another goroutine is ready to run. It must be run with
+// GODEBUG=asyncpreemptoff=1.
+func stall() {
+	runtime.GOMAXPROCS(1)
+	done := make(chan bool)
+	go func() {
+		go func() {}()
+		stallSpin()
+		done <- true
+	}()
+	<-done
+}
+
+//go:noinline
+func stallSpin() {
+	for i := 0; i < 1e9; i++ {
+	}
+}
+
+func StallWatchdog() {
+	n := runtime.NumGoroutine()
+	called := make(chan time.Duration, 1)
+	debug.SetStallWatchdog(100*time.Millisecond, func(d time.Duration) {
+		select {
+		case called <- d:
+		default:
+		}
+	})
+	stall()
+	select {
+	case d := <-called:
+		if d < 100*time.Millisecond {
+			fmt.Printf("stall of %v reported, want at least 100ms\n", d)
+			return
+		}
+	case <-time.After(5 * time.Second):
+		fmt.Println("stall not reported")
+		return
+	}
+	debug.SetStallWatchdog(0, nil)
+
+	// Disabling the watchdog stops the goroutine calling f.
+	for i := 0; runtime.NumGoroutine() > n; i++ {
+		if i == 500 {
+			fmt.Printf("%d goroutines after disabling the watchdog, want %d\n", runtime.NumGoroutine(), n)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	fmt.Println("OK")
+}
+
+func StallWatchdogDump() {
+	debug.SetStallWatchdog(100*time.Millisecond, nil)
+	stall()
+	debug.SetStallWatchdog(0, nil)
+	fmt.Println("OK")
+}
@@ -0,0 +1,85 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !plan9,!windows
+
+package main
+
+/*
+#include <unistd.h>
+
+static void cgoStatsSleep(void) {
+	usleep(200000);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+func init() {
+	register("CgoStats", CgoStats)
+}
+
+func CgoStats() {
+	// The first ReadCgoStats starts timing cgo calls.
+	var s runtime.CgoStats
+	runtime.ReadCgoStats(&s)
+	if s.Time != 0 {
+		fmt.Printf("before timing: Time = %v, want 0\n", time.Duration(s.Time))
+		return
+	}
+
+	done := make(chan bool)
+	go func() {
+		C.cgoStatsSleep()
+		done <- true
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	runtime.ReadCgoStats(&s)
+	if s.InFlight != 1 {
+		fmt.Printf("during call: InFlight = %d, want 1\n", s.InFlight)
+		return
+	}
+	current := int64(0)
+	for _, t := range s.PerThread {
+		current += t.Current
+	}
+	if current < int64(50*time.Millisecond) {
+		fmt.Printf("during call: Current = %v, want at least 50ms\n", time.Duration(current))
+		return
+	}
+	<-done
+
+	runtime.ReadCgoStats(&s)
+	if s.InFlight != 0 {
+		fmt.Printf("after call: InFlight = %d, want 0\n", s.InFlight)
+		return
+	}
+	if s.Calls != runtime.NumCgoCall() {
+		fmt.Printf("Calls = %d, NumCgoCall = %d\n", s.Calls, runtime.NumCgoCall())
+		return
+	}
+	if s.Time < int64(150*time.Millisecond) {
+		fmt.Printf("after call: Time = %v, want at least 150ms\n", time.Duration(s.Time))
+		return
+	}
+	calls := int64(0)
+	for _, t := range s.PerThread {
+		calls += t.Calls
+		if t.Current != 0 {
+			fmt.Printf("after call: thread %d has Current = %d\n", t.ID, t.Current)
+			return
+		}
+	}
+	if calls != s.Calls {
+		fmt.Printf("per-thread calls sum to %d, want %d\n", calls, s.Calls)
+		return
+	}
+	fmt.Println("OK")
+}
@@ -258,6 +258,7 @@ func addtimer(t *timer) {
 	lock(&pp.timersLock)
 	cleantimers(pp)
 	doaddtimer(pp, t)
+	atomic.Xadd64(&pp.timersStarted, 1)
 	unlock(&pp.timersLock)
 
 	wakeNetPoller(when)
@@ -472,6 +473,7 @@ loop:
 		pp := getg().m.p.ptr()
 		lock(&pp.timersLock)
 		doaddtimer(pp, t)
+		atomic.Xadd64(&pp.timersStarted, 1)
 		unlock(&pp.timersLock)
 		if !atomic.Cas(&t.status, timerModifying, timerWaiting) {
 			badTimer()
@@ -848,6 +850,7 @@ func runOneTimer(pp *p, t *timer, now int64) {
 
 	unlock(&pp.timersLock)
 
+	atomic.Xadd64(&pp.timersFired, 1)
 	f(arg, seq)
 
 	lock(&pp.timersLock)
@@ -1041,6 +1044,102 @@ func timeSleepUntil() (int64, *p) {
 	return next, pret
 }
 
+// TimerStats describes the runtime timers, which implement the timers and
+// tickers of package time as well as network deadlines and sleeps.
+type TimerStats struct {
+	// Active is the number of timers waiting to fire.
+	Active int
+
+	// Periodic is the number of active timers that fire repeatedly,
+	// such as those of time.Ticker and time.Tick.
+	Periodic int
+
+	// Started is the cumulative count of timers started, and Fired
+	// the cumulative count of timer functions run. Sampling them
+	// at intervals gives creation and firing rates.
+	//
+	// A Periodic count that keeps growing usually indicates leaked
+	// tickers, for example from calling time.Tick in a loop.
+	Started uint64
+	Fired   uint64
+
+	// PerP holds the statistics of each P, indexed by P.
+	PerP []TimerPStats
+}
+
+// TimerPStats describes the timers on the heap of a single P.
+// Started and Fired count only the timers started and run by the
+// P while it had its current index.
+type TimerPStats struct {
+	Active   int
+	Periodic int
+	Started  uint64
+	Fired    uint64
+}
+
+// deadPTimerStats holds the Started and Fired counts of destroyed Ps.
+var deadPTimerStats struct {
+	started uint64
+	fired   uint64
+}
+
+// ReadTimerStats populates s with statistics about the runtime timers.
+// It reuses the PerP slice of s if it has enough capacity.
+//
+// Unlike ReadMemStats, ReadTimerStats does not stop the world, but it
+// briefly locks the timer heap of each P in turn to count the periodic
+// timers, so its cost grows with the number of active timers. The
+// counts of different Ps are therefore not taken at exactly the same
+// moment.
+func ReadTimerStats(s *TimerStats) {
+	if n := int(gomaxprocs); cap(s.PerP) < n {
+		s.PerP = make([]TimerPStats, 0, n)
+	}
+	s.PerP = s.PerP[:0]
+	s.Active = 0
+	s.Periodic = 0
+	s.Started = atomic.Load64(&deadPTimerStats.started)
+	s.Fired = atomic.Load64(&deadPTimerStats.fired)
+
+	// Prevent allp slice changes. This is like timeSleepUntil.
+	lock(&allpLock)
+	for _, pp := range allp {
+		if pp == nil {
+			// This can happen if procresize has grown
+			// allp but not yet created new Ps.
+			continue
+		}
+		var ps TimerPStats
+		lock(&pp.timersLock)
+		// The P counts the timers on its heap, including the
+		// deleted timers that it has not yet removed.
+		ps.Active = int(atomic.Load(&pp.numTimers)) - int(atomic.Load(&pp.deletedTimers))
+		if ps.Active < 0 {
+			ps.Active = 0
+		}
+		for _, t := range pp.timers {
+			if t.period == 0 {
+				continue
+			}
+			switch atomic.Load(&t.status) {
+			case timerWaiting, timerModifiedEarlier, timerModifiedLater,
+				timerModifying, timerMoving, timerRunning:
+				ps.Periodic++
+			}
+		}
+		ps.Started = atomic.Load64(&pp.timersStarted)
+		ps.Fired = atomic.Load64(&pp.timersFired)
+		unlock(&pp.timersLock)
+
+		s.Active += ps.Active
+		s.Periodic += ps.Periodic
+		s.Started += ps.Started
+		s.Fired += ps.Fired
+		s.PerP = append(s.PerP, ps)
+	}
+	unlock(&allpLock)
+}
+
 // Heap maintenance algorithms.
 // These algorithms check for slice index errors manually.
 // Slice index error can happen if the program is using racy
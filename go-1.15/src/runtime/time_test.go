@@ -13,6 +13,7 @@ import (
 	"reflect"
 	"runtime"
 	"testing"
+	"time"
 )
 
 func TestFakeTime(t *testing.T) {
@@ -91,3 +92,49 @@ func parseFakeTime(x []byte) ([]fakeTimeFrame, error) {
 	}
 	return frames, nil
 }
+
+func TestReadTimerStats(t *testing.T) {
+	var before runtime.TimerStats
+	runtime.ReadTimerStats(&before)
+
+	const n = 10
+	var tickers [n]*time.Ticker
+	for i := range tickers {
+		tickers[i] = time.NewTicker(time.Hour)
+	}
+	timer := time.NewTimer(time.Millisecond)
+	<-timer.C
+
+	var s runtime.TimerStats
+	runtime.ReadTimerStats(&s)
+	for _, tk := range tickers {
+		tk.Stop()
+	}
+
+	if got := s.Periodic - before.Periodic; got < n {
+		t.Errorf("Periodic grew by %d after starting %d tickers; want at least %d", got, n, n)
+	}
+	if got := s.Active - before.Active; got < n {
+		t.Errorf("Active grew by %d after starting %d tickers; want at least %d", got, n, n)
+	}
+	if s.Active < s.Periodic {
+		t.Errorf("Active = %d < Periodic = %d", s.Active, s.Periodic)
+	}
+	if got := s.Started - before.Started; got < n+1 {
+		t.Errorf("Started grew by %d after starting %d timers; want at least %d", got, n+1, n+1)
+	}
+	if s.Fired <= before.Fired {
+		t.Errorf("Fired = %d after a timer fired; want > %d", s.Fired, before.Fired)
+	}
+	if len(s.PerP) != runtime.GOMAXPROCS(0) {
+		t.Errorf("len(PerP) = %d; want GOMAXPROCS = %d", len(s.PerP), runtime.GOMAXPROCS(0))
+	}
+	var active, periodic int
+	for _, ps := range s.PerP {
+		active += ps.Active
+		periodic += ps.Periodic
+	}
+	if active != s.Active || periodic != s.Periodic {
+		t.Errorf("PerP sums to Active=%d Periodic=%d; want %d and %d", active, periodic, s.Active, s.Periodic)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+import (
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// A Counter is an int64 counter for values that are added to much more
+// often than they are read, such as metrics updated on hot paths. Rather
+// than contending on a single cache line, adders on different Ps update
+// separate shards, which Load sums.
+//
+// The zero value for a Counter is zero and ready to use.
+// A Counter must not be copied after first use.
+type Counter struct {
+	noCopy noCopy
+
+	shards unsafe.Pointer // *counterShards, allocated on first Add
+}
+
+type counterShards struct {
+	s    []counterShard
+	mask uint
+}
+
+type counterShard struct {
+	n int64 // first for 64-bit alignment (atomic)
+
+	// Prevents false sharing on widespread platforms with
+	// 128 mod (cache line size) = 0 .
+	pad [128 - 8]byte
+}
+
+// Add adds delta to c.
+func (c *Counter) Add(delta int64) {
+	s := (*counterShards)(atomic.LoadPointer(&c.shards))
+	if s == nil {
+		s = c.init()
+	}
+	// The P only picks the shard, so it is fine if
+	// the goroutine moves to another P before adding.
+	pid := runtime_procPin()
+	runtime_procUnpin()
+	atomic.AddInt64(&s.s[uint(pid)&s.mask].n, delta)
+}
+
+// Load returns the value of c: the sum of the deltas of the calls of Add
+// that happened before Load. Calls of Add concurrent with Load may or may
+// not be included. Load takes time proportional to the number of shards,
+// which is GOMAXPROCS at the first call of Add, rounded up to a power of
+// two.
+func (c *Counter) Load() int64 {
+	s := (*counterShards)(atomic.LoadPointer(&c.shards))
+	if s == nil {
+		return 0
+	}
+	var sum int64
+	for i := range s.s {
+		sum += atomic.LoadInt64(&s.s[i].n)
+	}
+	return sum
+}
+
+// init allocates the shards of c, if no other Add has done so.
+func (c *Counter) init() *counterShards {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n *= 2
+	}
+	s := &counterShards{s: make([]counterShard, n), mask: uint(n - 1)}
+	if !atomic.CompareAndSwapPointer(&c.shards, nil, unsafe.Pointer(s)) {
+		s = (*counterShards)(atomic.LoadPointer(&c.shards))
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"runtime"
+	. "sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	var c Counter
+	if v := c.Load(); v != 0 {
+		t.Fatalf("zero Counter Load = %d, want 0", v)
+	}
+	c.Add(5)
+	c.Add(-2)
+	if v := c.Load(); v != 3 {
+		t.Fatalf("Load = %d, want 3", v)
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	var c Counter
+	const (
+		goroutines = 16
+		adds       = 10000
+	)
+	var wg WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < adds; i++ {
+				c.Add(int64(g))
+				if i%100 == 0 {
+					runtime.Gosched()
+				}
+			}
+		}(g)
+	}
+	// Raise GOMAXPROCS above the number of shards meanwhile.
+	runtime.GOMAXPROCS(8)
+	wg.Wait()
+	want := int64(adds * goroutines * (goroutines - 1) / 2)
+	if v := c.Load(); v != want {
+		t.Errorf("Load = %d, want %d", v, want)
+	}
+}
+
+func TestCounterAllocs(t *testing.T) {
+	var c Counter
+	c.Add(1)
+	if allocs := testing.AllocsPerRun(100, func() { c.Add(1) }); allocs != 0 {
+		t.Errorf("Add allocates %v times, want 0", allocs)
+	}
+}
+
+func BenchmarkCounterAdd(b *testing.B) {
+	var c Counter
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Add(1)
+		}
+	})
+}
+
+func BenchmarkCounterAddAtomic(b *testing.B) {
+	// For comparison with BenchmarkCounterAdd.
+	var n int64
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			atomic.AddInt64(&n, 1)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync
+
+// A Group deduplicates concurrent calls with the same key: while a call
+// for a key is in flight, other calls for that key wait for it and share
+// its result instead of repeating the work. This suits, for example,
+// filling a cache entry that many goroutines miss at once.
+// The zero value for a Group is ready to use.
+//
+// A Group must not be copied after first use.
+type Group struct {
+	mu Mutex            // protects m
+	m  map[string]*call // lazily initialized
+}
+
+// A call is an in-flight or completed call of a Group.
+type call struct {
+	wg WaitGroup
+
+	// These fields are written once before wg is done
+	// and only read after it.
+	val        interface{}
+	err        error
+	panicked   bool
+	panicValue interface{}
+
+	// These fields are protected by the Group's mu.
+	dups  int
+	chans []chan<- Result
+}
+
+// Result holds the results of Group.Do, so they can be passed on a channel.
+type Result struct {
+	Val    interface{}
+	Err    error
+	Shared bool
+}
+
+// goexitError is returned to the callers sharing a call whose function
+// called runtime.Goexit.
+type goexitError struct{}
+
+func (goexitError) Error() string { return "sync: Group function called runtime.Goexit" }
+
+// Do executes and returns the results of fn, making sure that only one
+// execution is in flight for a given key at a time. If a duplicate call
+// comes in, the duplicate caller waits for the original to complete and
+// receives the same results. The return value shared reports whether v
+// was given to multiple callers.
+//
+// If fn panics, Do panics with the same value in every caller.
+func (g *Group) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+	if c, ok := g.m[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		if c.panicked {
+			panic(c.panicValue)
+		}
+		return c.val, c.err, true
+	}
+	c := new(call)
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	g.doCall(c, key, fn)
+	return c.val, c.err, c.dups > 0
+}
+
+// DoChan is like Do but returns a channel that will receive the results
+// when they are ready. The channel is buffered, so a caller that stops
+// waiting, for example because its context was canceled, does not block
+// the call; the other callers still receive the results.
+//
+// If fn panics, the panic cannot be passed on to the callers waiting on
+// channels, so the program crashes instead of leaving them blocked.
+func (g *Group) DoChan(key string, fn func() (interface{}, error)) <-chan Result {
+	ch := make(chan Result, 1)
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+	if c, ok := g.m[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.mu.Unlock()
+		return ch
+	}
+	c := &call{chans: []chan<- Result{ch}}
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	go g.doCall(c, key, fn)
+	return ch
+}
+
+// Forget tells the Group to forget about a key. Calls for the key made
+// after Forget execute fn again rather than waiting for an earlier call
+// in flight, which still completes and delivers its results to the
+// callers already waiting for it.
+func (g *Group) Forget(key string) {
+	g.mu.Lock()
+	delete(g.m, key)
+	g.mu.Unlock()
+}
+
+// doCall executes fn for the call c and delivers its results.
+func (g *Group) doCall(c *call, key string, fn func() (interface{}, error)) {
+	normalReturn := false
+	defer func() {
+		if !normalReturn {
+			// fn panicked or called runtime.Goexit.
+			if r := recover(); r != nil {
+				c.panicked = true
+				c.panicValue = r
+			} else {
+				c.err = goexitError{}
+			}
+		}
+
+		g.mu.Lock()
+		c.wg.Done()
+		if g.m[key] == c {
+			delete(g.m, key)
+		}
+		chans := c.chans
+		shared := c.dups > 0
+		g.mu.Unlock()
+
+		if c.panicked {
+			if len(chans) > 0 {
+				// Crash in a goroutine of our own, which
+				// cannot be recovered, rather than leave the
+				// callers on chans waiting forever.
+				go panic(c.panicValue)
+				select {}
+			}
+			panic(c.panicValue)
+		}
+		for _, ch := range chans {
+			ch <- Result{c.val, c.err, shared}
+		}
+	}()
+	c.val, c.err = fn()
+	normalReturn = true
+}
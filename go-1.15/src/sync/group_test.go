@@ -0,0 +1,187 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sync_test
+
+import (
+	"errors"
+	"runtime"
+	. "sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupDo(t *testing.T) {
+	var g Group
+	v, err, shared := g.Do("key", func() (interface{}, error) {
+		return "bar", nil
+	})
+	if v != "bar" || err != nil || shared {
+		t.Errorf("Do = %v, %v, %v; want bar, nil, false", v, err, shared)
+	}
+
+	someErr := errors.New("some error")
+	v, err, _ = g.Do("key", func() (interface{}, error) {
+		return nil, someErr
+	})
+	if v != nil || err != someErr {
+		t.Errorf("Do = %v, %v; want nil, %v", v, err, someErr)
+	}
+}
+
+func TestGroupDoDupSuppress(t *testing.T) {
+	var g Group
+	var calls int32
+	release := make(chan bool)
+	started := make(chan bool)
+	fn := func() (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return "result", nil
+	}
+
+	const n = 10
+	var wg WaitGroup
+	var sharedCount int32
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		g.Do("key", fn)
+	}()
+	<-started
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, shared := g.Do("key", fn)
+			if v != "result" || err != nil {
+				t.Errorf("Do = %v, %v; want result, nil", v, err)
+			}
+			if shared {
+				atomic.AddInt32(&sharedCount, 1)
+			}
+		}()
+	}
+	// Let the duplicates reach the Group; those that do not
+	// are harmless, as they start a call of their own.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := atomic.LoadInt32(&calls); got >= n {
+		t.Errorf("fn called %d times for %d concurrent callers", got, n+1)
+	}
+	if atomic.LoadInt32(&sharedCount) == 0 {
+		t.Errorf("no caller shared a result")
+	}
+}
+
+func TestGroupDoChan(t *testing.T) {
+	var g Group
+	release := make(chan bool)
+	ch1 := g.DoChan("key", func() (interface{}, error) {
+		<-release
+		return 1, nil
+	})
+	ch2 := g.DoChan("key", func() (interface{}, error) {
+		t.Error("duplicate call executed fn")
+		return 2, nil
+	})
+	close(release)
+	for _, ch := range []<-chan Result{ch1, ch2} {
+		res := <-ch
+		if res.Val != 1 || res.Err != nil || !res.Shared {
+			t.Errorf("DoChan result = %+v; want {1 <nil> true}", res)
+		}
+	}
+
+	// A caller that stops waiting does not block the call.
+	release = make(chan bool)
+	g.DoChan("abandoned", func() (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+	close(release)
+	v, _, _ := g.Do("other", func() (interface{}, error) { return "ok", nil })
+	if v != "ok" {
+		t.Errorf("Do = %v; want ok", v)
+	}
+}
+
+func TestGroupForget(t *testing.T) {
+	var g Group
+	release := make(chan bool)
+	ch1 := g.DoChan("key", func() (interface{}, error) {
+		<-release
+		return 1, nil
+	})
+	g.Forget("key")
+	v, _, shared := g.Do("key", func() (interface{}, error) {
+		return 2, nil
+	})
+	if v != 2 || shared {
+		t.Errorf("Do after Forget = %v, %v; want 2, false", v, shared)
+	}
+	close(release)
+	if res := <-ch1; res.Val != 1 {
+		t.Errorf("forgotten call result = %v; want 1", res.Val)
+	}
+}
+
+func TestGroupDoPanic(t *testing.T) {
+	var g Group
+	release := make(chan bool)
+	started := make(chan bool)
+	first, dup := make(chan interface{}), make(chan interface{})
+	go func() {
+		defer func() { first <- recover() }()
+		g.Do("key", func() (interface{}, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+	go func() {
+		defer func() { dup <- recover() }()
+		g.Do("key", func() (interface{}, error) {
+			return nil, nil
+		})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	if r := <-first; r != "boom" {
+		t.Errorf("caller recovered %v; want boom", r)
+	}
+	// The duplicate may have been too late to share the call.
+	if r := <-dup; r != "boom" && r != nil {
+		t.Errorf("duplicate caller recovered %v; want boom", r)
+	}
+
+	// The key is usable again.
+	v, _, _ := g.Do("key", func() (interface{}, error) { return "ok", nil })
+	if v != "ok" {
+		t.Errorf("Do after panic = %v; want ok", v)
+	}
+}
+
+func TestGroupDoGoexit(t *testing.T) {
+	var g Group
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		g.Do("key", func() (interface{}, error) {
+			runtime.Goexit()
+			return nil, nil
+		})
+		t.Error("Do returned after runtime.Goexit")
+	}()
+	<-done
+	v, _, _ := g.Do("key", func() (interface{}, error) { return "ok", nil })
+	if v != "ok" {
+		t.Errorf("Do after Goexit = %v; want ok", v)
+	}
+}
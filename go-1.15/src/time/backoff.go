@@ -0,0 +1,154 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"errors"
+	"sync"
+)
+
+// Backoff describes the intervals of a BackoffTicker.
+type Backoff struct {
+	// Base is the first interval. It must be greater than zero.
+	Base Duration
+
+	// Max caps the interval. If Max is zero or negative,
+	// the interval grows without bound.
+	Max Duration
+
+	// Factor is the growth of the interval after each tick.
+	// If Factor is less than or equal to 1, it defaults to 2.
+	Factor float64
+
+	// Jitter, in the range [0, 1], randomly shortens each interval
+	// by up to that fraction, so that many clients backing off at
+	// the same time do not tick in lockstep.
+	Jitter float64
+}
+
+// grow returns the interval that follows d.
+func (b *Backoff) grow(d Duration) Duration {
+	f := b.Factor
+	if f <= 1 {
+		f = 2
+	}
+	g := float64(d) * f
+	if g >= float64(maxDuration) || b.Max > 0 && g > float64(b.Max) {
+		if b.Max > 0 {
+			return b.Max
+		}
+		return maxDuration
+	}
+	return Duration(g)
+}
+
+// jitter returns d shortened randomly by up to b.Jitter of its length.
+// The random value r is uniformly distributed over [0, 1<<32).
+func (b *Backoff) jitter(d Duration, r uint32) Duration {
+	if b.Jitter <= 0 {
+		return d
+	}
+	j := b.Jitter
+	if j > 1 {
+		j = 1
+	}
+	return d - Duration(j*float64(r)/(1<<32)*float64(d))
+}
+
+// from runtime
+func fastrand() uint32
+
+// A BackoffTicker holds a channel that delivers ``ticks'' of a clock
+// at intervals that grow after each tick, as described by its Backoff.
+// It encapsulates the retry loop around Timer.Reset that otherwise has
+// to be written by hand. As with Ticker, ticks are dropped for slow
+// receivers.
+type BackoffTicker struct {
+	C <-chan Time // The channel on which the ticks are delivered.
+	c chan Time
+	r runtimeTimer
+
+	mu      sync.Mutex
+	b       Backoff
+	cur     Duration // interval until the next tick, before jitter
+	gen     uintptr  // incremented by Stop and Reset; the timer's seq
+	stopped bool
+}
+
+// NewBackoffTicker returns a new BackoffTicker containing a channel that
+// will send the time after b.Base, and then after intervals growing by
+// b.Factor up to b.Max. b.Base must be greater than zero; if not,
+// NewBackoffTicker will panic. Stop the ticker to release associated resources.
+func NewBackoffTicker(b Backoff) *BackoffTicker {
+	if b.Base <= 0 {
+		panic(errors.New("non-positive interval for NewBackoffTicker"))
+	}
+	c := make(chan Time, 1)
+	t := &BackoffTicker{
+		C:   c,
+		c:   c,
+		b:   b,
+		cur: b.Base,
+	}
+	t.r = runtimeTimer{
+		when: when(b.jitter(b.Base, fastrand())),
+		f:    goBackoffTick,
+		arg:  t,
+		seq:  t.gen,
+	}
+	// startTimer, unlike modTimer, orders the writes above before
+	// the timer function for the race detector.
+	startTimer(&t.r)
+	return t
+}
+
+// Stop turns off the ticker. After Stop, no more ticks will be sent
+// until Reset is called. Stop does not close the channel, to prevent
+// a concurrent goroutine reading from the channel from seeing an
+// erroneous "tick".
+func (t *BackoffTicker) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	t.gen++
+	stopTimer(&t.r)
+}
+
+// Reset returns the interval to Base and restarts the ticker, stopped
+// or not. The next tick will arrive after Base has elapsed. Reset is
+// typically called after the operation being retried has succeeded.
+func (t *BackoffTicker) Reset() {
+	if t.r.f == nil {
+		panic("time: Reset called on uninitialized BackoffTicker")
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = false
+	t.cur = t.b.Base
+	t.gen++
+	modTimer(&t.r, when(t.b.jitter(t.cur, fastrand())), 0, t.r.f, t.r.arg, t.gen)
+}
+
+// goBackoffTick is the timer function of BackoffTickers.
+// The timer is rearmed on a new goroutine, as in goScheduleTick.
+// seq is the generation of the ticker the timer was armed for.
+func goBackoffTick(arg interface{}, seq uintptr) {
+	go arg.(*BackoffTicker).tick(seq)
+}
+
+func (t *BackoffTicker) tick(gen uintptr) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || gen != t.gen {
+		// Stop or Reset was called after the timer fired.
+		return
+	}
+	select {
+	case t.c <- Now():
+	default:
+	}
+	t.cur = t.b.grow(t.cur)
+	resetTimer(&t.r, when(t.b.jitter(t.cur, fastrand())))
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time_test
+
+import (
+	"testing"
+	. "time"
+)
+
+var backoffGrowTests = []struct {
+	b    Backoff
+	d    Duration
+	want Duration
+}{
+	{Backoff{}, Second, 2 * Second},
+	{Backoff{Factor: 1.5}, 2 * Second, 3 * Second},
+	{Backoff{Factor: 0.5}, Second, 2 * Second},
+	{Backoff{Max: 5 * Second}, 4 * Second, 5 * Second},
+	{Backoff{Max: 5 * Second}, 5 * Second, 5 * Second},
+	{Backoff{}, 1 << 62, 1<<63 - 1},
+	{Backoff{Factor: 10}, 1 << 62, 1<<63 - 1},
+}
+
+func TestBackoffGrow(t *testing.T) {
+	for _, tt := range backoffGrowTests {
+		if got := BackoffGrow(tt.b, tt.d); got != tt.want {
+			t.Errorf("%+v.grow(%v) = %v; want %v", tt.b, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	b := Backoff{Jitter: 0.5}
+	if got := BackoffJitter(b, Second, 0); got != Second {
+		t.Errorf("jitter with r=0 = %v; want %v", got, Second)
+	}
+	if got := BackoffJitter(b, Second, 1<<31); got != 750*Millisecond {
+		t.Errorf("jitter with r=1<<31 = %v; want %v", got, 750*Millisecond)
+	}
+	if got := BackoffJitter(b, Second, 1<<32-1); got <= 500*Millisecond {
+		t.Errorf("jitter with r=1<<32-1 = %v; want > %v", got, 500*Millisecond)
+	}
+	if got := BackoffJitter(Backoff{}, Second, 1<<31); got != Second {
+		t.Errorf("jitter without Jitter = %v; want %v", got, Second)
+	}
+}
+
+func TestBackoffTicker(t *testing.T) {
+	base := 5 * Millisecond
+	ticker := NewBackoffTicker(Backoff{Base: base, Max: 4 * base})
+	defer ticker.Stop()
+	t0 := Now()
+	for i := 0; i < 4; i++ {
+		<-ticker.C
+	}
+	// The intervals are base, 2*base, 4*base, 4*base.
+	if dt, want := Since(t0), 11*base; dt < want {
+		t.Errorf("4 ticks took %v; want at least %v", dt, want)
+	}
+	ticker.Stop()
+	ticker.Reset()
+	<-ticker.C
+	ticker.Stop()
+	select {
+	case <-ticker.C:
+	default:
+	}
+	select {
+	case <-ticker.C:
+		t.Error("BackoffTicker did not shut down")
+	case <-After(10 * base):
+	}
+}
+
+// Test that a tick from before Stop and Reset does not undo the Reset.
+func TestBackoffTickerStaleTick(t *testing.T) {
+	ticker := NewBackoffTicker(Backoff{Base: Hour})
+	defer ticker.Stop()
+	if sent, cur := BackoffStaleTick(ticker); sent || cur != Hour {
+		t.Errorf("stale tick: sent = %v, interval = %v; want false, %v", sent, cur, Hour)
+	}
+}
+
+// Test that NewBackoffTicker panics when given a non-positive base.
+func TestNewBackoffTickerZeroBase(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil {
+			t.Errorf("NewBackoffTicker with zero Base should have panicked")
+		}
+	}()
+	NewBackoffTicker(Backoff{})
+}
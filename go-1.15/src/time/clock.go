@@ -0,0 +1,240 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"errors"
+	"sync"
+)
+
+// A Clock is a source of the current time and of timers.
+// Code that takes a Clock instead of calling Now, Sleep, NewTimer and
+// NewTicker directly can be tested with a ManualClock, without real sleeps.
+type Clock interface {
+	Now() Time
+	Sleep(d Duration)
+	NewTimer(d Duration) *Timer
+	NewTicker(d Duration) *Ticker
+}
+
+// SystemClock returns the Clock implemented by the package-level
+// functions Now, Sleep, NewTimer and NewTicker.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() Time                    { return Now() }
+func (systemClock) Sleep(d Duration)             { Sleep(d) }
+func (systemClock) NewTimer(d Duration) *Timer   { return NewTimer(d) }
+func (systemClock) NewTicker(d Duration) *Ticker { return NewTicker(d) }
+
+// A ManualClock is a Clock whose time only changes when Advance is called.
+// Timers and Tickers created by a ManualClock fire during Advance, in order
+// of their expiration times, with the clock set to each expiration time in
+// turn. Their Stop and Reset methods behave as for the system clock.
+//
+// A ManualClock is safe for concurrent use by multiple goroutines.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    Time
+	seq    uint64         // creation order, to break ties between timers
+	timers []*manualTimer // active timers, unordered
+}
+
+// manualTimer is a Timer or Ticker created by a ManualClock.
+type manualTimer struct {
+	c      *ManualClock
+	when   Time
+	period Duration
+	phase  Time // first expiration after the last reset, for resetPhase
+	seq    uint64
+	f      func(now Time) // called with c.mu held; must not block
+	active bool
+}
+
+// NewManualClock returns a ManualClock set to t.
+func NewManualClock(t Time) *ManualClock {
+	return &ManualClock{now: t}
+}
+
+// Now returns the current time of c.
+func (c *ManualClock) Now() Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the time of c forward by d, firing the timers that
+// expire on the way. Negative values of d are treated as zero.
+func (c *ManualClock) Advance(d Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now
+	if d > 0 {
+		end = end.Add(d)
+	}
+	for {
+		var next *manualTimer
+		for _, t := range c.timers {
+			if t.when.After(end) {
+				continue
+			}
+			if next == nil || t.when.Before(next.when) || t.when.Equal(next.when) && t.seq < next.seq {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		if next.when.After(c.now) {
+			c.now = next.when
+		}
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+		} else {
+			c.remove(next)
+		}
+		next.f(c.now)
+	}
+	c.now = end
+}
+
+// Timers returns the number of active Timers and Tickers of c,
+// including those of goroutines blocked in Sleep. Tests can use it
+// to wait for the code under test to start waiting before calling Advance.
+func (c *ManualClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Sleep blocks until c has been advanced by at least d.
+func (c *ManualClock) Sleep(d Duration) {
+	if d <= 0 {
+		return
+	}
+	<-c.NewTimer(d).C
+}
+
+// NewTimer creates a new Timer that will send the time of c on its
+// channel once c has been advanced by at least d.
+func (c *ManualClock) NewTimer(d Duration) *Timer {
+	ch := make(chan Time, 1)
+	t := &Timer{C: ch}
+	t.m = c.start(d, 0, func(now Time) {
+		select {
+		case ch <- now:
+		default:
+		}
+	})
+	return t
+}
+
+// NewTicker returns a new Ticker that sends the time of c on its channel
+// each time c has been advanced past another period d. The duration d
+// must be greater than zero; if not, NewTicker will panic.
+func (c *ManualClock) NewTicker(d Duration) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewTicker"))
+	}
+	ch := make(chan Time, 1)
+	t := &Ticker{C: ch, c: ch}
+	t.d = manualTicker{c.start(d, d, t.send)}
+	return t
+}
+
+// manualTicker is the driver of a Ticker created by ManualClock.NewTicker.
+type manualTicker struct {
+	m *manualTimer
+}
+
+func (d manualTicker) stop()                              { d.m.stop() }
+func (d manualTicker) reset(period Duration)              { d.m.reset(period, period) }
+func (d manualTicker) resetAt(next Time, period Duration) { d.m.resetAt(next, period) }
+func (d manualTicker) resetPhase(period Duration)         { d.m.resetPhase(period) }
+
+func (c *ManualClock) start(d, period Duration, f func(Time)) *manualTimer {
+	t := &manualTimer{c: c, f: f}
+	t.reset(d, period)
+	return t
+}
+
+// remove removes t from the active timers. c.mu must be held.
+func (c *ManualClock) remove(t *manualTimer) {
+	for i, ct := range c.timers {
+		if ct == t {
+			last := len(c.timers) - 1
+			c.timers[i] = c.timers[last]
+			c.timers[last] = nil
+			c.timers = c.timers[:last]
+			break
+		}
+	}
+	t.active = false
+}
+
+// stop deactivates t, reporting whether it was active.
+func (t *manualTimer) stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+	wasActive := t.active
+	if wasActive {
+		t.c.remove(t)
+	}
+	return wasActive
+}
+
+// reset (re)activates t to expire after d and then every period,
+// if period is positive. It reports whether t was active.
+func (t *manualTimer) reset(d, period Duration) bool {
+	c := t.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	return c.resetLocked(t, c.now.Add(d), period)
+}
+
+// resetAt is like reset, but sets the first expiration to next.
+func (t *manualTimer) resetAt(next Time, period Duration) bool {
+	c := t.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resetLocked(t, next, period)
+}
+
+// resetPhase changes the period of t, keeping its expirations on the
+// sequence t.phase plus a multiple of period.
+func (t *manualTimer) resetPhase(period Duration) bool {
+	c := t.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	phase := t.phase
+	next := phase
+	if !next.After(c.now) {
+		next = next.Add(period * (1 + c.now.Sub(next)/period))
+	}
+	wasActive := c.resetLocked(t, next, period)
+	t.phase = phase
+	return wasActive
+}
+
+// resetLocked implements resetAt. c.mu must be held.
+func (c *ManualClock) resetLocked(t *manualTimer, next Time, period Duration) bool {
+	wasActive := t.active
+	t.when = next
+	t.phase = next
+	t.period = period
+	c.seq++
+	t.seq = c.seq
+	if !wasActive {
+		t.active = true
+		c.timers = append(c.timers, t)
+	}
+	return wasActive
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time_test
+
+import (
+	"testing"
+	. "time"
+)
+
+var _ Clock = SystemClock()
+var _ Clock = (*ManualClock)(nil)
+
+func TestManualClockTimer(t *testing.T) {
+	start := Date(2020, 1, 1, 0, 0, 0, 0, UTC)
+	c := NewManualClock(start)
+	timer := c.NewTimer(Second)
+	c.Advance(999 * Millisecond)
+	select {
+	case <-timer.C:
+		t.Fatal("timer fired early")
+	default:
+	}
+	c.Advance(Millisecond)
+	select {
+	case got := <-timer.C:
+		if want := start.Add(Second); !got.Equal(want) {
+			t.Errorf("timer sent %v; want %v", got, want)
+		}
+	default:
+		t.Fatal("timer did not fire")
+	}
+	if timer.Stop() {
+		t.Error("Stop of expired timer returned true")
+	}
+	if timer.Reset(Second) {
+		t.Error("Reset of expired timer returned true")
+	}
+	if !timer.Stop() {
+		t.Error("Stop of active timer returned false")
+	}
+	c.Advance(Hour)
+	select {
+	case <-timer.C:
+		t.Error("stopped timer fired")
+	default:
+	}
+	if n := c.Timers(); n != 0 {
+		t.Errorf("Timers() = %d; want 0", n)
+	}
+}
+
+func TestManualClockTicker(t *testing.T) {
+	start := Date(2020, 1, 1, 0, 0, 0, 0, UTC)
+	c := NewManualClock(start)
+	ticker := c.NewTicker(Second)
+	for i := 1; i <= 3; i++ {
+		c.Advance(Second)
+		if got, want := <-ticker.C, start.Add(Duration(i)*Second); !got.Equal(want) {
+			t.Errorf("tick %d at %v; want %v", i, got, want)
+		}
+	}
+	// Advancing by several periods delivers one tick and drops the rest.
+	c.Advance(3 * Second)
+	if got, want := <-ticker.C, start.Add(4*Second); !got.Equal(want) {
+		t.Errorf("tick at %v; want %v", got, want)
+	}
+	if n := ticker.Missed(); n != 2 {
+		t.Errorf("Missed() = %d; want 2", n)
+	}
+	ticker.Reset(2 * Second)
+	c.Advance(Second)
+	select {
+	case <-ticker.C:
+		t.Error("reset ticker fired early")
+	default:
+	}
+	c.Advance(Second)
+	<-ticker.C
+	ticker.Stop()
+	c.Advance(Hour)
+	select {
+	case <-ticker.C:
+		t.Error("stopped ticker fired")
+	default:
+	}
+	if got, want := c.Now(), start.Add(Hour+8*Second); !got.Equal(want) {
+		t.Errorf("Now() = %v; want %v", got, want)
+	}
+}
+
+func TestManualClockOrder(t *testing.T) {
+	c := NewManualClock(Date(2020, 1, 1, 0, 0, 0, 0, UTC))
+	a := c.NewTimer(2 * Second)
+	b := c.NewTimer(Second)
+	c.Advance(Minute)
+	ta, tb := <-a.C, <-b.C
+	if !tb.Before(ta) {
+		t.Errorf("timers fired at %v and %v; want the shorter one first", ta, tb)
+	}
+}
+
+func TestManualClockSleep(t *testing.T) {
+	c := NewManualClock(Date(2020, 1, 1, 0, 0, 0, 0, UTC))
+	done := make(chan bool)
+	go func() {
+		c.Sleep(Second)
+		done <- true
+	}()
+	for c.Timers() == 0 {
+		Sleep(Millisecond)
+	}
+	c.Advance(Second)
+	<-done
+}
+
+func TestManualClockTickerResetPhase(t *testing.T) {
+	start := Date(2020, 1, 1, 0, 0, 0, 0, UTC)
+	c := NewManualClock(start)
+	ticker := c.NewTicker(10 * Second)
+	c.Advance(25 * Second)
+	<-ticker.C
+	// The ticks stay on the original phase, start+10s+k*15s.
+	ticker.ResetPhase(15 * Second)
+	c.Advance(14 * Second)
+	select {
+	case <-ticker.C:
+		t.Fatal("tick before the phase-preserving boundary")
+	default:
+	}
+	c.Advance(Second)
+	if got, want := <-ticker.C, start.Add(40*Second); !got.Equal(want) {
+		t.Errorf("first tick after ResetPhase at %v; want %v", got, want)
+	}
+	c.Advance(15 * Second)
+	if got, want := <-ticker.C, start.Add(55*Second); !got.Equal(want) {
+		t.Errorf("second tick after ResetPhase at %v; want %v", got, want)
+	}
+
+	next := start.Add(Hour)
+	ticker.ResetAt(next, Minute)
+	c.Advance(Hour)
+	if got := <-ticker.C; !got.Equal(next) {
+		t.Errorf("tick after ResetAt at %v; want %v", got, next)
+	}
+	c.Advance(Minute)
+	if got, want := <-ticker.C, next.Add(Minute); !got.Equal(want) {
+		t.Errorf("second tick after ResetAt at %v; want %v", got, want)
+	}
+	ticker.Stop()
+}
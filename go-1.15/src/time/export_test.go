@@ -41,6 +41,55 @@ var (
 	TzsetOffset            = tzsetOffset
 )
 
+func BackoffGrow(b Backoff, d Duration) Duration {
+	return b.grow(d)
+}
+
+func BackoffJitter(b Backoff, d Duration, r uint32) Duration {
+	return b.jitter(d, r)
+}
+
+// BackoffStaleTick runs the timer function of t as if the timer had
+// fired just before Stop and Reset were called, and reports whether
+// the late tick was sent and the interval it left.
+func BackoffStaleTick(t *BackoffTicker) (sent bool, cur Duration) {
+	t.mu.Lock()
+	gen := t.gen
+	t.mu.Unlock()
+	t.Stop()
+	t.Reset()
+	t.tick(gen)
+	select {
+	case <-t.C:
+		sent = true
+	default:
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return sent, t.cur
+}
+
+// SharedTickerPeriods returns the number of distinct periods
+// currently driving shared Tickers.
+func SharedTickerPeriods() int {
+	sharedTickers.mu.Lock()
+	defer sharedTickers.mu.Unlock()
+	return len(sharedTickers.m)
+}
+
+// SetTickerLeakCheck turns the GODEBUG=tickerleak=1 check on or off,
+// sending reports to report. It returns a function restoring the
+// previous settings.
+func SetTickerLeakCheck(on bool, report func(stack []uintptr)) (restore func()) {
+	oldOn, oldReport := tickerLeakCheck, tickerLeakReport
+	tickerLeakCheck, tickerLeakReport = on, report
+	return func() {
+		tickerLeakCheck, tickerLeakReport = oldOn, oldReport
+	}
+}
+
+var Godebug = godebug
+
 func LoadFromEmbeddedTZData(zone string) (string, error) {
 	return loadFromEmbeddedTZData(zone)
 }
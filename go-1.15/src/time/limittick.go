@@ -0,0 +1,153 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// NewTickerCount is like NewTicker, except that the Ticker stops itself
+// after delivering n ticks on its channel. Ticks dropped for slow receivers
+// are counted by Missed and do not count toward n. Once the Ticker has
+// stopped, the channel returned by Done is closed; the last tick may still
+// be buffered in C at that point.
+//
+// The duration d and the count n must be greater than zero; if not,
+// NewTickerCount will panic. Reset, ResetAt and ResetPhase must not be
+// called on the returned Ticker.
+func NewTickerCount(d Duration, n int) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewTickerCount"))
+	}
+	if n <= 0 {
+		panic(errors.New("non-positive count for NewTickerCount"))
+	}
+	return newLimitTicker(d, &limitTicker{left: int64(n)})
+}
+
+// NewTickerUntil is like NewTicker, except that the Ticker stops itself at
+// deadline. No tick is sent after deadline, and the channel returned by Done
+// is closed when it passes. If deadline has already passed, the Ticker
+// never ticks.
+//
+// The duration d must be greater than zero; if not, NewTickerUntil will
+// panic. Reset, ResetAt and ResetPhase must not be called on the returned
+// Ticker.
+func NewTickerUntil(d Duration, deadline Time) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewTickerUntil"))
+	}
+	l := &limitTicker{
+		left:     -1,
+		deadline: when(Until(deadline)),
+	}
+	t := newLimitTicker(d, l)
+	// Set stopper before starting it, as finish may run at once.
+	l.stopper = &Timer{
+		r: runtimeTimer{
+			when: l.deadline,
+			f:    goFunc,
+			arg:  l.finish,
+		},
+	}
+	startTimer(&l.stopper.r)
+	return t
+}
+
+// A limitTicker is the driver of a Ticker created by NewTickerCount or
+// NewTickerUntil.
+type limitTicker struct {
+	t        *Ticker
+	left     int64 // ticks still to be delivered, accessed atomically; negative if unlimited
+	deadline int64 // runtimeNano time after which no tick is sent; 0 if none
+
+	once    sync.Once
+	done    chan struct{}
+	stopper *Timer // stops the Ticker at deadline; nil if none
+}
+
+func newLimitTicker(d Duration, l *limitTicker) *Ticker {
+	l.done = make(chan struct{})
+	c := make(chan Time, 1)
+	t := &Ticker{
+		C: c,
+		c: c,
+		d: l,
+	}
+	l.t = t
+	t.r = runtimeTimer{
+		when:   when(d),
+		period: int64(d),
+		f:      sendLimitTick,
+		arg:    l,
+	}
+	t.setPhase(t.r.when, d)
+	startTimer(&t.r)
+	return t
+}
+
+// Done returns a channel that is closed when a Ticker created by
+// NewTickerCount or NewTickerUntil stops, either because it reached
+// its limit or because Stop was called. For other Tickers Done
+// returns nil.
+func (t *Ticker) Done() <-chan struct{} {
+	l, ok := t.d.(*limitTicker)
+	if !ok {
+		return nil
+	}
+	return l.done
+}
+
+// sendLimitTick is the timer function of Tickers with a limit.
+// The timer function must not block or stop its own timer,
+// so the Ticker is stopped by finish on a new goroutine.
+func sendLimitTick(arg interface{}, seq uintptr) {
+	l := arg.(*limitTicker)
+	if l.deadline != 0 && runtimeNano() > l.deadline {
+		return
+	}
+	if atomic.LoadInt64(&l.left) == 0 {
+		return
+	}
+	select {
+	case l.t.c <- Now():
+	default:
+		atomic.AddUint64(&l.t.missed, 1)
+		return
+	}
+	if atomic.LoadInt64(&l.left) > 0 && atomic.AddInt64(&l.left, -1) == 0 {
+		go l.finish()
+	}
+}
+
+// finish stops the Ticker and closes its Done channel. It may be called
+// repeatedly.
+func (l *limitTicker) finish() {
+	l.once.Do(func() {
+		stopTimer(&l.t.r)
+		if l.stopper != nil {
+			l.stopper.Stop()
+		}
+		close(l.done)
+	})
+}
+
+func (l *limitTicker) stop() {
+	l.finish()
+}
+
+func (l *limitTicker) reset(d Duration) {
+	panic("time: Reset called on self-stopping Ticker")
+}
+
+func (l *limitTicker) resetAt(next Time, period Duration) {
+	panic("time: ResetAt called on self-stopping Ticker")
+}
+
+func (l *limitTicker) resetPhase(d Duration) {
+	panic("time: ResetPhase called on self-stopping Ticker")
+}
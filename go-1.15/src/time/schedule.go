@@ -0,0 +1,199 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import "sync"
+
+// A Schedule describes a recurring set of activation times, in the
+// manner of a cron entry. Activations happen on the days selected by
+// Weekdays, at the times of day listed in Times and at every Period
+// starting from Offset after midnight. The two kinds of activation
+// may be combined; Next reports the earliest of them.
+//
+// All days and times of day are interpreted in Location.
+// A Schedule with neither Period nor Times set never activates.
+type Schedule struct {
+	// Period, if positive, activates the schedule every Period,
+	// aligned to Offset after midnight of each selected day.
+	// The last interval of a day is shorter when Period does
+	// not divide 24 hours evenly.
+	Period Duration
+
+	// Offset shifts the Period alignment away from midnight.
+	// It is reduced modulo Period.
+	Offset Duration
+
+	// Times lists additional activation times as offsets from
+	// midnight, in wall clock time. Offsets outside [0, 24h) are ignored.
+	Times []Duration
+
+	// Weekdays, if non-empty, restricts activations to the listed days.
+	Weekdays []Weekday
+
+	// Location is the time zone used to determine days and times of day.
+	// If nil, Local is used.
+	Location *Location
+}
+
+// Next returns the first activation of s strictly after t.
+// If s never activates, Next returns the zero Time.
+func (s *Schedule) Next(t Time) Time {
+	loc := s.Location
+	if loc == nil {
+		loc = Local
+	}
+	if s.Period <= 0 && len(s.Times) == 0 {
+		return Time{}
+	}
+	y, m, d := t.In(loc).Date()
+	// Any selected weekday appears within the next 8 days,
+	// counting the remainder of t's own day.
+	for i := 0; i <= 7; i++ {
+		day := Date(y, m, d+i, 0, 0, 0, 0, loc)
+		if !s.onDay(day.Weekday()) {
+			continue
+		}
+		if next, ok := s.nextOnDay(day, Date(y, m, d+i+1, 0, 0, 0, 0, loc), t); ok {
+			return next
+		}
+	}
+	return Time{}
+}
+
+// onDay reports whether s activates on weekday w.
+func (s *Schedule) onDay(w Weekday) bool {
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+	for _, sw := range s.Weekdays {
+		if sw == w {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOnDay returns the first activation of s in [day, end) that is
+// strictly after t.
+func (s *Schedule) nextOnDay(day, end, t Time) (next Time, ok bool) {
+	if s.Period > 0 {
+		off := s.Offset % s.Period
+		if off < 0 {
+			off += s.Period
+		}
+		first := day.Add(off)
+		next = first
+		if !next.After(t) {
+			// Skip the activations at or before t.
+			n := t.Sub(first)/s.Period + 1
+			next = first.Add(n * s.Period)
+		}
+		ok = next.Before(end)
+	}
+	y, m, d := day.Date()
+	for _, tod := range s.Times {
+		if tod < 0 || tod >= 24*Hour {
+			continue
+		}
+		h, min, sec := int(tod/Hour), int(tod/Minute%60), int(tod/Second%60)
+		c := Date(y, m, d, h, min, sec, int(tod%Second), day.Location())
+		if c.After(t) && c.Before(end) && (!ok || c.Before(next)) {
+			next, ok = c, true
+		}
+	}
+	return next, ok
+}
+
+// scheduleTicker is the driver of a Ticker created by NewScheduleTicker.
+type scheduleTicker struct {
+	t       *Ticker
+	mu      sync.Mutex
+	s       Schedule
+	next    Time // activation the timer is armed for
+	stopped bool
+}
+
+// NewScheduleTicker returns a new Ticker containing a channel that will
+// send the current time at each activation of s. As with NewTicker, ticks
+// are dropped for slow receivers and counted by Missed.
+// Later changes to s do not affect the Ticker. Stop the Ticker to release
+// associated resources. Reset must not be called on the returned Ticker.
+func NewScheduleTicker(s Schedule) *Ticker {
+	s.Times = append([]Duration(nil), s.Times...)
+	s.Weekdays = append([]Weekday(nil), s.Weekdays...)
+	c := make(chan Time, 1)
+	st := &scheduleTicker{s: s}
+	t := &Ticker{
+		C: c,
+		c: c,
+		d: st,
+	}
+	st.t = t
+	t.r = runtimeTimer{
+		f:   goScheduleTick,
+		arg: st,
+	}
+	st.mu.Lock()
+	st.arm()
+	st.mu.Unlock()
+	return t
+}
+
+// arm starts the timer for the next activation after the current time.
+// st.mu must be held.
+func (st *scheduleTicker) arm() {
+	now := Now()
+	// The timer runs on the monotonic clock, so it may fire slightly
+	// before the wall clock reaches the activation it was armed for.
+	// Never schedule that activation twice.
+	from := now
+	if from.Before(st.next) {
+		from = st.next
+	}
+	next := st.s.Next(from)
+	if next.IsZero() {
+		return
+	}
+	st.next = next
+	resetTimer(&st.t.r, when(next.Sub(now)))
+}
+
+// goScheduleTick is the timer function of schedule-driven Tickers.
+// Computing the next activation may need to load time zone data,
+// so it is done on a new goroutine rather than in the timer function.
+func goScheduleTick(arg interface{}, seq uintptr) {
+	go arg.(*scheduleTicker).tick()
+}
+
+func (st *scheduleTicker) tick() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.stopped {
+		return
+	}
+	st.t.send(Now())
+	st.arm()
+}
+
+func (st *scheduleTicker) stop() {
+	// Stop while holding the lock, so that a tick in progress
+	// does not restart the timer after stop returns.
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.stopped = true
+	stopTimer(&st.t.r)
+}
+
+func (st *scheduleTicker) reset(d Duration) {
+	panic("time: Reset called on Ticker created by NewScheduleTicker")
+}
+
+func (st *scheduleTicker) resetAt(next Time, period Duration) {
+	panic("time: ResetAt called on Ticker created by NewScheduleTicker")
+}
+
+func (st *scheduleTicker) resetPhase(d Duration) {
+	panic("time: ResetPhase called on Ticker created by NewScheduleTicker")
+}
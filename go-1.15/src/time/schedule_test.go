@@ -0,0 +1,118 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time_test
+
+import (
+	"testing"
+	. "time"
+)
+
+var scheduleTests = []struct {
+	name  string
+	s     Schedule
+	after Time
+	want  Time
+}{
+	{
+		"empty",
+		Schedule{},
+		Date(2020, 6, 1, 12, 0, 0, 0, UTC),
+		Time{},
+	},
+	{
+		"period",
+		Schedule{Period: 15 * Minute, Location: UTC},
+		Date(2020, 6, 1, 12, 7, 0, 0, UTC),
+		Date(2020, 6, 1, 12, 15, 0, 0, UTC),
+	},
+	{
+		"period on boundary",
+		Schedule{Period: 15 * Minute, Location: UTC},
+		Date(2020, 6, 1, 12, 15, 0, 0, UTC),
+		Date(2020, 6, 1, 12, 30, 0, 0, UTC),
+	},
+	{
+		"period with offset",
+		Schedule{Period: Hour, Offset: 5 * Minute, Location: UTC},
+		Date(2020, 6, 1, 12, 7, 0, 0, UTC),
+		Date(2020, 6, 1, 13, 5, 0, 0, UTC),
+	},
+	{
+		"period realigned at midnight",
+		Schedule{Period: 7 * Hour, Location: UTC},
+		Date(2020, 6, 1, 22, 0, 0, 0, UTC),
+		Date(2020, 6, 2, 0, 0, 0, 0, UTC),
+	},
+	{
+		"times",
+		Schedule{Times: []Duration{17 * Hour, 9*Hour + 30*Minute}, Location: UTC},
+		Date(2020, 6, 1, 12, 0, 0, 0, UTC),
+		Date(2020, 6, 1, 17, 0, 0, 0, UTC),
+	},
+	{
+		"times next day",
+		Schedule{Times: []Duration{17 * Hour, 9*Hour + 30*Minute}, Location: UTC},
+		Date(2020, 6, 1, 17, 0, 0, 0, UTC),
+		Date(2020, 6, 2, 9, 30, 0, 0, UTC),
+	},
+	{
+		"weekdays",
+		// 2020-06-05 is a Friday.
+		Schedule{Times: []Duration{9 * Hour}, Weekdays: []Weekday{Monday, Wednesday}, Location: UTC},
+		Date(2020, 6, 5, 8, 0, 0, 0, UTC),
+		Date(2020, 6, 8, 9, 0, 0, 0, UTC),
+	},
+	{
+		"period and weekdays",
+		Schedule{Period: 30 * Minute, Weekdays: []Weekday{Saturday}, Location: UTC},
+		Date(2020, 6, 5, 23, 50, 0, 0, UTC),
+		Date(2020, 6, 6, 0, 0, 0, 0, UTC),
+	},
+	{
+		"period and times",
+		Schedule{Period: Hour, Times: []Duration{12*Hour + 20*Minute}, Location: UTC},
+		Date(2020, 6, 1, 12, 10, 0, 0, UTC),
+		Date(2020, 6, 1, 12, 20, 0, 0, UTC),
+	},
+	{
+		"location",
+		Schedule{Times: []Duration{9 * Hour}, Location: FixedZone("UTC+3", 3*60*60)},
+		Date(2020, 6, 1, 5, 0, 0, 0, UTC),
+		Date(2020, 6, 1, 6, 0, 0, 0, UTC),
+	},
+}
+
+func TestScheduleNext(t *testing.T) {
+	for _, tt := range scheduleTests {
+		got := tt.s.Next(tt.after)
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%v) = %v; want %v", tt.name, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleTicker(t *testing.T) {
+	ticker := NewScheduleTicker(Schedule{Period: 10 * Millisecond, Location: UTC})
+	defer ticker.Stop()
+	prev := Now()
+	for i := 0; i < 3; i++ {
+		tick := <-ticker.C
+		if !tick.After(prev) {
+			t.Errorf("tick %d at %v, not after %v", i, tick, prev)
+		}
+		prev = tick
+	}
+	ticker.Stop()
+	Sleep(30 * Millisecond)
+	select {
+	case <-ticker.C:
+	default:
+	}
+	select {
+	case <-ticker.C:
+		t.Error("schedule Ticker did not shut down")
+	case <-After(30 * Millisecond):
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// NewSharedTicker is like NewTicker, except that all shared Tickers with the
+// same period are driven by a single runtime timer and tick at the same
+// instants. Processes holding thousands of tickers with identical periods,
+// such as per-connection keepalives, thereby avoid growing the runtime timer
+// heap with one entry per ticker. In exchange, the first tick of a shared
+// Ticker may arrive after less than d.
+//
+// The duration d must be greater than zero; if not, NewSharedTicker will panic.
+// Stop the ticker to release associated resources.
+func NewSharedTicker(d Duration) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewSharedTicker"))
+	}
+	c := make(chan Time, 1)
+	t := &Ticker{
+		C: c,
+		c: c,
+	}
+	sub := &sharedSub{t: t}
+	sub.join(d)
+	t.d = sub
+	return t
+}
+
+// A sharedTicker is the runtime timer behind all shared Tickers of one period.
+type sharedTicker struct {
+	period Duration
+	r      runtimeTimer
+
+	// state guards subs. Ticks are sent only while it is held, so that
+	// no tick is sent to a Ticker once leave has returned. The timer
+	// function sends ticks inline and must not block, so it never waits
+	// for state: if join or leave holds it, the timer function sets
+	// sharedPending, and the holder sends the tick as it releases state.
+	state uint32
+	subs  []*sharedSub
+}
+
+const (
+	sharedLocked  = 1 << iota // state is held
+	sharedPending             // a tick arrived while state was held
+)
+
+// A sharedSub is the subscription of one Ticker to a sharedTicker,
+// and the driver of that Ticker.
+type sharedSub struct {
+	s *sharedTicker // protected by sharedTickers.mu
+	t *Ticker
+	i int // index in s.subs, or -1 if not subscribed; protected by s.state
+}
+
+// sharedTickers maps each period to its sharedTicker.
+var sharedTickers struct {
+	mu sync.Mutex
+	m  map[Duration]*sharedTicker
+}
+
+// join subscribes sub to the sharedTicker for period d,
+// starting a new one if necessary. sub must not be subscribed.
+func (sub *sharedSub) join(d Duration) {
+	sharedTickers.mu.Lock()
+	defer sharedTickers.mu.Unlock()
+	s := sharedTickers.m[d]
+	start := s == nil
+	if start {
+		if sharedTickers.m == nil {
+			sharedTickers.m = make(map[Duration]*sharedTicker)
+		}
+		s = &sharedTicker{period: d}
+		s.r = runtimeTimer{
+			when:   when(d),
+			period: int64(d),
+			f:      sharedTick,
+			arg:    s,
+		}
+		sharedTickers.m[d] = s
+	}
+	sub.s = s
+	s.lock()
+	sub.i = len(s.subs)
+	s.subs = append(s.subs, sub)
+	s.unlock()
+	if start {
+		startTimer(&s.r)
+	}
+}
+
+// leave unsubscribes sub, stopping its sharedTicker if sub was the last
+// subscriber. The last subscriber takes the place of sub in s.subs.
+func (sub *sharedSub) leave() {
+	sharedTickers.mu.Lock()
+	defer sharedTickers.mu.Unlock()
+	s := sub.s
+	s.lock()
+	if sub.i < 0 {
+		s.unlock()
+		return
+	}
+	last := len(s.subs) - 1
+	s.subs[sub.i] = s.subs[last]
+	s.subs[sub.i].i = sub.i
+	s.subs[last] = nil
+	s.subs = s.subs[:last]
+	sub.i = -1
+	empty := last == 0
+	s.unlock()
+	if empty && sharedTickers.m[s.period] == s {
+		stopTimer(&s.r)
+		delete(sharedTickers.m, s.period)
+	}
+}
+
+// sharedTick is the timer function of sharedTickers. Like sendTime,
+// it sends the tick to each subscriber without blocking, unless join
+// or leave holds s.state; then the holder sends it.
+func sharedTick(arg interface{}, seq uintptr) {
+	s := arg.(*sharedTicker)
+	for {
+		old := atomic.LoadUint32(&s.state)
+		if old&sharedLocked != 0 {
+			if atomic.CompareAndSwapUint32(&s.state, old, old|sharedPending) {
+				return
+			}
+			continue
+		}
+		if atomic.CompareAndSwapUint32(&s.state, old, sharedLocked) {
+			s.send(Now())
+			s.unlock()
+			return
+		}
+	}
+}
+
+// lock acquires s.state. It is called by join and leave, which may wait.
+func (s *sharedTicker) lock() {
+	for !atomic.CompareAndSwapUint32(&s.state, 0, sharedLocked) {
+		runtime.Gosched()
+	}
+}
+
+// unlock releases s.state, first sending the tick that arrived while
+// it was held, if any.
+func (s *sharedTicker) unlock() {
+	for {
+		old := atomic.LoadUint32(&s.state)
+		if old&sharedPending != 0 {
+			if atomic.CompareAndSwapUint32(&s.state, old, sharedLocked) {
+				s.send(Now())
+			}
+			continue
+		}
+		if atomic.CompareAndSwapUint32(&s.state, old, 0) {
+			return
+		}
+	}
+}
+
+// send delivers one tick to every subscriber. s.state must be held.
+func (s *sharedTicker) send(now Time) {
+	for _, sub := range s.subs {
+		sub.t.send(now)
+	}
+}
+
+func (sub *sharedSub) stop() {
+	sub.leave()
+}
+
+// reset subscribes sub to the sharedTicker for the new period instead.
+func (sub *sharedSub) reset(d Duration) {
+	sub.leave()
+	sub.join(d)
+}
+
+func (sub *sharedSub) resetAt(next Time, period Duration) {
+	panic("time: ResetAt called on Ticker created by NewSharedTicker")
+}
+
+func (sub *sharedSub) resetPhase(d Duration) {
+	panic("time: ResetPhase called on Ticker created by NewSharedTicker")
+}
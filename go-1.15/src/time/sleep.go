@@ -42,6 +42,7 @@ func modTimer(t *runtimeTimer, when, period int64, f func(interface{}, uintptr),
 type Timer struct {
 	C <-chan Time
 	r runtimeTimer
+	m *manualTimer // non-nil for timers created by a ManualClock; r is unused
 }
 
 // Stop prevents the Timer from firing.
@@ -67,6 +68,9 @@ type Timer struct {
 // If the caller needs to know whether f is completed, it must coordinate
 // with f explicitly.
 func (t *Timer) Stop() bool {
+	if t.m != nil {
+		return t.m.stop()
+	}
 	if t.r.f == nil {
 		panic("time: Stop called on uninitialized Timer")
 	}
@@ -113,6 +117,9 @@ func NewTimer(d Duration) *Timer {
 // Reset should always be invoked on stopped or expired channels, as described above.
 // The return value exists to preserve compatibility with existing programs.
 func (t *Timer) Reset(d Duration) bool {
+	if t.m != nil {
+		return t.m.reset(d, 0)
+	}
 	if t.r.f == nil {
 		panic("time: Reset called on uninitialized Timer")
 	}
@@ -123,9 +130,8 @@ func (t *Timer) Reset(d Duration) bool {
 func sendTime(c interface{}, seq uintptr) {
 	// Non-blocking send of time on c.
 	// Used in NewTimer, it cannot block anyway (buffer).
-	// Used in NewTicker, dropping sends on the floor is
-	// the desired behavior when the reader gets behind,
-	// because the sends are periodic.
+	// Tickers use sendTick instead, which also counts
+	// the sends dropped when the reader gets behind.
 	select {
 	case c.(chan Time) <- Now():
 	default:
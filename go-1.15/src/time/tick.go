@@ -1,11 +1,33 @@
 package time
 
-import "errors"
+import (
+	"errors"
+	"sync/atomic"
+)
 
 // 一个Ticker持有一个通道，它每隔一段时间就发送一个时钟的“滴答声”。
 type Ticker struct {
-	C <-chan Time // 传输滴答声的通道。
-	r runtimeTimer
+	// 以下字段原子访问，放在首位以保证64位对齐。
+	missed uint64 // 因通道已满而丢弃的滴答数。
+	phase  int64  // 第一个滴答的runtimeNano时间，ResetPhase据此保持相位。
+	origin int64  // 当前周期下第一个滴答的runtimeNano时间，TickEvent的序号从这里开始计数。
+	period int64  // 当前周期，与origin一起用于计算TickEvent的序号和名义时间。
+
+	C      <-chan Time      // 传输滴答声的通道。
+	c      chan Time        // C的发送端，由sendTick使用。
+	Events <-chan TickEvent // 由NewEventTicker创建时代替C传输滴答，否则为nil。
+	events chan TickEvent
+	r      runtimeTimer // 由运行时计时器直接驱动时使用。
+	d      tickerDriver // 驱动滴答并实现Stop和各Reset方法，由创建Ticker的函数设置；直接初始化的Ticker为nil。
+}
+
+// 一个tickerDriver驱动Ticker的滴答。每种创建Ticker的方式各有一个实现，Ticker的Stop、Reset、ResetAt和ResetPhase转发给它；
+// 不支持的操作由实现自己恐慌。参数已由Ticker的方法检查过。
+type tickerDriver interface {
+	stop()
+	reset(d Duration)
+	resetAt(next Time, period Duration)
+	resetPhase(d Duration)
 }
 
 // NewTicker返回一个包含通道的Ticker，该通道将发送带有duration参数指定的时间段的时间。它调整间隔或滴答，以弥补慢Ticker。持续时间d必须大于零;否则，NewTicker将会恐慌。停止Ticker以释放相关的资源。
@@ -17,31 +39,219 @@ func NewTicker(d Duration) *Ticker {
 	c := make(chan Time, 1)
 	t := &Ticker{
 		C: c,
-		r: runtimeTimer{
-			when:   when(d),
-			period: int64(d),
-			f:      sendTime,
-			arg:    c,
-		},
+		c: c,
+	}
+	t.r = runtimeTimer{
+		when:   when(d),
+		period: int64(d),
+		f:      sendTick,
+		arg:    t,
+	}
+	t.d = timerTicker{t}
+	t.setPhase(t.r.when, d)
+	startTimer(&t.r)
+	if tickerLeakCheck {
+		return newLeakCheckedTicker(t)
+	}
+	return t
+}
+
+// TickEvent描述一次滴答，由NewEventTicker创建的Ticker在Events上传输。
+type TickEvent struct {
+	// Seq是自创建Ticker或最近一次Reset、ResetAt、ResetPhase以来经过的周期数，从1开始。
+	// 这些调用之后序号重新从1开始；除此之外，相邻两次收到的Seq不连续，说明其间有滴答因接收方落后或运行时延迟而被丢弃。
+	Seq uint64
+
+	// Scheduled是这次滴答名义上的到期时间，Time是实际发送的时间。
+	// 两者之差反映的是计时器的延迟(漂移)，而不是丢失的滴答。
+	Scheduled Time
+	Time      Time
+}
+
+// NewEventTicker与NewTicker一样创建一个Ticker，但滴答以TickEvent值的形式在Events上传输(C为nil)，
+// 其中携带序号和名义到期时间，使接收方能够区分漂移和丢失，并准确计算经过的时间。
+// 持续时间d必须大于零;否则，NewEventTicker将会恐慌。停止Ticker以释放相关的资源。
+func NewEventTicker(d Duration) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for NewEventTicker"))
+	}
+	events := make(chan TickEvent, 1)
+	t := &Ticker{
+		Events: events,
+		events: events,
 	}
+	t.r = runtimeTimer{
+		when:   when(d),
+		period: int64(d),
+		f:      sendEventTick,
+		arg:    t,
+	}
+	t.d = timerTicker{t}
+	t.setPhase(t.r.when, d)
 	startTimer(&t.r)
 	return t
 }
 
+// setPhase记录第一个滴答的runtimeNano时间phase和周期d。
+func (t *Ticker) setPhase(phase int64, d Duration) {
+	atomic.StoreInt64(&t.phase, phase)
+	atomic.StoreInt64(&t.origin, phase)
+	atomic.StoreInt64(&t.period, int64(d))
+}
+
 // Stop停止a ticker. 停止后，将不再发送节拍。停止不关闭通道，以防止同时从通道读取goroutine看到一个错误的“滴答”。
 func (t *Ticker) Stop() {
-	stopTimer(&t.r)
+	if t.d == nil {
+		return
+	}
+	t.d.stop()
+}
+
+// StopDrain与Stop一样停止ticker，然后以非阻塞方式取出通道中已缓冲但尚未接收的滴答，报告是否有这样的滴答。
+// 返回后通道为空，之后可以直接调用Reset，而不会收到停止前留下的过时滴答。
+// 与Stop后手动排空一样，StopDrain不能与对通道的其他接收并发调用。
+func (t *Ticker) StopDrain() bool {
+	t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-t.Events:
+		return true
+	default:
+		return false
+	}
 }
 
 // Reset停止报价器并将其周期重置为指定的持续时间。下一个滴答将在新时期结束后到达。
 func (t *Ticker) Reset(d Duration) {
-	if t.r.f == nil {
+	if t.d == nil {
 		panic("time: Reset called on uninitialized Ticker")
 	}
-	modTimer(&t.r, when(d), int64(d), t.r.f, t.r.arg, t.r.seq)
+	t.d.reset(d)
+}
+
+// ResetAt停止ticker并重新安排它：下一个滴答在next时刻到达，此后每隔period到达一个。如果next已经过去，下一个滴答会立即到达。
+// 与Reset不同，滴答的相位由next决定，而不是由调用的时刻决定。period必须大于零;否则，ResetAt将会恐慌。
+func (t *Ticker) ResetAt(next Time, period Duration) {
+	if period <= 0 {
+		panic(errors.New("non-positive interval for ResetAt"))
+	}
+	if t.d == nil {
+		panic("time: ResetAt called on uninitialized Ticker")
+	}
+	t.d.resetAt(next, period)
+}
+
+// ResetPhase与Reset一样将ticker的周期改为d，但保持原有的相位：之后的滴答落在第一个滴答(由创建ticker或最近一次Reset、ResetAt所安排)加上d的整数倍的时刻上，
+// 而不是从调用ResetPhase的时刻重新计时，因此周期性任务在改变周期后仍在原来的边界上触发。d必须大于零;否则，ResetPhase将会恐慌。
+func (t *Ticker) ResetPhase(d Duration) {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for ResetPhase"))
+	}
+	if t.d == nil {
+		panic("time: ResetPhase called on uninitialized Ticker")
+	}
+	t.d.resetPhase(d)
+}
+
+// timerTicker是由NewTicker和NewEventTicker创建的Ticker的tickerDriver，直接使用Ticker的运行时计时器t.r。
+type timerTicker struct {
+	t *Ticker
+}
+
+func (d timerTicker) stop() {
+	stopTimer(&d.t.r)
+}
+
+func (d timerTicker) reset(period Duration) {
+	d.schedule(when(period), period)
+}
+
+func (d timerTicker) resetAt(next Time, period Duration) {
+	d.schedule(when(Until(next)), period)
+}
+
+func (d timerTicker) resetPhase(period Duration) {
+	t := d.t
+	// 序号从新周期下的第一个滴答重新计数。
+	next := phaseNext(atomic.LoadInt64(&t.phase), runtimeNano(), int64(period))
+	atomic.StoreInt64(&t.origin, next)
+	atomic.StoreInt64(&t.period, int64(period))
+	modTimer(&t.r, next, int64(period), t.r.f, t.r.arg, t.r.seq)
+}
+
+// schedule让第一个滴答在runtimeNano时间next到达，此后每隔period到达一个。
+func (d timerTicker) schedule(next int64, period Duration) {
+	t := d.t
+	t.setPhase(next, period)
+	modTimer(&t.r, next, int64(period), t.r.f, t.r.arg, t.r.seq)
+}
+
+// phaseNext返回序列phase+k*d(k>=0)中第一个晚于now的时刻。
+func phaseNext(phase, now, d int64) int64 {
+	if phase > now {
+		return phase
+	}
+	return phase + d*(1+(now-phase)/d)
+}
+
+// Missed返回自Ticker创建以来因接收方落后(通道中已有一个未读的滴答)而被丢弃的滴答总数。
+// 计数只增不减，调用方可以在每次接收后与上一次的值相减，得到两次接收之间丢失的滴答数，从而在定速循环中进行补偿。
+// Missed可以与Ticker的其他方法及对C的接收并发调用。
+func (t *Ticker) Missed() uint64 {
+	if l, ok := t.d.(*tickerLeak); ok {
+		t = l.t
+	}
+	return atomic.LoadUint64(&t.missed)
+}
+
+// sendTick是Ticker的计时器回调。它与sendTime一样以非阻塞方式发送当前时间，但在通道已满、滴答被丢弃时记录到t.missed中。
+func sendTick(arg interface{}, seq uintptr) {
+	arg.(*Ticker).send(Now())
+}
+
+// sendEventTick是由NewEventTicker创建的Ticker的计时器回调。
+func sendEventTick(arg interface{}, seq uintptr) {
+	arg.(*Ticker).sendDetailed()
+}
+
+// sendDetailed以非阻塞方式在t.events上发送一个TickEvent。名义到期时间取不晚于当前时间的最后一个周期边界origin+k*period，
+// 这与运行时对迟到的周期计时器只触发一次、跳过其余周期的做法一致。
+func (t *Ticker) sendDetailed() {
+	now := Now()
+	// 使用now自身的单调时钟读数，使得各次滴答的Scheduled之差恰好是周期的整数倍。
+	rnow := runtimeNano()
+	if now.wall&hasMonotonic != 0 {
+		rnow = now.ext + startNano
+	}
+	origin := atomic.LoadInt64(&t.origin)
+	period := atomic.LoadInt64(&t.period)
+	var k int64
+	if rnow > origin && period > 0 {
+		k = (rnow - origin) / period
+	}
+	tick := TickEvent{
+		Seq:       uint64(k) + 1,
+		Scheduled: now.Add(-Duration(rnow - (origin + k*period))),
+		Time:      now,
+	}
+	select {
+	case t.events <- tick:
+	default:
+		atomic.AddUint64(&t.missed, 1)
+	}
+}
+
+// send以非阻塞方式在t.c上发送now，如果通道已满则计入t.missed。
+func (t *Ticker) send(now Time) {
+	select {
+	case t.c <- now:
+	default:
+		atomic.AddUint64(&t.missed, 1)
+	}
 }
 
-// Tick是一个方便的包装NewTicker提供访问滴答通道。滴答是有用的客户端，没有必要关闭的报价机，请注意，没有办法关闭它，底层报价机无法恢复的垃圾收集器;它“泄漏”。与NewTicker不同，Tick在d <= 0时返回nil。
+// Tick是一个方便的包装NewTicker提供访问滴答通道。滴答是有用的客户端，没有必要关闭的报价机，请注意，没有办法关闭它，底层报价机无法恢复的垃圾收集器;它“泄漏”。设置GODEBUG=tickerleak=1可以在这样的Ticker被垃圾回收时报告创建它的调用栈。与NewTicker不同，Tick在d <= 0时返回nil。
 func Tick(d Duration) <-chan Time {
 	if d <= 0 {
 		return nil
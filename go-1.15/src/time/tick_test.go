@@ -6,7 +6,9 @@ package time_test
 
 import (
 	"fmt"
+	"os"
 	"runtime"
+	"strings"
 	"testing"
 	. "time"
 )
@@ -118,6 +120,31 @@ func TestNewTickerLtZeroDuration(t *testing.T) {
 	NewTicker(-1)
 }
 
+func TestTickerMissed(t *testing.T) {
+	ticker := NewTicker(Millisecond)
+	defer ticker.Stop()
+	// Don't receive for a while, so that the buffered tick blocks
+	// the ones that follow.
+	Sleep(50 * Millisecond)
+	if n := ticker.Missed(); n == 0 {
+		t.Errorf("Missed() = 0 after not receiving for 50 ticks; want > 0")
+	}
+	<-ticker.C
+	ticker.Stop()
+	before := ticker.Missed()
+	Sleep(10 * Millisecond)
+	if after := ticker.Missed(); after != before {
+		t.Errorf("Missed() changed from %d to %d after Stop", before, after)
+	}
+}
+
+func TestTickerMissedDirectInitialization(t *testing.T) {
+	tk := &Ticker{C: make(chan Time)}
+	if n := tk.Missed(); n != 0 {
+		t.Errorf("Missed() = %d; want 0", n)
+	}
+}
+
 func BenchmarkTicker(b *testing.B) {
 	benchmark(b, func(n int) {
 		ticker := NewTicker(Nanosecond)
@@ -148,3 +175,410 @@ func BenchmarkTickerResetNaive(b *testing.B) {
 		ticker.Stop()
 	})
 }
+
+func TestSharedTicker(t *testing.T) {
+	const n = 10
+	periods := SharedTickerPeriods()
+	var tickers [n]*Ticker
+	for i := range tickers {
+		tickers[i] = NewSharedTicker(10 * Millisecond)
+	}
+	if got := SharedTickerPeriods(); got != periods+1 {
+		t.Errorf("%d shared tickers of one period use %d timers; want 1", n, got-periods)
+	}
+	for i := 0; i < 2; i++ {
+		for _, tk := range tickers {
+			<-tk.C
+		}
+	}
+	tickers[0].Reset(20 * Millisecond)
+	<-tickers[0].C
+
+	// Stopping one ticker leaves the others, which take its place in
+	// the subscriber list, ticking.
+	tickers[3].StopDrain()
+	for i := 0; i < 2; i++ {
+		for j, tk := range tickers {
+			if j != 3 {
+				<-tk.C
+			}
+		}
+	}
+	select {
+	case <-tickers[3].C:
+		t.Errorf("tick received after Stop")
+	default:
+	}
+	for _, tk := range tickers {
+		tk.Stop()
+	}
+	if got := SharedTickerPeriods(); got != periods {
+		t.Errorf("after Stop, shared tickers use %d timers; want 0", got-periods)
+	}
+}
+
+func TestSharedTickerChurn(t *testing.T) {
+	// Tickers joining and leaving concurrently make the timer function
+	// leave ticks to join and leave to send; they must arrive in order.
+	tk := NewSharedTicker(Millisecond)
+	defer tk.Stop()
+	stop := make(chan bool)
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			NewSharedTicker(Millisecond).Stop()
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-done
+	}()
+	var last Time
+	for i := 0; i < 50; i++ {
+		now := <-tk.C
+		if now.Before(last) {
+			t.Fatalf("tick at %v arrived after tick at %v", now, last)
+		}
+		last = now
+	}
+}
+
+func BenchmarkSharedTicker(b *testing.B) {
+	benchmark(b, func(n int) {
+		ticker := NewSharedTicker(Nanosecond)
+		for i := 0; i < n; i++ {
+			<-ticker.C
+		}
+		ticker.Stop()
+	})
+}
+
+func TestTickerStopDrain(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		newTicker func(Duration) *Ticker
+	}{
+		{"NewTicker", NewTicker},
+		{"NewEventTicker", NewEventTicker},
+	} {
+		ticker := tt.newTicker(Millisecond)
+		Sleep(10 * Millisecond)
+		if !ticker.StopDrain() {
+			t.Errorf("%s: StopDrain reported no pending tick", tt.name)
+		}
+		select {
+		case <-ticker.C:
+			t.Errorf("%s: tick after StopDrain", tt.name)
+		case <-ticker.Events:
+			t.Errorf("%s: tick after StopDrain", tt.name)
+		case <-After(10 * Millisecond):
+		}
+		if ticker.StopDrain() {
+			t.Errorf("%s: second StopDrain reported a pending tick", tt.name)
+		}
+	}
+}
+
+func TestTickerGroup(t *testing.T) {
+	g := NewTickerGroup()
+	defer g.Stop()
+	fast := g.NewTicker(5 * Millisecond)
+	slow := g.NewTicker(20 * Millisecond)
+	never := g.NewTicker(Hour)
+	if n := g.Len(); n != 3 {
+		t.Errorf("Len() = %d; want 3", n)
+	}
+
+	t0 := Now()
+	fastTicks := 0
+	for fastTicks < 100 {
+		select {
+		case <-fast.C:
+			fastTicks++
+			continue
+		case <-slow.C:
+		case <-never.C:
+			t.Fatal("tick from Ticker with a period of an hour")
+		}
+		break
+	}
+	if dt := Since(t0); dt < 20*Millisecond-5*Millisecond {
+		t.Errorf("first tick of 20ms Ticker after %v", dt)
+	}
+
+	never.Stop()
+	if n := g.Len(); n != 2 {
+		t.Errorf("after Stop, Len() = %d; want 2", n)
+	}
+	never.Reset(Millisecond)
+	<-never.C
+	slow.ResetAt(Now().Add(-Second), Hour)
+	<-slow.C
+
+	g.Stop()
+	if n := g.Len(); n != 0 {
+		t.Errorf("after group Stop, Len() = %d; want 0", n)
+	}
+	Sleep(10 * Millisecond)
+	for _, tk := range []*Ticker{fast, slow, never} {
+		select {
+		case <-tk.C:
+		default:
+		}
+	}
+	select {
+	case <-fast.C:
+		t.Error("tick after group Stop")
+	case <-never.C:
+		t.Error("tick after group Stop")
+	case <-After(20 * Millisecond):
+	}
+	g.NewTicker(Millisecond).Reset(Millisecond)
+}
+
+func BenchmarkTickerGroup(b *testing.B) {
+	g := NewTickerGroup()
+	defer g.Stop()
+	benchmark(b, func(n int) {
+		ticker := g.NewTicker(Nanosecond)
+		for i := 0; i < n; i++ {
+			<-ticker.C
+		}
+		ticker.Stop()
+	})
+}
+
+func TestTickerResetAt(t *testing.T) {
+	ticker := NewTicker(Hour)
+	defer ticker.Stop()
+	next := Now().Add(20 * Millisecond)
+	ticker.ResetAt(next, 10*Millisecond)
+	if got := <-ticker.C; got.Before(next) {
+		t.Errorf("tick at %v; want no earlier than %v", got, next)
+	}
+	if got := <-ticker.C; got.Before(next.Add(10 * Millisecond)) {
+		t.Errorf("second tick at %v; want no earlier than %v", got, next.Add(10*Millisecond))
+	}
+}
+
+func TestTickerResetPhase(t *testing.T) {
+	delta := 20 * Millisecond
+	t0 := Now()
+	ticker := NewTicker(delta)
+	defer ticker.Stop()
+	<-ticker.C
+	ticker.ResetPhase(2 * delta)
+	// The next tick is due at t0+3*delta, not 2*delta after ResetPhase.
+	got := <-ticker.C
+	if dt := got.Sub(t0); dt < 3*delta {
+		t.Errorf("tick after ResetPhase at %v; want no earlier than %v", dt, 3*delta)
+	}
+}
+
+func TestEventTicker(t *testing.T) {
+	delta := 10 * Millisecond
+	ticker := NewEventTicker(delta)
+	defer ticker.Stop()
+	if ticker.C != nil {
+		t.Errorf("NewEventTicker set C")
+	}
+	first := <-ticker.Events
+	if first.Seq != 1 {
+		t.Errorf("first tick has Seq %d; want 1", first.Seq)
+	}
+	if first.Time.Before(first.Scheduled) {
+		t.Errorf("tick sent at %v, before its scheduled time %v", first.Time, first.Scheduled)
+	}
+	// Fall behind, so that some ticks are dropped.
+	Sleep(5 * delta)
+	<-ticker.Events
+	tick := <-ticker.Events
+	if tick.Seq <= first.Seq+2 {
+		t.Errorf("after falling behind, got tick %d; want a gap after tick %d", tick.Seq, first.Seq)
+	}
+	if d := tick.Scheduled.Sub(first.Scheduled); d != Duration(tick.Seq-first.Seq)*delta {
+		t.Errorf("ticks %d and %d scheduled %v apart; want %v", first.Seq, tick.Seq, d, Duration(tick.Seq-first.Seq)*delta)
+	}
+	if ticker.Missed() == 0 {
+		t.Errorf("Missed() = 0 after falling behind")
+	}
+}
+
+// Test that the Seq of an event Ticker restarts after ResetPhase,
+// rather than being counted in the new period from the first tick.
+func TestEventTickerResetPhase(t *testing.T) {
+	delta := 5 * Millisecond
+	ticker := NewEventTicker(delta)
+	defer ticker.Stop()
+	for i := 0; i < 3; i++ {
+		<-ticker.Events
+	}
+	ticker.StopDrain()
+	ticker.ResetPhase(10 * delta)
+	tick := <-ticker.Events
+	if late := tick.Time.Sub(tick.Scheduled); tick.Seq != 1 && late < 10*delta {
+		t.Errorf("first tick after ResetPhase has Seq %d, %v late; want 1", tick.Seq, late)
+	}
+}
+
+func TestTickerCount(t *testing.T) {
+	const n = 3
+	ticker := NewTickerCount(Millisecond, n)
+	defer ticker.Stop()
+	got := 0
+	for {
+		select {
+		case <-ticker.C:
+			got++
+			continue
+		case <-ticker.Done():
+		}
+		break
+	}
+	// The last tick may still be buffered when Done is closed.
+	select {
+	case <-ticker.C:
+		got++
+	default:
+	}
+	if got != n {
+		t.Errorf("received %d ticks; want %d", got, n)
+	}
+	select {
+	case <-ticker.C:
+		t.Errorf("tick received after Done")
+	case <-After(10 * Millisecond):
+	}
+}
+
+func TestTickerUntil(t *testing.T) {
+	delta := 5 * Millisecond
+	deadline := Now().Add(10 * delta)
+	ticker := NewTickerUntil(delta, deadline)
+	defer ticker.Stop()
+	got := 0
+	for {
+		select {
+		case tm := <-ticker.C:
+			if tm.After(deadline) {
+				t.Errorf("tick at %v, after deadline %v", tm, deadline)
+			}
+			got++
+			continue
+		case <-ticker.Done():
+		}
+		break
+	}
+	if Now().Before(deadline) {
+		t.Errorf("Done closed before deadline")
+	}
+	if got == 0 {
+		t.Errorf("no ticks before deadline")
+	}
+
+	past := NewTickerUntil(delta, Now().Add(-Second))
+	<-past.Done()
+	select {
+	case <-past.C:
+		t.Errorf("tick received from Ticker with past deadline")
+	case <-After(2 * delta):
+	}
+}
+
+func TestTickerStopDone(t *testing.T) {
+	ticker := NewTickerCount(Hour, 1)
+	ticker.Stop()
+	<-ticker.Done()
+	tk := NewTicker(Hour)
+	defer tk.Stop()
+	if tk.Done() != nil {
+		t.Errorf("Done of plain Ticker is not nil")
+	}
+}
+
+func TestGodebug(t *testing.T) {
+	defer os.Setenv("GODEBUG", os.Getenv("GODEBUG"))
+	for _, tt := range []struct {
+		env, key, want string
+	}{
+		{"", "tickerleak", ""},
+		{"tickerleak=1", "tickerleak", "1"},
+		{"gctrace=1,tickerleak=2", "tickerleak", "2"},
+		{"tickerleak=3,gctrace=1", "tickerleak", "3"},
+		{"xtickerleak=1,tickerleak", "tickerleak", ""},
+		{"gctrace=1,gctrace=1,tickerleak=1", "tickerleak", "1"},
+		{"tickerleak=1,tickerleak=0", "tickerleak", "0"},
+	} {
+		os.Setenv("GODEBUG", tt.env)
+		if got := Godebug(tt.key); got != tt.want {
+			t.Errorf("GODEBUG=%q: godebug(%q) = %q; want %q", tt.env, tt.key, got, tt.want)
+		}
+	}
+}
+
+// leakCreator returns the function that created a leaked Ticker and
+// reports whether the leak comes from TestTickerLeakCheck, as opposed
+// to Tickers leaked by other tests when run with GODEBUG=tickerleak=1.
+func leakCreator(stack []uintptr) (creator string, ours bool) {
+	frames := runtime.CallersFrames(stack)
+	for i := 0; ; i++ {
+		f, more := frames.Next()
+		if i == 0 {
+			creator = f.Function
+		}
+		if strings.HasPrefix(f.Function, "time_test.TestTickerLeakCheck") {
+			return creator, true
+		}
+		if !more {
+			return creator, false
+		}
+	}
+}
+
+func TestTickerLeakCheck(t *testing.T) {
+	leaks := make(chan string, 10)
+	restore := SetTickerLeakCheck(true, func(stack []uintptr) {
+		if creator, ours := leakCreator(stack); ours {
+			leaks <- creator
+		}
+	})
+	defer restore()
+
+	// A stopped Ticker is not reported.
+	func() {
+		ticker := NewTicker(Hour)
+		ticker.Reset(Hour)
+		ticker.Stop()
+	}()
+	// Tick leaks its Ticker.
+	c := Tick(Hour)
+	_ = c
+
+	timeout := After(10 * Second)
+	for {
+		runtime.GC()
+		select {
+		case creator := <-leaks:
+			if creator != "time.Tick" {
+				t.Errorf("leaked Ticker created by %s; want time.Tick", creator)
+			}
+			runtime.GC()
+			runtime.GC()
+			select {
+			case <-leaks:
+				t.Errorf("more than one leak reported")
+			case <-After(10 * Millisecond):
+			}
+			return
+		case <-timeout:
+			t.Fatal("leak not reported")
+		case <-After(Millisecond):
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// A TickerGroup drives many Tickers, each with its own period, from a single
+// goroutine and a single timer. It is meant for servers running periodic
+// work for tens of thousands of entities, where a runtime timer per Ticker
+// is too expensive. Tickers of a group are created by its NewTicker method
+// and support Stop, Reset, ResetAt and ResetPhase like any other Ticker.
+//
+// A TickerGroup must be created with NewTickerGroup.
+// Stop the group to release its goroutine.
+type TickerGroup struct {
+	mu      sync.Mutex
+	entries []*groupEntry // min-heap ordered by next
+	stopped bool
+	wake    chan struct{} // signals run that entries[0] changed or the group stopped
+}
+
+// A groupEntry is the driver of a Ticker driven by a TickerGroup.
+type groupEntry struct {
+	g      *TickerGroup
+	t      *Ticker
+	next   int64 // runtimeNano time of the next tick
+	phase  int64 // time of the first tick, for ResetPhase
+	period int64
+	index  int // position in g.entries, or -1 if not scheduled
+}
+
+// NewTickerGroup returns a new TickerGroup and starts its goroutine.
+func NewTickerGroup() *TickerGroup {
+	g := &TickerGroup{wake: make(chan struct{}, 1)}
+	go g.run()
+	return g
+}
+
+// NewTicker returns a new Ticker driven by g, sending the time on its
+// channel every d. As with the package-level NewTicker, ticks are dropped
+// for slow receivers and counted by Missed. If g has been stopped, the
+// Ticker never ticks.
+//
+// The duration d must be greater than zero; if not, NewTicker will panic.
+// Stop the Ticker to remove it from g.
+func (g *TickerGroup) NewTicker(d Duration) *Ticker {
+	if d <= 0 {
+		panic(errors.New("non-positive interval for TickerGroup.NewTicker"))
+	}
+	c := make(chan Time, 1)
+	t := &Ticker{
+		C: c,
+		c: c,
+	}
+	e := &groupEntry{g: g, t: t, index: -1}
+	t.d = e
+	g.schedule(e, when(d), d)
+	return t
+}
+
+func (e *groupEntry) stop() {
+	e.g.remove(e)
+}
+
+func (e *groupEntry) reset(d Duration) {
+	e.g.schedule(e, when(d), d)
+}
+
+func (e *groupEntry) resetAt(next Time, period Duration) {
+	e.g.schedule(e, when(Until(next)), period)
+}
+
+func (e *groupEntry) resetPhase(d Duration) {
+	e.g.resetPhase(e, d)
+}
+
+// Stop stops all Tickers of g and ends its goroutine.
+// Later calls to NewTicker and to Reset methods of the
+// group's Tickers have no effect.
+func (g *TickerGroup) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return
+	}
+	g.stopped = true
+	for _, e := range g.entries {
+		e.index = -1
+	}
+	g.entries = nil
+	g.poke()
+}
+
+// Len returns the number of running Tickers in g.
+func (g *TickerGroup) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.entries)
+}
+
+// schedule (re)starts e with its next tick at next and the given period.
+func (g *TickerGroup) schedule(e *groupEntry, next int64, period Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return
+	}
+	e.next, e.phase, e.period = next, next, int64(period)
+	g.fix(e)
+}
+
+// resetPhase changes the period of e to d, keeping its phase.
+func (g *TickerGroup) resetPhase(e *groupEntry, d Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		return
+	}
+	e.period = int64(d)
+	e.next = phaseNext(e.phase, runtimeNano(), e.period)
+	g.fix(e)
+}
+
+// remove stops e.
+func (g *TickerGroup) remove(e *groupEntry) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	i := e.index
+	if i < 0 {
+		return
+	}
+	last := len(g.entries) - 1
+	if i != last {
+		g.swap(i, last)
+	}
+	g.entries[last] = nil
+	g.entries = g.entries[:last]
+	e.index = -1
+	if i != last {
+		moved := g.entries[i]
+		g.up(i)
+		g.down(moved.index)
+	}
+}
+
+// fix moves e to its place in the heap, adding it if necessary.
+// g.mu must be held.
+func (g *TickerGroup) fix(e *groupEntry) {
+	if e.index < 0 {
+		e.index = len(g.entries)
+		g.entries = append(g.entries, e)
+	}
+	g.up(e.index)
+	g.down(e.index)
+	if e.index == 0 {
+		g.poke()
+	}
+}
+
+// poke wakes the goroutine of g without blocking.
+func (g *TickerGroup) poke() {
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run is the goroutine of g. It sends the ticks that are due,
+// then sleeps until the next one or until woken by poke.
+func (g *TickerGroup) run() {
+	var timer *Timer
+	for {
+		g.mu.Lock()
+		if g.stopped {
+			g.mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+		rnow := runtimeNano()
+		var now Time
+		sent := false
+		for len(g.entries) > 0 && g.entries[0].next <= rnow {
+			e := g.entries[0]
+			if !sent {
+				now, sent = Now(), true
+			}
+			e.t.send(now)
+			// Like the runtime, skip the periods that have already passed.
+			e.next = phaseNext(e.next, rnow, e.period)
+			g.down(0)
+		}
+		wait := Duration(-1)
+		if len(g.entries) > 0 {
+			wait = Duration(g.entries[0].next - rnow)
+		}
+		g.mu.Unlock()
+
+		if sent {
+			// Let the receivers run first. Otherwise, with a short
+			// period, the timer would wake this goroutine again in
+			// their place until the scheduler preempts it.
+			runtime.Gosched()
+		}
+		if wait < 0 {
+			<-g.wake
+			continue
+		}
+		if timer == nil {
+			timer = NewTimer(wait)
+		} else {
+			timer.Reset(wait)
+		}
+		select {
+		case <-timer.C:
+		case <-g.wake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		}
+	}
+}
+
+// Heap maintenance, as in package container/heap, which time cannot import.
+
+func (g *TickerGroup) swap(i, j int) {
+	g.entries[i], g.entries[j] = g.entries[j], g.entries[i]
+	g.entries[i].index = i
+	g.entries[j].index = j
+}
+
+func (g *TickerGroup) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2 // parent
+		if g.entries[i].next <= g.entries[j].next {
+			break
+		}
+		g.swap(i, j)
+		j = i
+	}
+}
+
+func (g *TickerGroup) down(i int) {
+	n := len(g.entries)
+	for {
+		j := 2*i + 1 // left child
+		if j >= n {
+			break
+		}
+		if j2 := j + 1; j2 < n && g.entries[j2].next < g.entries[j].next {
+			j = j2
+		}
+		if g.entries[i].next <= g.entries[j].next {
+			break
+		}
+		g.swap(i, j)
+		i = j
+	}
+}
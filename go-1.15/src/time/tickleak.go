@@ -0,0 +1,114 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package time
+
+import (
+	"internal/bytealg"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+)
+
+// Setting GODEBUG=tickerleak=1 makes the package report Tickers created by
+// NewTicker or Tick that become unreachable without being stopped. Such a
+// Ticker can never be stopped, so its runtime timer keeps firing for the
+// life of the process. The report is printed to standard error and includes
+// the stack that created the Ticker.
+//
+// The runtime timer refers to the Ticker it drives, so a running Ticker is
+// never unreachable. In this mode NewTicker therefore returns a handle that
+// forwards to the real Ticker, and the leak check is a finalizer on the handle.
+var tickerLeakCheck = godebug("tickerleak") == "1"
+
+// tickerLeakReport is called by the finalizer of a leaked Ticker
+// with its creation stack. Tests replace it.
+var tickerLeakReport = printTickerLeak
+
+// A tickerLeak is the driver of a Ticker handle created in leak check mode.
+// It forwards all operations to the real Ticker.
+type tickerLeak struct {
+	t       *Ticker   // the Ticker driven by the runtime timer
+	stack   []uintptr // where the handle was created
+	stopped uint32    // accessed atomically; nonzero if Stop was called last
+}
+
+// godebug returns the value of key in the GODEBUG environment variable.
+// As in the runtime, the last setting of key wins.
+func godebug(key string) string {
+	env, _ := syscall.Getenv("GODEBUG")
+	value := ""
+	for env != "" {
+		field := env
+		if i := bytealg.IndexByteString(env, ','); i >= 0 {
+			field, env = env[:i], env[i+1:]
+		} else {
+			env = ""
+		}
+		if len(field) > len(key) && field[:len(key)] == key && field[len(key)] == '=' {
+			value = field[len(key)+1:]
+		}
+	}
+	return value
+}
+
+// newLeakCheckedTicker returns a handle forwarding to t and arranges
+// for a report if the handle is collected while t is running.
+func newLeakCheckedTicker(t *Ticker) *Ticker {
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers, newLeakCheckedTicker and NewTicker.
+	n := runtime.Callers(3, pcs)
+	h := &Ticker{
+		C: t.C,
+		d: &tickerLeak{t: t, stack: pcs[:n]},
+	}
+	runtime.SetFinalizer(h, finalizeTicker)
+	return h
+}
+
+func finalizeTicker(h *Ticker) {
+	l := h.d.(*tickerLeak)
+	if atomic.LoadUint32(&l.stopped) == 0 {
+		tickerLeakReport(l.stack)
+	}
+}
+
+// setStopped records whether the Ticker behind the handle was last stopped
+// or restarted, and returns that Ticker.
+func (l *tickerLeak) setStopped(stopped bool) *Ticker {
+	v := uint32(0)
+	if stopped {
+		v = 1
+	}
+	atomic.StoreUint32(&l.stopped, v)
+	return l.t
+}
+
+func (l *tickerLeak) stop() {
+	l.setStopped(true).Stop()
+}
+
+func (l *tickerLeak) reset(d Duration) {
+	l.setStopped(false).Reset(d)
+}
+
+func (l *tickerLeak) resetAt(next Time, period Duration) {
+	l.setStopped(false).ResetAt(next, period)
+}
+
+func (l *tickerLeak) resetPhase(d Duration) {
+	l.setStopped(false).ResetPhase(d)
+}
+
+func printTickerLeak(stack []uintptr) {
+	print("time: Ticker garbage collected without Stop; created at:\n")
+	frames := runtime.CallersFrames(stack)
+	for {
+		f, more := frames.Next()
+		print("\t", f.Function, "\n\t\t", f.File, ":", f.Line, "\n")
+		if !more {
+			break
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wheel_test
+
+import (
+	"fmt"
+	"time"
+	"time/wheel"
+)
+
+func Example() {
+	// Idle timeouts need no more precision than a tenth of a second.
+	w := wheel.New(100 * time.Millisecond)
+	defer w.Stop()
+
+	closed := make(chan string)
+	idle := func(name string) *wheel.Timer {
+		return w.AfterFunc(300*time.Millisecond, func() { closed <- name })
+	}
+	a := idle("a")
+	idle("b")
+
+	// Activity on a connection pushes its deadline back.
+	a.Reset(time.Second)
+
+	fmt.Println("closed", <-closed)
+	fmt.Println("closed", <-closed)
+	// Output:
+	// closed b
+	// closed a
+}
@@ -0,0 +1,226 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package wheel implements a hierarchical timer wheel, an alternative to
+// the timers of package time for programs that manage very many coarse
+// timeouts, such as the idle timers of a server's connections.
+//
+// The timers of a Wheel expire on the ticks of a single clock and run
+// their functions on a single goroutine. Starting and stopping a timer
+// take constant time, however many timers are pending, and pending
+// timers cost the runtime nothing.
+package wheel
+
+import (
+	"sync"
+	"time"
+)
+
+// Each level of the wheel has 64 slots; a slot of level l spans 64^l
+// ticks. A timer is kept at the lowest level whose span covers its
+// expiry, and moves down a level when the clock reaches its slot.
+const (
+	slotBits  = 6
+	numSlots  = 1 << slotBits
+	numLevels = 8 // timers expire at most 64^8 - 1 ticks ahead
+	maxTicks  = 1<<(slotBits*numLevels) - 1
+)
+
+// A Wheel is a set of timers that expire on the ticks of a clock.
+type Wheel struct {
+	tick  time.Duration
+	start time.Time
+	clock func() time.Duration // time elapsed since start
+	stop  chan struct{}
+
+	mu     sync.Mutex
+	now    uint64 // number of ticks processed
+	levels [numLevels][numSlots]timerList
+}
+
+// A Timer runs a function once on the goroutine of its Wheel, unless it
+// is stopped first.
+type Timer struct {
+	w          *Wheel
+	f          func()
+	when       uint64     // tick at which the timer expires
+	list       *timerList // the slot holding the timer, or nil if not pending
+	prev, next *Timer
+}
+
+type timerList struct {
+	head *Timer
+}
+
+// New returns a wheel whose clock ticks every tick, and starts the
+// goroutine that runs the functions of its expired timers.
+// It panics if tick is not positive.
+func New(tick time.Duration) *Wheel {
+	if tick <= 0 {
+		panic("wheel: non-positive tick")
+	}
+	w := newWheel(tick)
+	go w.run(time.NewTicker(tick))
+	return w
+}
+
+func newWheel(tick time.Duration) *Wheel {
+	w := &Wheel{tick: tick, start: time.Now(), stop: make(chan struct{})}
+	w.clock = func() time.Duration { return time.Since(w.start) }
+	return w
+}
+
+// Stop stops the clock of w. Timers that have not expired never run.
+// Stop must be called at most once.
+func (w *Wheel) Stop() {
+	close(w.stop)
+}
+
+// AfterFunc waits for the duration d to elapse and then calls f on the
+// goroutine of w, which calls the functions of all expiring timers in
+// turn, so f should not block. Timers expire on the next tick after d
+// has elapsed, so f runs no sooner than d and at most about one tick
+// later.
+func (w *Wheel) AfterFunc(d time.Duration, f func()) *Timer {
+	t := &Timer{w: w, f: f}
+	w.mu.Lock()
+	w.add(t, d)
+	w.mu.Unlock()
+	return t
+}
+
+// Stop prevents t from running. It returns true if the call stops the
+// timer, false if the timer has already expired or been stopped.
+func (t *Timer) Stop() bool {
+	w := t.w
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if t.list == nil {
+		return false
+	}
+	t.list.remove(t)
+	return true
+}
+
+// Reset changes t to expire after duration d, whether or not it has
+// already expired or been stopped. It returns true if the timer had been
+// active, false if the timer had expired or been stopped.
+func (t *Timer) Reset(d time.Duration) bool {
+	w := t.w
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	active := t.list != nil
+	if active {
+		t.list.remove(t)
+	}
+	w.add(t, d)
+	return active
+}
+
+// add schedules t to expire on the first tick after d. w.mu must be held.
+func (w *Wheel) add(t *Timer, d time.Duration) {
+	// The clock may be ahead of the ticks processed so far,
+	// and partway through a tick.
+	e := w.clock()
+	when := uint64(e / w.tick)
+	if d > 0 {
+		ticks := uint64(d / w.tick)
+		if ticks > maxTicks {
+			ticks = maxTicks
+		}
+		// Round up the remainders of e and d.
+		if r := e%w.tick + d%w.tick; r > w.tick {
+			ticks += 2
+		} else if r > 0 {
+			ticks++
+		}
+		when += ticks
+	}
+	if when <= w.now {
+		when = w.now + 1
+	}
+	if when-w.now > maxTicks {
+		when = w.now + maxTicks
+	}
+	t.when = when
+	w.place(t)
+}
+
+// place adds t to the slot for its expiry. w.mu must be held.
+func (w *Wheel) place(t *Timer) {
+	delta := t.when - w.now
+	level := 0
+	for delta >= 1<<(slotBits*(level+1)) {
+		level++
+	}
+	slot := t.when >> (slotBits * level) & (numSlots - 1)
+	w.levels[level][slot].push(t)
+}
+
+// run advances the clock on the ticks of ticker until w is stopped.
+func (w *Wheel) run(ticker *time.Ticker) {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			// Catch up on ticks that were delayed or dropped.
+			for _, t := range w.advance(uint64(now.Sub(w.start) / w.tick)) {
+				t.f()
+			}
+		}
+	}
+}
+
+// advance processes the ticks up to target and returns the timers that
+// expired, in order of expiry.
+func (w *Wheel) advance(target uint64) []*Timer {
+	var expired []*Timer
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.now < target {
+		w.now++
+		// When a level wraps around, move the timers of the
+		// next slot of the level above down to the lower levels.
+		for level := 1; level < numLevels; level++ {
+			if w.now&(1<<(slotBits*level)-1) != 0 {
+				break
+			}
+			l := &w.levels[level][w.now>>(slotBits*level)&(numSlots-1)]
+			for t := l.head; t != nil; t = l.head {
+				l.remove(t)
+				w.place(t)
+			}
+		}
+		l := &w.levels[0][w.now&(numSlots-1)]
+		for t := l.head; t != nil; t = l.head {
+			l.remove(t)
+			expired = append(expired, t)
+		}
+	}
+	return expired
+}
+
+func (l *timerList) push(t *Timer) {
+	t.list = l
+	t.prev = nil
+	t.next = l.head
+	if l.head != nil {
+		l.head.prev = t
+	}
+	l.head = t
+}
+
+func (l *timerList) remove(t *Timer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		l.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+	t.list, t.prev, t.next = nil, nil, nil
+}
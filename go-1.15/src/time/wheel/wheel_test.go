@@ -0,0 +1,183 @@
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package wheel
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+// newTestWheel returns a wheel whose clock stands still
+// at the ticks it has processed.
+func newTestWheel(tick time.Duration) *Wheel {
+	w := newWheel(tick)
+	w.clock = func() time.Duration { return time.Duration(w.now) * w.tick }
+	return w
+}
+
+// fired advances w to target and returns the ticks at which the
+// expired timers were due, which must all be target.
+func fired(t *testing.T, w *Wheel, target uint64) int {
+	t.Helper()
+	n := 0
+	for _, tm := range w.advance(target) {
+		if tm.when != target {
+			t.Fatalf("timer due at tick %d fired at tick %d", tm.when, target)
+		}
+		n++
+	}
+	return n
+}
+
+func TestExpiry(t *testing.T) {
+	w := newTestWheel(time.Millisecond)
+	r := rand.New(rand.NewSource(1))
+	var due []uint64
+	for i := 0; i < 5000; i++ {
+		// Spread the timers over the first three levels.
+		ticks := uint64(1) << uint(r.Intn(3*slotBits))
+		ticks += uint64(r.Int63n(int64(ticks)))
+		w.AfterFunc(time.Duration(ticks)*time.Millisecond, nil)
+		due = append(due, ticks)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i] < due[j] })
+	i := 0
+	for tick := uint64(1); tick <= due[len(due)-1]; tick++ {
+		want := 0
+		for ; i < len(due) && due[i] == tick; i++ {
+			want++
+		}
+		if got := fired(t, w, tick); got != want {
+			t.Fatalf("tick %d: %d timers fired, want %d", tick, got, want)
+		}
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	w := newTestWheel(time.Second)
+	w.AfterFunc(3*time.Second, nil)
+	w.AfterFunc(100*time.Second, nil)
+	w.AfterFunc(5000*time.Second, nil)
+	expired := w.advance(6000)
+	if len(expired) != 3 {
+		t.Fatalf("%d timers fired, want 3", len(expired))
+	}
+	for i, want := range []uint64{3, 100, 5000} {
+		if expired[i].when != want {
+			t.Errorf("timer %d was due at tick %d, want %d", i, expired[i].when, want)
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	w := newTestWheel(10 * time.Millisecond)
+	for _, tt := range []struct {
+		d    time.Duration
+		when uint64
+	}{
+		{-time.Second, 1},
+		{0, 1},
+		{time.Millisecond, 1},
+		{10 * time.Millisecond, 1},
+		{11 * time.Millisecond, 2},
+		{time.Second, 100},
+		{1<<63 - 1, (1<<63-1)/10000000 + 1},
+	} {
+		if tm := w.AfterFunc(tt.d, nil); tm.when != tt.when {
+			t.Errorf("AfterFunc(%v) expires at tick %d, want %d", tt.d, tm.when, tt.when)
+		}
+	}
+
+	// Timers too far ahead for the wheel expire as late as it allows.
+	if tm := newTestWheel(1).AfterFunc(1<<63-1, nil); tm.when != maxTicks {
+		t.Errorf("AfterFunc(%v) on a 1ns wheel expires at tick %d, want %d", time.Duration(1<<63-1), tm.when, uint64(maxTicks))
+	}
+
+	// Partway through a tick, the timer must not expire
+	// before d has elapsed.
+	w.clock = func() time.Duration { return 15 * time.Millisecond }
+	for _, tt := range []struct {
+		d    time.Duration
+		when uint64
+	}{
+		{0, 1},
+		{time.Millisecond, 2},
+		{5 * time.Millisecond, 2},
+		{6 * time.Millisecond, 3},
+		{10 * time.Millisecond, 3},
+	} {
+		if tm := w.AfterFunc(tt.d, nil); tm.when != tt.when {
+			t.Errorf("at 15ms, AfterFunc(%v) expires at tick %d, want %d", tt.d, tm.when, tt.when)
+		}
+	}
+}
+
+func TestStopReset(t *testing.T) {
+	w := newTestWheel(time.Millisecond)
+	a := w.AfterFunc(10*time.Millisecond, nil)
+	b := w.AfterFunc(100*time.Millisecond, nil)
+	if !a.Stop() {
+		t.Error("Stop of pending timer returned false")
+	}
+	if a.Stop() {
+		t.Error("second Stop returned true")
+	}
+	if !b.Reset(20 * time.Millisecond) {
+		t.Error("Reset of pending timer returned false")
+	}
+	if n := fired(t, w, 19); n != 0 {
+		t.Fatalf("%d timers fired by tick 19, want 0", n)
+	}
+	if n := fired(t, w, 20); n != 1 {
+		t.Fatalf("%d timers fired at tick 20, want 1", n)
+	}
+	if b.Stop() {
+		t.Error("Stop of expired timer returned true")
+	}
+	if a.Reset(5 * time.Millisecond) {
+		t.Error("Reset of stopped timer returned true")
+	}
+	if n := fired(t, w, 25); n != 1 {
+		t.Fatalf("%d timers fired at tick 25, want 1", n)
+	}
+	if n := fired(t, w, 1000); n != 0 {
+		t.Fatalf("%d timers fired by tick 1000, want 0", n)
+	}
+}
+
+func TestWheel(t *testing.T) {
+	w := New(time.Millisecond)
+	defer w.Stop()
+	start := time.Now()
+	done := make(chan time.Duration, 1)
+	w.AfterFunc(20*time.Millisecond, func() { done <- time.Since(start) })
+	stopped := w.AfterFunc(10*time.Millisecond, func() { t.Error("stopped timer fired") })
+	stopped.Stop()
+	select {
+	case d := <-done:
+		if d < 20*time.Millisecond {
+			t.Errorf("timer fired after %v, want at least 20ms", d)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timer did not fire")
+	}
+}
+
+func BenchmarkAfterFuncStop(b *testing.B) {
+	w := newTestWheel(time.Millisecond)
+	for i := 0; i < b.N; i++ {
+		w.AfterFunc(time.Duration(i%100000)*time.Millisecond, nil).Stop()
+	}
+}
+
+func BenchmarkReset(b *testing.B) {
+	w := newTestWheel(time.Millisecond)
+	tm := w.AfterFunc(time.Second, nil)
+	for i := 0; i < b.N; i++ {
+		tm.Reset(time.Duration(i%100000) * time.Millisecond)
+	}
+}